@@ -49,7 +49,7 @@ func (r *Rope) BalanceWithConfig(config *BalanceConfig) *Rope {
 	builder := NewBuilder()
 	rebalanceNode(r.root, builder, config)
 	result, _ := builder.Build() // Build should not fail in this context
-	return result
+	return r.keepSettings(result)
 }
 
 // rebalanceNode recursively rebalances a node.
@@ -59,7 +59,7 @@ func rebalanceNode(node RopeNode, builder *RopeBuilder, config *BalanceConfig) {
 	}
 
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 		text := leaf.text
 
 		// Split large leaves into smaller chunks
@@ -153,16 +153,13 @@ func shouldMerge(left, right RopeNode, config *BalanceConfig) bool {
 		return false
 	}
 
-	leftLeaf := left.(*LeafNode)
-	rightLeaf := right.(*LeafNode)
-
-	totalSize := leftLeaf.Size() + rightLeaf.Size()
+	totalSize := left.Size() + right.Size()
 	return totalSize <= config.MaxLeafSize
 }
 
 // mergeLeaves merges two leaf nodes into one.
 func mergeLeaves(left, right *LeafNode) *LeafNode {
-	return &LeafNode{text: left.text + right.text}
+	return newLeafNode(left.text + right.text)
 }
 
 // shouldSplit returns true if a leaf should be split.
@@ -173,8 +170,8 @@ func shouldSplit(leaf *LeafNode, config *BalanceConfig) bool {
 // splitLeaf splits a leaf node at a character position.
 func splitLeaf(leaf *LeafNode, pos int) (*LeafNode, *LeafNode) {
 	runes := []rune(leaf.text)
-	left := &LeafNode{text: string(runes[:pos])}
-	right := &LeafNode{text: string(runes[pos:])}
+	left := newLeafNode(string(runes[:pos]))
+	right := newLeafNode(string(runes[pos:]))
 	return left, right
 }
 
@@ -192,14 +189,16 @@ func rebalanceTree(node RopeNode, config *BalanceConfig) RopeNode {
 
 	// Merge small adjacent leaves
 	if shouldMerge(internal.left, internal.right, config) {
-		leftLeaf := internal.left.(*LeafNode)
-		rightLeaf := internal.right.(*LeafNode)
+		leftLeaf := asLeafNode(internal.left)
+		rightLeaf := asLeafNode(internal.right)
 		return mergeLeaves(leftLeaf, rightLeaf)
 	}
 
 	// Update cached values
 	internal.length = internal.left.Length()
 	internal.size = internal.left.Size()
+	internal.lines = nodeLineBreaks(internal.left)
+	internal.ascii = nodeIsASCII(internal.left) && nodeIsASCII(internal.right)
 
 	return internal
 }
@@ -244,11 +243,10 @@ func collectStats(node RopeNode, depth int, stats *TreeStats) {
 	stats.NodeCount++
 
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
 		stats.LeafCount++
 		stats.Depth = max(stats.Depth, depth)
 
-		size := leaf.Size()
+		size := node.Size()
 		stats.MaxLeafSize += size
 
 		if stats.MinLeafSize == 0 || size < stats.MinLeafSize {
@@ -275,11 +273,7 @@ func (r *Rope) Compact() *Rope {
 	}
 
 	newRoot := rebuildOptimal(r.root, DefaultMinLeafSize, DefaultMaxLeafSize)
-	return &Rope{
-		root:   newRoot,
-		length: r.length,
-		size:   r.size,
-	}
+	return r.derive(newRoot, r.length, r.size)
 }
 
 // rebuildOptimal rebuilds a subtree with optimal node sizes.
@@ -309,7 +303,7 @@ func collectLeaves(node RopeNode) []*LeafNode {
 	}
 
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 		return []*LeafNode{leaf}
 	}
 
@@ -334,7 +328,7 @@ func mergeLeavesOptimal(leaves []*LeafNode, minSize, maxSize int) []*LeafNode {
 
 		if combinedSize <= maxSize {
 			// Merge
-			current = &LeafNode{text: current.text + leaves[i].text}
+			current = newLeafNode(current.text + leaves[i].text)
 		} else {
 			// Don't merge
 			merged = append(merged, current)
@@ -347,7 +341,7 @@ func mergeLeavesOptimal(leaves []*LeafNode, minSize, maxSize int) []*LeafNode {
 }
 
 // buildBalancedTree builds a balanced tree from a slice of leaves.
-func buildBalancedTree(leaves []*LeafNode, start, end int) RopeNode {
+func buildBalancedTree[L RopeNode](leaves []L, start, end int) RopeNode {
 	if start >= end {
 		return nil
 	}
@@ -367,12 +361,7 @@ func buildBalancedTree(leaves []*LeafNode, start, end int) RopeNode {
 		return left
 	}
 
-	return &InternalNode{
-		left:   left,
-		right:  right,
-		length: left.Length(),
-		size:   left.Size(),
-	}
+	return newInternalNode(left, right)
 }
 
 // ========== Validation ==========
@@ -1,6 +1,7 @@
 package rope
 
 import (
+	"sort"
 	"unicode/utf8"
 )
 
@@ -93,6 +94,137 @@ func (c *BytePosCache) Reset() {
 	c.charToByte = c.charToByte[:0]
 }
 
+// ========== Rope Byte Position Cache ==========
+
+// byteCacheInterval is the number of characters between checkpoints of a
+// rope's byte position cache.
+const byteCacheInterval = 256
+
+// byteCacheMaxInsert is the longest insertion, in characters, that a rope's
+// byte position cache survives. Inserted text gets no checkpoints, so after
+// a longer one the cache is dropped and rebuilt on the next lookup.
+const byteCacheMaxInsert = 8 * byteCacheInterval
+
+// ropeByteCache maps character positions to byte positions through sorted
+// checkpoints. It is never modified once built, so ropes can share it.
+type ropeByteCache struct {
+	chars []int // Character positions of the checkpoints, starting with 0
+	bytes []int // Byte positions of the checkpoints
+}
+
+// GetBytePosCached returns the byte position of charPos, like CharToByte,
+// using a cache of checkpoints kept with the rope. The cache is built on the
+// first call, and ropes produced from this one by Insert, Delete or Replace
+// inherit it with offsets past the edit adjusted, so repeated conversions
+// on a document being edited stay cheap.
+//
+// Example:
+//
+//	r := rope.New("héllo")
+//	fmt.Println(r.GetBytePosCached(2)) // 3
+func (r *Rope) GetBytePosCached(charPos int) int {
+	if r == nil || charPos <= 0 {
+		return 0
+	}
+	if charPos >= r.Length() {
+		return r.Size()
+	}
+
+	caches := r.lazyCaches()
+	caches.mu.Lock()
+	if caches.byteCache == nil {
+		caches.byteCache = buildRopeByteCache(r)
+	}
+	c := caches.byteCache
+	caches.mu.Unlock()
+
+	// Count the bytes from the nearest checkpoint by walking the leaves
+	i := sort.SearchInts(c.chars, charPos+1) - 1
+	w, start := leafWalkerAt(r, c.chars[i])
+	bytePos, skip, count := c.bytes[i], c.chars[i]-start, charPos-c.chars[i]
+	for count > 0 {
+		text, ok := w.next()
+		if !ok {
+			break
+		}
+		from, to := -1, len(text)
+		for j := range text {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if from < 0 {
+				from = j
+			}
+			if count == 0 {
+				to = j
+				break
+			}
+			count--
+		}
+		if from >= 0 {
+			bytePos += to - from
+		}
+	}
+	return bytePos
+}
+
+// buildRopeByteCache records a checkpoint every byteCacheInterval characters.
+func buildRopeByteCache(r *Rope) *ropeByteCache {
+	c := &ropeByteCache{
+		chars: make([]int, 0, r.Length()/byteCacheInterval+1),
+		bytes: make([]int, 0, r.Length()/byteCacheInterval+1),
+	}
+	charPos, base := 0, 0
+	forEachLeaf(r.root, func(text string) bool {
+		for i := range text {
+			if charPos%byteCacheInterval == 0 {
+				c.chars = append(c.chars, charPos)
+				c.bytes = append(c.bytes, base+i)
+			}
+			charPos++
+		}
+		base += len(text)
+		return true
+	})
+	if len(c.chars) == 0 {
+		c.chars, c.bytes = append(c.chars, 0), append(c.bytes, 0)
+	}
+	return c
+}
+
+// editedByteCache returns the byte position cache for the rope produced by
+// replacing removed characters at pos with inserted characters, changing
+// the size by sizeDelta bytes. It returns nil if r has no cache or the
+// insertion is too long to keep one.
+func (r *Rope) editedByteCache(pos, removed, inserted, sizeDelta int) *ropeByteCache {
+	caches := r.caches.Load()
+	if caches == nil || inserted > byteCacheMaxInsert {
+		return nil
+	}
+	caches.mu.Lock()
+	c := caches.byteCache
+	caches.mu.Unlock()
+	if c == nil {
+		return nil
+	}
+
+	// Checkpoints up to pos are unchanged, ones inside the removed text are
+	// gone, and the rest move by the edit's deltas
+	keep := sort.SearchInts(c.chars, pos+1)
+	shift := max(sort.SearchInts(c.chars, pos+removed), keep)
+
+	n := keep + len(c.chars) - shift
+	edited := &ropeByteCache{chars: make([]int, n), bytes: make([]int, n)}
+	copy(edited.chars, c.chars[:keep])
+	copy(edited.bytes, c.bytes[:keep])
+	for i := shift; i < len(c.chars); i++ {
+		edited.chars[keep+i-shift] = c.chars[i] + inserted - removed
+		edited.bytes[keep+i-shift] = c.bytes[i] + sizeDelta
+	}
+	return edited
+}
+
 // ========== Cached Leaf Node ==========
 
 // CachedLeaf is a leaf node with built-in byte position cache.
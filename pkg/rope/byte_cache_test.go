@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestByteCacheBug(t *testing.T) {
@@ -19,3 +20,63 @@ func TestByteCacheBug(t *testing.T) {
 	assert.True(t, result >= 0)
 	assert.True(t, result < len(text))
 }
+
+// TestGetBytePosCached_AfterEdits tests cached conversions on ropes derived by editing
+func TestGetBytePosCached_AfterEdits(t *testing.T) {
+	r := New(strings.Repeat("héllo wörld, 你好! ", 200))
+	for _, pos := range []int{0, 1, 255, 256, 257, 1000, r.Length() - 1, r.Length()} {
+		assert.Equal(t, r.CharToByte(pos), r.GetBytePosCached(pos), "pos %d", pos)
+	}
+	require.NotNil(t, r.caches.Load().byteCache)
+
+	edited, err := r.Insert(300, "ünïcødé")
+	require.NoError(t, err)
+	edited, err = edited.Delete(100, 700)
+	require.NoError(t, err)
+	edited, err = edited.Replace(50, 60, "x")
+	require.NoError(t, err)
+	require.NotNil(t, edited.caches.Load(), "small edits keep the cache")
+
+	for pos := 0; pos <= edited.Length(); pos += 37 {
+		assert.Equal(t, edited.CharToByte(pos), edited.GetBytePosCached(pos), "pos %d", pos)
+	}
+
+	// The original rope's cache is unaffected
+	for pos := 0; pos <= r.Length(); pos += 101 {
+		assert.Equal(t, r.CharToByte(pos), r.GetBytePosCached(pos), "pos %d", pos)
+	}
+
+	// A long insertion drops the cache, which is rebuilt on demand
+	long, err := edited.Insert(10, strings.Repeat("é", byteCacheMaxInsert+1))
+	require.NoError(t, err)
+	assert.Nil(t, long.caches.Load())
+	assert.Equal(t, long.CharToByte(3000), long.GetBytePosCached(3000))
+}
+
+// TestGetBytePosCached_MultiLeaf tests cached conversions across leaf boundaries
+func TestGetBytePosCached_MultiLeaf(t *testing.T) {
+	text := strings.Repeat("héllo 世界 🎉 ", 100)
+	for _, r := range []*Rope{chunkedRope(text, 7), chunkedRope(text, 300)} {
+		for pos := 0; pos <= r.Length(); pos++ {
+			assert.Equal(t, r.CharToByte(pos), r.GetBytePosCached(pos), "pos %d", pos)
+		}
+	}
+}
+
+// BenchmarkGetBytePosCached measures repeated conversions with the rope's cache
+func BenchmarkGetBytePosCached(b *testing.B) {
+	r := New(strings.Repeat("héllo wörld, 你好! ", 5000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.GetBytePosCached((i * 7919) % r.Length())
+	}
+}
+
+// BenchmarkGetBytePos_Rebuild measures conversions that rebuild the cache each time
+func BenchmarkGetBytePos_Rebuild(b *testing.B) {
+	r := New(strings.Repeat("héllo wörld, 你好! ", 5000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewBytePosCache(r.String()).GetBytePos((i * 7919) % r.Length())
+	}
+}
@@ -52,7 +52,7 @@ func (it *BytesIterator) loadLeafAtByte(bytePos int) {
 // findLeafForByte recursively finds the leaf containing the byte position.
 func (it *BytesIterator) findLeafForByte(node RopeNode, targetBytePos int, offset int) bool {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 		it.currentLeaf = leaf.text
 		it.leafBytePos = targetBytePos - offset
 		return true
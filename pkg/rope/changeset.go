@@ -1,11 +1,18 @@
 package rope
 
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
 // Operation represents a single edit operation for Rope's internal ChangeSet.
 // This is different from ot.Operation - this is Rope's internal representation.
 type Operation struct {
 	OpType OpType
 	Length int    // For Retain and Delete
-	Text   string // For Insert
+	Text   string // For Insert, and for Delete if the deleted text was captured
 }
 
 // OpType represents the type of operation.
@@ -17,6 +24,29 @@ const (
 	OpInsert               // Insert text
 )
 
+// String returns the string representation of OpType
+func (t OpType) String() string {
+	switch t {
+	case OpRetain:
+		return "retain"
+	case OpDelete:
+		return "delete"
+	case OpInsert:
+		return "insert"
+	default:
+		return "unknown"
+	}
+}
+
+// String returns a compact description of the operation for debugging,
+// such as `retain 5`, `delete 2` or `insert "abc"`.
+func (op Operation) String() string {
+	if op.OpType == OpInsert {
+		return fmt.Sprintf("%s %q", op.OpType, op.Text)
+	}
+	return fmt.Sprintf("%s %d", op.OpType, op.Length)
+}
+
 // ChangeSet represents a set of changes to transform one document state to another.
 // It is composable and invertible, making it ideal for undo/redo.
 //
@@ -49,6 +79,16 @@ func (cs *ChangeSet) Delete(n int) *ChangeSet {
 	return cs
 }
 
+// DeleteText deletes the characters of text, which must be the text at the
+// current position, and records it in the operation so that
+// InvertSelfContained can undo the deletion without the original rope.
+func (cs *ChangeSet) DeleteText(text string) *ChangeSet {
+	n := utf8.RuneCountInString(text)
+	cs.operations = append(cs.operations, Operation{OpType: OpDelete, Length: n, Text: text})
+	cs.lenAfter -= n
+	return cs
+}
+
 // Insert inserts text.
 func (cs *ChangeSet) Insert(text string) *ChangeSet {
 	cs.operations = append(cs.operations, Operation{OpType: OpInsert, Text: text})
@@ -71,6 +111,29 @@ func (cs *ChangeSet) IsEmpty() bool {
 	return len(cs.operations) == 0
 }
 
+// Operations returns a copy of the changeset's operations, in order.
+// Changing the returned slice does not affect the changeset.
+func (cs *ChangeSet) Operations() []Operation {
+	ops := make([]Operation, len(cs.operations))
+	copy(ops, cs.operations)
+	return ops
+}
+
+// OpCount returns the number of operations in the changeset.
+func (cs *ChangeSet) OpCount() int {
+	return len(cs.operations)
+}
+
+// String returns the operations of the changeset in a compact form for
+// debugging, such as `retain 5, delete 2, insert "abc"`.
+func (cs *ChangeSet) String() string {
+	parts := make([]string, len(cs.operations))
+	for i, op := range cs.operations {
+		parts[i] = op.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
 // finalize ensures the changeset covers the entire document by retaining
 // any remaining characters. This follows Helix's approach where changesets
 // must account for every character in the input document.
@@ -114,6 +177,7 @@ func (cs *ChangeSet) fuse() {
 				prev.Length += op.Length
 			case OpDelete:
 				prev.Length += op.Length
+				prev.Text += op.Text
 			case OpInsert:
 				prev.Text += op.Text
 			}
@@ -125,6 +189,164 @@ func (cs *ChangeSet) fuse() {
 	cs.operations = fused
 }
 
+// Normalize returns a canonical copy of the changeset. Two changesets that
+// describe the same edit normalize to the same operation list:
+//   - empty operations are dropped and adjacent operations of the same type
+//     are fused
+//   - between two retains, all deletions come before all insertions
+//   - a trailing retain is dropped, since it leaves the rest unchanged
+//
+// The receiver is not modified.
+func (cs *ChangeSet) Normalize() *ChangeSet {
+	result := NewChangeSet(cs.lenBefore)
+	result.lenAfter = cs.lenAfter
+
+	// Collect each run of non-retain operations as one delete and one insert
+	deleted := 0
+	var inserted strings.Builder
+	flush := func() {
+		if deleted > 0 {
+			result.operations = append(result.operations, Operation{OpType: OpDelete, Length: deleted})
+		}
+		if inserted.Len() > 0 {
+			result.operations = append(result.operations, Operation{OpType: OpInsert, Text: inserted.String()})
+		}
+		deleted = 0
+		inserted.Reset()
+	}
+
+	for _, op := range cs.operations {
+		switch op.OpType {
+		case OpRetain:
+			if op.Length == 0 {
+				continue
+			}
+			flush()
+			result.operations = append(result.operations, op)
+		case OpDelete:
+			deleted += op.Length
+		case OpInsert:
+			inserted.WriteString(op.Text)
+		}
+	}
+	flush()
+
+	result.fuse()
+	if n := len(result.operations); n > 0 && result.operations[n-1].OpType == OpRetain {
+		result.operations = result.operations[:n-1]
+	}
+	return result
+}
+
+// Equal reports whether two changesets describe the same edit, comparing
+// their normalized forms.
+func (cs *ChangeSet) Equal(other *ChangeSet) bool {
+	if cs == nil || other == nil {
+		return cs == other
+	}
+	if cs.lenBefore != other.lenBefore || cs.lenAfter != other.lenAfter {
+		return false
+	}
+
+	a, b := cs.Normalize().operations, other.Normalize().operations
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DebugValidateChangeSets makes Apply and Compose run Validate on their
+// inputs and fail fast on malformed changesets. It is off by default because
+// Apply tolerates trailing retains that run past the end of the document.
+// It is safe to toggle while other goroutines apply changesets.
+//
+// Example:
+//
+//	rope.DebugValidateChangeSets.Store(true)
+var DebugValidateChangeSets atomic.Bool
+
+// Validate checks the changeset's internal consistency:
+//   - operation lengths are not negative
+//   - retains and deletes together consume at most lenBefore characters
+//     (any remainder is implicitly retained)
+//   - lenBefore minus the deleted characters plus the inserted characters
+//     equals lenAfter
+//
+// Returns an *ErrInvalidInput describing the first problem found.
+func (cs *ChangeSet) Validate() error {
+	consumed, deleted, inserted := 0, 0, 0
+	for i, op := range cs.operations {
+		if op.Length < 0 {
+			return &ErrInvalidInput{
+				Parameter: fmt.Sprintf("operations[%d]", i),
+				Value:     op.Length,
+				Reason:    "length must not be negative",
+			}
+		}
+		switch op.OpType {
+		case OpRetain:
+			consumed += op.Length
+		case OpDelete:
+			consumed += op.Length
+			deleted += op.Length
+		case OpInsert:
+			inserted += utf8.RuneCountInString(op.Text)
+		}
+	}
+
+	if consumed > cs.lenBefore {
+		return &ErrInvalidInput{
+			Parameter: "operations",
+			Value:     consumed,
+			Reason:    fmt.Sprintf("retains and deletes exceed lenBefore %d", cs.lenBefore),
+		}
+	}
+	if want := cs.lenBefore - deleted + inserted; want != cs.lenAfter {
+		return &ErrInvalidInput{
+			Parameter: "lenAfter",
+			Value:     cs.lenAfter,
+			Reason:    fmt.Sprintf("operations produce length %d", want),
+		}
+	}
+	return nil
+}
+
+// Finish ends building the changeset: it checks that the retains and
+// deletes do not run past the end of the document, naming the first
+// operation that does, and retains whatever they leave uncovered.
+// Call it after the last Retain, Delete or Insert to catch an invalid
+// changeset where it is built rather than when it is applied.
+//
+// Example:
+//
+//	cs, err := rope.NewChangeSet(5).Retain(3).Delete(4).Finish()
+//	// err: invalid parameter operations[1]: ends at 7, past lenBefore 5 (delete 4)
+func (cs *ChangeSet) Finish() (*ChangeSet, error) {
+	consumed := 0
+	for i, op := range cs.operations {
+		if op.OpType == OpInsert {
+			continue
+		}
+		consumed += op.Length
+		if consumed > cs.lenBefore {
+			return nil, &ErrInvalidInput{
+				Parameter: fmt.Sprintf("operations[%d]", i),
+				Value:     op,
+				Reason:    fmt.Sprintf("ends at %d, past lenBefore %d", consumed, cs.lenBefore),
+			}
+		}
+	}
+	if err := cs.Validate(); err != nil {
+		return nil, err
+	}
+	return cs.finalize(), nil
+}
+
 // Apply applies the changeset to a rope and returns the modified rope.
 func (cs *ChangeSet) Apply(r *Rope) (*Rope, error) {
 	if r == nil || cs.IsEmpty() {
@@ -136,6 +358,11 @@ func (cs *ChangeSet) Apply(r *Rope) (*Rope, error) {
 		// Length mismatch - cannot apply
 		return r, ErrLengthMismatch
 	}
+	if DebugValidateChangeSets.Load() {
+		if err := cs.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	// Make a copy to finalize (don't modify original)
 	csCopy := NewChangeSet(cs.lenBefore)
@@ -176,6 +403,66 @@ func (cs *ChangeSet) Apply(r *Rope) (*Rope, error) {
 	return result, nil
 }
 
+// ApplyChecked applies the changeset to r with all-or-nothing semantics, for
+// changesets from untrusted sources such as remote collaborators. The
+// changeset must have been built for a document of r's length and pass
+// Validate, so that no retain or delete runs past the end of r; otherwise
+// an error is returned before any edit is made. r itself is never modified.
+//
+// Example:
+//
+//	doc, err := remote.ApplyChecked(doc)
+//	if err != nil {
+//	    return err // doc is unchanged
+//	}
+func (cs *ChangeSet) ApplyChecked(r *Rope) (*Rope, error) {
+	if r == nil {
+		r = Empty()
+	}
+	if r.Length() != cs.lenBefore {
+		return nil, &ErrInvalidInput{
+			Parameter: "lenBefore",
+			Value:     cs.lenBefore,
+			Reason:    fmt.Sprintf("document length is %d", r.Length()),
+		}
+	}
+	if err := cs.Validate(); err != nil {
+		return nil, err
+	}
+	return cs.Apply(r)
+}
+
+// ApplySequence applies changesets to r one after another, each to the
+// result of the previous one. Before applying a changeset its LenBefore is
+// checked against the current document length, and a mismatch is reported
+// with the index of the offending changeset instead of producing garbage.
+//
+// Example:
+//
+//	cs1 := rope.NewChangeSet(5).Retain(5).Insert("!")
+//	cs2 := rope.NewChangeSet(6).Insert(">").Retain(6)
+//	result, _ := rope.ApplySequence(rope.New("hello"), cs1, cs2)
+//	fmt.Println(result.String()) // ">hello!"
+func ApplySequence(r *Rope, sets ...*ChangeSet) (*Rope, error) {
+	result := r
+	for i, cs := range sets {
+		if cs.LenBefore() != result.Length() {
+			return nil, &ErrInvalidInput{
+				Parameter: fmt.Sprintf("sets[%d]", i),
+				Value:     cs.LenBefore(),
+				Reason:    fmt.Sprintf("lenBefore does not match document length %d", result.Length()),
+			}
+		}
+
+		var err error
+		result, err = cs.Apply(result)
+		if err != nil {
+			return nil, fmt.Errorf("applying sets[%d]: %w", i, err)
+		}
+	}
+	return result, nil
+}
+
 // Invert creates an inverted changeset that undoes this changeset.
 // The original rope state is needed to properly invert deletions.
 func (cs *ChangeSet) Invert(original *Rope) (*ChangeSet, error) {
@@ -213,6 +500,34 @@ func (cs *ChangeSet) Invert(original *Rope) (*ChangeSet, error) {
 	return inverted, nil
 }
 
+// InvertSelfContained returns a changeset that undoes this one using the
+// deleted text captured by DeleteText, so unlike Invert it does not need
+// the original rope, and an undo log can keep just the changesets.
+// Returns nil if a deletion did not capture its text.
+//
+// Example:
+//
+//	tx, _ := rope.SpliceTx(doc, 0, 5, "Howdy")
+//	undo := tx.Changes().InvertSelfContained()
+func (cs *ChangeSet) InvertSelfContained() *ChangeSet {
+	inverted := NewChangeSet(cs.lenAfter)
+	for _, op := range cs.operations {
+		switch op.OpType {
+		case OpRetain:
+			inverted.Retain(op.Length)
+		case OpDelete:
+			if utf8.RuneCountInString(op.Text) != op.Length {
+				return nil
+			}
+			inverted.Insert(op.Text)
+		case OpInsert:
+			inverted.Delete(utf8.RuneCountInString(op.Text))
+		}
+	}
+	inverted.fuse()
+	return inverted
+}
+
 // MapPosition maps a single position through this changeset with the given association.
 func (cs *ChangeSet) MapPosition(pos int, assoc Assoc) int {
 	mapper := NewPositionMapper(cs)
@@ -239,6 +554,8 @@ func (cs *ChangeSet) MapPositions(positions []int, associations []Assoc) []int {
 
 // Transform transforms this changeset to apply after another changeset.
 // This is used for operational transformation in concurrent editing.
+// Both changesets must start from the same document; where both insert at
+// the same position, other's text comes first. See Rebase.
 func (cs *ChangeSet) Transform(other *ChangeSet) *ChangeSet {
 	if other == nil || other.IsEmpty() {
 		result := NewChangeSet(cs.lenBefore)
@@ -247,21 +564,83 @@ func (cs *ChangeSet) Transform(other *ChangeSet) *ChangeSet {
 		result.lenAfter = cs.lenAfter
 		return result
 	}
+	if cs == nil {
+		return NewChangeSet(other.lenAfter)
+	}
+	return transformChangeSet(cs, other, false)
+}
 
-	if cs == nil || cs.IsEmpty() {
-		result := NewChangeSet(other.lenBefore)
-		result.operations = make([]Operation, len(other.operations))
-		copy(result.operations, other.operations)
-		result.lenAfter = other.lenAfter
-		return result
+// Rebase transforms a local changeset, made against a document that has
+// since been changed by onto, so that it applies after onto, as a client
+// does with its pending edits when changes arrive from the server. The
+// changes in onto are kept: where both insert at the same position, the
+// text of onto comes first.
+//
+// Applying onto and then the rebased changeset gives the same document as
+// applying the local changeset and then onto transformed over it with the
+// opposite priority, the OT convergence property.
+//
+// Example:
+//
+//	// Local "X" at 5 and remote "abc" at 0, both against "hello world"
+//	local := rope.NewChangeSet(11).Retain(5).Insert("X")
+//	remote := rope.NewChangeSet(11).Insert("abc")
+//	rebased := local.Rebase(remote) // retain 8, insert "X", retain 6
+func (cs *ChangeSet) Rebase(onto *ChangeSet) *ChangeSet {
+	return cs.Transform(onto)
+}
+
+// transformChangeSet transforms a to apply after b, which starts from the
+// same document. Where both insert at the same position, a's text comes
+// first if aFirst is set, and b's otherwise.
+func transformChangeSet(a, b *ChangeSet, aFirst bool) *ChangeSet {
+	ops := func(cs *ChangeSet) []Operation {
+		full := NewChangeSet(cs.lenBefore)
+		full.operations = append(full.operations, cs.operations...)
+		return full.finalize().operations
 	}
+	aOps, bOps := ops(a), ops(b)
 
-	// For now, use simple merge as placeholder
-	// A full OT-based transform would require more complex logic
-	result := NewChangeSet(cs.lenBefore)
-	result.operations = append(result.operations, cs.operations...)
-	result.lenAfter = cs.lenAfter
-	return result
+	result := NewChangeSet(b.lenAfter)
+	var opA, opB *Operation
+	for {
+		if opA == nil && len(aOps) > 0 {
+			opA, aOps = &aOps[0], aOps[1:]
+		}
+		if opB == nil && len(bOps) > 0 {
+			opB, bOps = &bOps[0], bOps[1:]
+		}
+
+		switch {
+		case opA != nil && opA.OpType == OpInsert && (aFirst || opB == nil || opB.OpType != OpInsert):
+			result.Insert(opA.Text)
+			opA = nil
+			continue
+		case opB != nil && opB.OpType == OpInsert:
+			result.Retain(utf8.RuneCountInString(opB.Text))
+			opB = nil
+			continue
+		case opA == nil || opB == nil:
+			// Both are done, or the changesets disagree on the document length
+			result.fuse()
+			return result
+		}
+
+		n := min(opA.Length, opB.Length)
+		switch {
+		case opA.OpType == OpRetain && opB.OpType == OpRetain:
+			result.Retain(n)
+		case opA.OpType == OpDelete && opB.OpType == OpRetain:
+			result.Delete(n)
+		}
+		// Text deleted by b needs no retain or delete from a
+		if opA.Length -= n; opA.Length == 0 {
+			opA = nil
+		}
+		if opB.Length -= n; opB.Length == 0 {
+			opB = nil
+		}
+	}
 }
 
 // ChangesIterator returns an iterator over the changeset's operations.
@@ -0,0 +1,558 @@
+package rope
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ========== Unified Diff ==========
+
+// Hunk is a single hunk of a unified diff.
+type Hunk struct {
+	OldStart int // Zero-based first line in the original document
+	OldLines int // Number of original lines covered by the hunk
+	NewStart int // Zero-based first line in the edited document
+	NewLines int // Number of edited lines covered by the hunk
+
+	// Lines holds the hunk body. Each entry starts with ' ' (context),
+	// '-' (removed) or '+' (added), followed by the line including its
+	// line ending. Only a final line without a newline lacks the ending.
+	Lines []string
+}
+
+// noNewlineMarker follows a diff line that has no trailing newline.
+const noNewlineMarker = "\\ No newline at end of file"
+
+// hunkHeader matches "@@ -l[,s] +l[,s] @@".
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// lineRegion is a run of changed lines, as half-open line index ranges in
+// the original and edited documents.
+type lineRegion struct {
+	oldLo, oldHi int
+	newLo, newHi int
+}
+
+// docLines splits text into lines that keep their line endings, and returns
+// the character offset of each line start plus a final entry for the end.
+func docLines(text string) ([]string, []int) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	starts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		starts[i+1] = starts[i] + utf8.RuneCountInString(line)
+	}
+	return lines, starts
+}
+
+// atLineStart reports whether pos is at the start of a line (or the start of text).
+func atLineStart(runes []rune, pos int) bool {
+	return pos == 0 || runes[pos-1] == '\n'
+}
+
+// ToUnifiedDiff renders the changeset as a unified diff against before,
+// with contextLines unchanged lines around each change. Nearby changes
+// share a hunk. A final line without a newline is followed by the usual
+// "\ No newline at end of file" marker.
+//
+// Returns an empty string if the changeset changes nothing or does not
+// apply to before.
+//
+// Example:
+//
+//	cs := rope.NewChangeSet(8).Retain(4).Delete(3).Insert("TWO")
+//	fmt.Print(cs.ToUnifiedDiff(rope.New("one\ntwo\n"), 3))
+//	// --- before
+//	// +++ after
+//	// @@ -1,2 +1,2 @@
+//	//  one
+//	// -two
+//	// +TWO
+func (cs *ChangeSet) ToUnifiedDiff(before *Rope, contextLines int) string {
+	if cs == nil || before == nil {
+		return ""
+	}
+	if contextLines < 0 {
+		contextLines = 0
+	}
+
+	after, err := cs.Apply(before)
+	if err != nil {
+		return ""
+	}
+
+	oldText, newText := before.String(), after.String()
+	oldLines, oldStarts := docLines(oldText)
+	newLines, newStarts := docLines(newText)
+
+	regions := changedLineRegions(cs, []rune(oldText), []rune(newText), oldStarts, newStarts)
+	if len(regions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- before\n+++ after\n")
+
+	for i := 0; i < len(regions); {
+		// Group regions whose context would touch or overlap
+		j := i + 1
+		for j < len(regions) && regions[j].oldLo-regions[j-1].oldHi <= 2*contextLines {
+			j++
+		}
+		group := regions[i:j]
+		i = j
+
+		first, last := group[0], group[len(group)-1]
+		oldStart := max(0, first.oldLo-contextLines)
+		oldEnd := min(len(oldLines), last.oldHi+contextLines)
+
+		h := Hunk{
+			OldStart: oldStart,
+			OldLines: oldEnd - oldStart,
+			NewStart: first.newLo - (first.oldLo - oldStart),
+		}
+		h.NewLines = last.newHi + (oldEnd - last.oldHi) - h.NewStart
+
+		ctx := oldStart
+		for _, reg := range group {
+			for ; ctx < reg.oldLo; ctx++ {
+				h.Lines = append(h.Lines, " "+oldLines[ctx])
+			}
+			for _, line := range oldLines[reg.oldLo:reg.oldHi] {
+				h.Lines = append(h.Lines, "-"+line)
+			}
+			for _, line := range newLines[reg.newLo:reg.newHi] {
+				h.Lines = append(h.Lines, "+"+line)
+			}
+			ctx = reg.oldHi
+		}
+		for ; ctx < oldEnd; ctx++ {
+			h.Lines = append(h.Lines, " "+oldLines[ctx])
+		}
+
+		writeHunk(&sb, h)
+	}
+
+	return sb.String()
+}
+
+// changedLineRegions converts the changeset's edits into runs of whole
+// changed lines. Edits that touch the same line are merged into one region.
+func changedLineRegions(cs *ChangeSet, oldRunes, newRunes []rune, oldStarts, newStarts []int) []lineRegion {
+	ops := cs.clone().finalize()
+	ops.fuse()
+
+	// Collect the changed character regions of both documents
+	type charRegion struct{ oldLo, oldHi, newLo, newHi int }
+	var edits []charRegion
+
+	oldPos, newPos := 0, 0
+	for i := 0; i < len(ops.operations); {
+		if ops.operations[i].OpType == OpRetain {
+			oldPos += ops.operations[i].Length
+			newPos += ops.operations[i].Length
+			i++
+			continue
+		}
+
+		edit := charRegion{oldLo: oldPos, newLo: newPos}
+		for ; i < len(ops.operations) && ops.operations[i].OpType != OpRetain; i++ {
+			if op := ops.operations[i]; op.OpType == OpDelete {
+				oldPos += op.Length
+			} else {
+				newPos += utf8.RuneCountInString(op.Text)
+			}
+		}
+		edit.oldHi, edit.newHi = oldPos, newPos
+		edits = append(edits, edit)
+	}
+
+	// lineEnd returns where the line containing the end of an edit ends in
+	// the original document. The text after an edit is unchanged, so the
+	// same distance applies to the edited document.
+	lineEnd := func(reg charRegion) int {
+		if atLineStart(oldRunes, reg.oldHi) && atLineStart(newRunes, reg.newHi) && (reg.oldHi > reg.oldLo || reg.newHi > reg.newLo) {
+			return reg.oldHi
+		}
+		end := reg.oldHi
+		for end < len(oldRunes) {
+			end++
+			if oldRunes[end-1] == '\n' {
+				break
+			}
+		}
+		return end
+	}
+
+	var lines []lineRegion
+	for i := 0; i < len(edits); {
+		reg := edits[i]
+
+		// Step back to the start of the line; that prefix is unchanged too
+		lo := reg.oldLo
+		for lo > 0 && oldRunes[lo-1] != '\n' {
+			lo--
+		}
+		reg.newLo -= reg.oldLo - lo
+		reg.oldLo = lo
+
+		// Absorb following edits that start on this region's last line
+		hi := lineEnd(reg)
+		for i++; i < len(edits) && (edits[i].oldLo < hi || !atLineStart(oldRunes, hi)); i++ {
+			reg.oldHi, reg.newHi = edits[i].oldHi, edits[i].newHi
+			hi = lineEnd(reg)
+		}
+		reg.newHi += hi - reg.oldHi
+		reg.oldHi = hi
+
+		lines = append(lines, lineRegion{
+			oldLo: sort.SearchInts(oldStarts, reg.oldLo),
+			oldHi: sort.SearchInts(oldStarts, reg.oldHi),
+			newLo: sort.SearchInts(newStarts, reg.newLo),
+			newHi: sort.SearchInts(newStarts, reg.newHi),
+		})
+	}
+	return lines
+}
+
+// hunkRange formats one side of a hunk header.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return strconv.Itoa(start + 1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// writeHunk writes a hunk header and body in unified diff format.
+func writeHunk(sb *strings.Builder, h Hunk) {
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+	for _, line := range h.Lines {
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n" + noNewlineMarker + "\n")
+		}
+	}
+}
+
+// ParseHunks parses the hunks of a unified diff. File headers and any
+// other lines outside hunks are ignored. Use ParseUnifiedDiff to apply the
+// hunks exactly or ApplyPatchFuzzy to place them approximately.
+func ParseHunks(patch string) ([]Hunk, error) {
+	lines := strings.SplitAfter(patch, "\n")
+	var hunks []Hunk
+
+	for i := 0; i < len(lines); {
+		m := hunkHeader.FindStringSubmatch(lines[i])
+		i++
+		if m == nil {
+			continue
+		}
+
+		h := Hunk{}
+		h.OldStart, h.OldLines = parseHunkRange(m[1], m[2])
+		h.NewStart, h.NewLines = parseHunkRange(m[3], m[4])
+
+		oldSeen, newSeen := 0, 0
+		for i < len(lines) && (oldSeen < h.OldLines || newSeen < h.NewLines) {
+			line := lines[i]
+			i++
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+
+			switch {
+			case line == "\n":
+				// Some tools strip the space from empty context lines
+				line = " \n"
+				fallthrough
+			case line[0] == ' ':
+				oldSeen++
+				newSeen++
+			case line[0] == '-':
+				oldSeen++
+			case line[0] == '+':
+				newSeen++
+			case line[0] == '\\':
+				dropLastNewline(h.Lines)
+				continue
+			default:
+				return nil, &ErrInvalidInput{
+					Parameter: "patch",
+					Value:     strings.TrimSuffix(line, "\n"),
+					Reason:    "unexpected line in hunk",
+				}
+			}
+			h.Lines = append(h.Lines, line)
+		}
+
+		if oldSeen != h.OldLines || newSeen != h.NewLines {
+			return nil, &ErrInvalidInput{
+				Parameter: "patch",
+				Value:     strings.TrimSuffix(m[0], "\n"),
+				Reason:    "hunk is shorter than its header",
+			}
+		}
+		if i < len(lines) && strings.HasPrefix(lines[i], "\\") {
+			dropLastNewline(h.Lines)
+			i++
+		}
+
+		hunks = append(hunks, h)
+	}
+
+	return hunks, nil
+}
+
+// parseHunkRange converts "l" and "s" of a hunk header to a zero-based
+// start line and a line count.
+func parseHunkRange(startStr, countStr string) (int, int) {
+	start, _ := strconv.Atoi(startStr)
+	count := 1
+	if countStr != "" {
+		count, _ = strconv.Atoi(countStr)
+	}
+	if count > 0 {
+		start--
+	}
+	return start, count
+}
+
+// dropLastNewline removes the line ending of the last hunk line, as
+// requested by a "\ No newline at end of file" marker.
+func dropLastNewline(lines []string) {
+	if n := len(lines); n > 0 {
+		lines[n-1] = strings.TrimSuffix(lines[n-1], "\n")
+	}
+}
+
+// ParseUnifiedDiff parses a unified diff against before and returns the
+// ChangeSet that performs it. Context and removed lines must match before
+// exactly; otherwise an error is returned.
+//
+// Example:
+//
+//	cs, err := rope.ParseUnifiedDiff(before, patch)
+//	if err == nil {
+//	    after, _ := cs.Apply(before)
+//	}
+func ParseUnifiedDiff(before *Rope, patch string) (*ChangeSet, error) {
+	if before == nil {
+		before = Empty()
+	}
+
+	hunks, err := ParseHunks(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, starts := docLines(before.String())
+	var edits []EditOperation
+	prevEnd := 0
+
+	for _, h := range hunks {
+		if h.OldStart < prevEnd || h.OldStart+h.OldLines > len(lines) {
+			return nil, &ErrInvalidRange{
+				Operation: "ParseUnifiedDiff",
+				Start:     h.OldStart,
+				End:       h.OldStart + h.OldLines,
+				ValidMax:  len(lines),
+			}
+		}
+		if bad := hunkMismatch(h, lines, h.OldStart); bad >= 0 {
+			return nil, &ErrInvalidInput{
+				Parameter: "patch",
+				Value:     fmt.Sprintf("line %d", bad+1),
+				Reason:    "hunk does not match document",
+			}
+		}
+		edits = appendHunkEdits(edits, h, h.OldStart, starts)
+		prevEnd = h.OldStart + h.OldLines
+	}
+
+	return changeSetFromEdits(before.Length(), edits), nil
+}
+
+// ========== Preview ==========
+
+// Preview renders each edit of the changeset against before on its own
+// line, as `@pos: ` followed by up to context unchanged characters on either
+// side, with deleted text marked as [-text-] and inserted text as {+text+}.
+// Positions are character offsets in before. Text is shown as is, so line
+// breaks inside the context or the edit continue on the next line.
+//
+// If before does not have the length the changeset expects, Preview falls
+// back to String.
+//
+// Example:
+//
+//	cs := rope.NewChangeSet(11).Retain(6).Delete(5).Insert("Gopher")
+//	fmt.Println(cs.Preview(rope.New("Hello World"), 3))
+//	// @6: lo [-World-]{+Gopher+}
+func (cs *ChangeSet) Preview(before *Rope, context int) string {
+	if before == nil || before.Length() != cs.lenBefore {
+		return cs.String()
+	}
+	context = max(context, 0)
+
+	var sb strings.Builder
+	pos := 0
+	for i := 0; i < len(cs.operations); {
+		if cs.operations[i].OpType == OpRetain {
+			pos += cs.operations[i].Length
+			i++
+			continue
+		}
+
+		// Gather the run of deletes and inserts making up this edit
+		start, deleted := pos, 0
+		var inserted strings.Builder
+		for ; i < len(cs.operations) && cs.operations[i].OpType != OpRetain; i++ {
+			if op := cs.operations[i]; op.OpType == OpDelete {
+				deleted += op.Length
+			} else {
+				inserted.WriteString(op.Text)
+			}
+		}
+		pos += deleted
+
+		end := min(start+deleted, before.Length())
+		leading, _ := before.Slice(max(start-context, 0), start)
+		removed, _ := before.Slice(start, end)
+		trailing, _ := before.Slice(end, min(end+context, before.Length()))
+
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "@%d: %s", start, leading)
+		if removed != "" {
+			fmt.Fprintf(&sb, "[-%s-]", removed)
+		}
+		if inserted.Len() > 0 {
+			fmt.Fprintf(&sb, "{+%s+}", inserted.String())
+		}
+		sb.WriteString(trailing)
+	}
+	return sb.String()
+}
+
+// hunkMismatch returns the index of the first document line that differs
+// from the context and removed lines of h when h is placed at line at, or
+// -1 if they all match.
+func hunkMismatch(h Hunk, lines []string, at int) int {
+	idx := at
+	for _, line := range h.Lines {
+		if line[0] == '+' {
+			continue
+		}
+		if idx >= len(lines) || lines[idx] != line[1:] {
+			return idx
+		}
+		idx++
+	}
+	return -1
+}
+
+// appendHunkEdits appends the edits that apply h, placed at line at, to
+// edits. starts holds the character offset of every line start.
+func appendHunkEdits(edits []EditOperation, h Hunk, at int, starts []int) []EditOperation {
+	oldIdx := at
+	blockStart, removed := -1, 0
+	var added strings.Builder
+
+	flush := func() {
+		if blockStart < 0 {
+			return
+		}
+		edits = append(edits, EditOperation{
+			From: starts[blockStart],
+			To:   starts[blockStart+removed],
+			Text: added.String(),
+		})
+		blockStart, removed = -1, 0
+		added.Reset()
+	}
+
+	for _, line := range h.Lines {
+		kind, text := line[0], line[1:]
+		if blockStart < 0 && kind != ' ' {
+			blockStart = oldIdx
+		}
+		switch kind {
+		case '+':
+			added.WriteString(text)
+			continue
+		case '-':
+			removed++
+		default:
+			flush()
+		}
+		oldIdx++
+	}
+	flush()
+	return edits
+}
+
+// ApplyPatchFuzzy applies hunks to the rope, tolerating documents that
+// changed since the patch was made. Like the patch command, each hunk is
+// tried at its expected line first, shifted by the offset of the hunk
+// before it, then at lines ever further above and below, up to maxOffset
+// lines away, until its context and removed lines match. Hunks must be in
+// document order and a hunk never lands before the end of the previous one.
+//
+// Returns the patched rope and, for every hunk, the offset in lines at
+// which it was applied. Hunks that match nowhere are skipped, and their
+// indexes are reported in an *ErrPatchFailed together with the rope
+// patched by the remaining hunks.
+//
+// Example:
+//
+//	hunks, _ := rope.ParseHunks(patch)
+//	r2, offsets, err := r.ApplyPatchFuzzy(hunks, 3)
+func (r *Rope) ApplyPatchFuzzy(hunks []Hunk, maxOffset int) (*Rope, []int, error) {
+	lines, starts := docLines(r.String())
+	offsets := make([]int, len(hunks))
+	var edits []EditOperation
+	var failed []int
+	prevEnd, carried := 0, 0
+
+	for i, h := range hunks {
+		expected := h.OldStart + carried
+		at := -1
+		for delta := 0; delta <= maxOffset && at < 0; delta++ {
+			for _, p := range []int{expected - delta, expected + delta} {
+				if p >= prevEnd && p+h.OldLines <= len(lines) && hunkMismatch(h, lines, p) < 0 {
+					at = p
+					break
+				}
+			}
+		}
+		if at < 0 {
+			failed = append(failed, i)
+			continue
+		}
+
+		edits = appendHunkEdits(edits, h, at, starts)
+		offsets[i] = at - h.OldStart
+		carried = offsets[i]
+		prevEnd = at + h.OldLines
+	}
+
+	result, _, err := r.applyEdits(edits)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(failed) > 0 {
+		return result, offsets, &ErrPatchFailed{Hunks: failed}
+	}
+	return result, offsets, nil
+}
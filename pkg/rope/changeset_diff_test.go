@@ -0,0 +1,148 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripDiff exports cs as a unified diff, parses it back and applies it.
+func roundTripDiff(t *testing.T, before *Rope, cs *ChangeSet, contextLines int) (string, *Rope) {
+	t.Helper()
+	patch := cs.ToUnifiedDiff(before, contextLines)
+	parsed, err := ParseUnifiedDiff(before, patch)
+	require.NoError(t, err)
+	result, err := parsed.Apply(before)
+	require.NoError(t, err)
+	return patch, result
+}
+
+// TestToUnifiedDiff_MultiHunk tests a multi-hunk edit with context lines
+func TestToUnifiedDiff_MultiHunk(t *testing.T) {
+	var sb strings.Builder
+	for _, word := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		sb.WriteString(word + "\n")
+	}
+	before := New(sb.String())
+
+	// Change "b" to "B" and delete "i"
+	cs := NewChangeSet(before.Length()).
+		Retain(2).Delete(1).Insert("B").
+		Retain(13).Delete(2)
+
+	patch, result := roundTripDiff(t, before, cs, 1)
+	assert.Equal(t, "--- before\n+++ after\n"+
+		"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"+
+		"@@ -8,3 +8,2 @@\n h\n-i\n j\n", patch)
+
+	expected, err := cs.Apply(before)
+	require.NoError(t, err)
+	assert.Equal(t, expected.String(), result.String())
+
+	// With more context the hunks merge
+	patch, result = roundTripDiff(t, before, cs, 3)
+	assert.Equal(t, 1, strings.Count(patch, "@@ -"))
+	assert.Equal(t, expected.String(), result.String())
+}
+
+// TestToUnifiedDiff_InsertAtEOF tests a patch that appends at the end of the document
+func TestToUnifiedDiff_InsertAtEOF(t *testing.T) {
+	before := New("one\ntwo\n")
+	cs := NewChangeSet(before.Length()).Retain(8).Insert("three\n")
+
+	patch, result := roundTripDiff(t, before, cs, 3)
+	assert.Equal(t, "--- before\n+++ after\n@@ -1,2 +1,3 @@\n one\n two\n+three\n", patch)
+	assert.Equal(t, "one\ntwo\nthree\n", result.String())
+}
+
+// TestToUnifiedDiff_NoNewlineAtEOF tests the missing-newline marker
+func TestToUnifiedDiff_NoNewlineAtEOF(t *testing.T) {
+	before := New("one\ntwo")
+	cs := NewChangeSet(before.Length()).Retain(7).Insert("\nthree")
+
+	patch, result := roundTripDiff(t, before, cs, 0)
+	assert.Equal(t, "--- before\n+++ after\n@@ -2 +2,2 @@\n"+
+		"-two\n\\ No newline at end of file\n+two\n+three\n\\ No newline at end of file\n", patch)
+	assert.Equal(t, "one\ntwo\nthree", result.String())
+}
+
+// TestToUnifiedDiff_SameLineEdits tests that several edits on one line share a region
+func TestToUnifiedDiff_SameLineEdits(t *testing.T) {
+	before := New("x\nhello world\ny\r\nz")
+	cs := NewChangeSet(before.Length()).
+		Retain(2).Delete(1).Insert("H").Retain(5).Delete(1).Insert("W").
+		Retain(8).Delete(1).Insert("Z")
+
+	patch, result := roundTripDiff(t, before, cs, 0)
+	assert.Equal(t, 2, strings.Count(patch, "@@ -"))
+	assert.Contains(t, patch, "-hello world\n+Hello World\n")
+
+	expected, err := cs.Apply(before)
+	require.NoError(t, err)
+	assert.Equal(t, expected.String(), result.String())
+}
+
+// TestToUnifiedDiff_NoChanges tests that an identity changeset yields no diff
+func TestToUnifiedDiff_NoChanges(t *testing.T) {
+	before := New("abc\n")
+	assert.Empty(t, NewChangeSet(4).Retain(4).ToUnifiedDiff(before, 3))
+	assert.Empty(t, NewChangeSet(4).ToUnifiedDiff(before, 3))
+}
+
+// TestParseUnifiedDiff_Mismatch tests rejecting a patch whose context does not match
+func TestParseUnifiedDiff_Mismatch(t *testing.T) {
+	before := New("one\ntwo\n")
+
+	_, err := ParseUnifiedDiff(before, "@@ -1,2 +1,2 @@\n one\n-TWO\n+2\n")
+	var inputErr *ErrInvalidInput
+	assert.ErrorAs(t, err, &inputErr)
+
+	_, err = ParseUnifiedDiff(before, "@@ -2,5 +2,5 @@\n two\n")
+	assert.Error(t, err)
+}
+
+// TestApplyPatchFuzzy_LineAddedAbove tests that a hunk still lands after the document shifted
+func TestApplyPatchFuzzy_LineAddedAbove(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line " + string(rune('a'+i)) + "\n"
+	}
+	original := New(strings.Join(lines, ""))
+	edited, err := original.Replace(original.LineStart(10), original.LineStart(11), "changed\n")
+	require.NoError(t, err)
+
+	hunks, err := ParseHunks(Diff(original, edited).ToUnifiedDiff(original, 3))
+	require.NoError(t, err)
+	require.Len(t, hunks, 1)
+
+	// Someone added two lines at the top in the meantime
+	shifted, err := original.Insert(0, "// header\n\n")
+	require.NoError(t, err)
+
+	result, offsets, err := shifted.ApplyPatchFuzzy(hunks, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, offsets)
+	assert.Equal(t, "// header\n\n"+edited.String(), result.String())
+
+	// Too far away for the allowed offset
+	_, _, err = shifted.ApplyPatchFuzzy(hunks, 1)
+	var patchErr *ErrPatchFailed
+	require.ErrorAs(t, err, &patchErr)
+	assert.Equal(t, []int{0}, patchErr.Hunks)
+}
+
+// TestApplyPatchFuzzy_PartialFailure tests that matching hunks apply and carry their offset
+func TestApplyPatchFuzzy_PartialFailure(t *testing.T) {
+	doc := New("x\na\nb\nc\nd\ne\nf\n")
+	hunks, err := ParseHunks("@@ -1,2 +1,2 @@\n a\n-b\n+B\n@@ -3,1 +3,1 @@\n-zzz\n+Z\n@@ -5,2 +5,2 @@\n e\n-f\n+F\n")
+	require.NoError(t, err)
+
+	result, offsets, err := doc.ApplyPatchFuzzy(hunks, 2)
+	var patchErr *ErrPatchFailed
+	require.ErrorAs(t, err, &patchErr)
+	assert.Equal(t, []int{1}, patchErr.Hunks)
+	assert.Equal(t, []int{1, 0, 1}, offsets)
+	assert.Equal(t, "x\na\nB\nc\nd\ne\nF\n", result.String())
+}
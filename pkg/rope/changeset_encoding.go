@@ -0,0 +1,124 @@
+package rope
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// ChangeSet binary format
+//
+// A serialized changeset is a 4-byte magic "TXCS", a version byte, lenBefore
+// and lenAfter as uvarints, the number of operations as a uvarint, and then
+// every operation as a type byte followed by a uvarint. For retains and
+// deletes the uvarint is the character count; for inserts it is the byte
+// length of the inserted UTF-8 text, which follows. Text captured by
+// DeleteText is not stored, so unchanged text never appears in the encoding.
+
+const (
+	changeSetMagic   = "TXCS"
+	changeSetVersion = 1
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*ChangeSet)(nil)
+	_ encoding.BinaryUnmarshaler = (*ChangeSet)(nil)
+)
+
+// MarshalBinary encodes the changeset in the versioned binary format.
+// It implements encoding.BinaryMarshaler.
+func (cs *ChangeSet) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, len(changeSetMagic)+1+binary.MaxVarintLen64*(3+2*len(cs.operations)))
+	buf = append(buf, changeSetMagic...)
+	buf = append(buf, changeSetVersion)
+	buf = binary.AppendUvarint(buf, uint64(cs.lenBefore))
+	buf = binary.AppendUvarint(buf, uint64(cs.lenAfter))
+	buf = binary.AppendUvarint(buf, uint64(len(cs.operations)))
+	for _, op := range cs.operations {
+		buf = append(buf, byte(op.OpType))
+		if op.OpType == OpInsert {
+			buf = binary.AppendUvarint(buf, uint64(len(op.Text)))
+			buf = append(buf, op.Text...)
+		} else {
+			buf = binary.AppendUvarint(buf, uint64(op.Length))
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the changeset with one decoded from data, which
+// must have been produced by MarshalBinary. The decoded changeset must pass
+// Validate. It implements encoding.BinaryUnmarshaler.
+func (cs *ChangeSet) UnmarshalBinary(data []byte) error {
+	if len(data) < len(changeSetMagic)+1 || string(data[:len(changeSetMagic)]) != changeSetMagic {
+		return errInvalidChangeSetBinary("missing header")
+	}
+	if version := data[len(changeSetMagic)]; version != changeSetVersion {
+		return &ErrInvalidInput{
+			Parameter: "version",
+			Value:     version,
+			Reason:    "unsupported binary format version",
+		}
+	}
+	data = data[len(changeSetMagic)+1:]
+
+	var header [3]uint64 // lenBefore, lenAfter, operation count
+	for i := range header {
+		v, n := binary.Uvarint(data)
+		if n <= 0 || v > uint64(math.MaxInt) {
+			return errInvalidChangeSetBinary("bad header")
+		}
+		header[i] = v
+		data = data[n:]
+	}
+
+	var ops []Operation
+	for i := uint64(0); i < header[2]; i++ {
+		if len(data) == 0 {
+			return errInvalidChangeSetBinary("truncated operation")
+		}
+		opType := OpType(data[0])
+		v, n := binary.Uvarint(data[1:])
+		if n <= 0 || v > uint64(math.MaxInt) {
+			return errInvalidChangeSetBinary("truncated operation")
+		}
+		data = data[1+n:]
+
+		switch opType {
+		case OpRetain, OpDelete:
+			ops = append(ops, Operation{OpType: opType, Length: int(v)})
+		case OpInsert:
+			if v > uint64(len(data)) {
+				return errInvalidChangeSetBinary("truncated insert")
+			}
+			text := string(data[:v])
+			data = data[v:]
+			if !utf8.ValidString(text) {
+				return errInvalidChangeSetBinary("insert is not valid UTF-8")
+			}
+			ops = append(ops, Operation{OpType: OpInsert, Text: text})
+		default:
+			return errInvalidChangeSetBinary(fmt.Sprintf("unknown operation type %d", opType))
+		}
+	}
+	if len(data) != 0 {
+		return errInvalidChangeSetBinary("trailing data")
+	}
+
+	decoded := ChangeSet{operations: ops, lenBefore: int(header[0]), lenAfter: int(header[1])}
+	if err := decoded.Validate(); err != nil {
+		return err
+	}
+	*cs = decoded
+	return nil
+}
+
+func errInvalidChangeSetBinary(reason string) error {
+	return &ErrInvalidInput{
+		Parameter: "data",
+		Value:     "changeset binary",
+		Reason:    reason,
+	}
+}
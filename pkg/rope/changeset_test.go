@@ -1,6 +1,9 @@
 package rope
 
 import (
+	"errors"
+	"math/rand"
+	"strings"
 	"testing"
 )
 
@@ -606,3 +609,471 @@ func TestCompositionPreservesContent(t *testing.T) {
 		}
 	})
 }
+
+// TestChangeSetNormalize tests that equivalent changesets normalize equal.
+func TestChangeSetNormalize(t *testing.T) {
+	// Replace "World" with "There" in "Hello World!", built two ways
+	a := NewChangeSet(12).Retain(6).Delete(5).Insert("There").Retain(1)
+	b := NewChangeSet(12).Retain(3).Retain(3).Insert("The").Delete(2).Insert("re").Delete(3)
+
+	if !a.Equal(b) {
+		t.Errorf("expected equivalent changesets to be equal:\n%#v\n%#v", a.Normalize().operations, b.Normalize().operations)
+	}
+
+	want := []Operation{
+		{OpType: OpRetain, Length: 6},
+		{OpType: OpDelete, Length: 5},
+		{OpType: OpInsert, Text: "There"},
+	}
+	got := b.Normalize().operations
+	if len(got) != len(want) {
+		t.Fatalf("Normalize() = %#v, want %#v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Normalize()[%d] = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+
+	// Normalizing doesn't change the result of applying
+	original := New("Hello World!")
+	before, _ := b.Apply(original)
+	after, _ := b.Normalize().Apply(original)
+	if before.String() != after.String() {
+		t.Errorf("normalized changeset applies differently: %q vs %q", after.String(), before.String())
+	}
+
+	// The receiver is left untouched
+	if len(b.operations) != 6 {
+		t.Errorf("Normalize modified the receiver: %#v", b.operations)
+	}
+}
+
+// TestChangeSetEqual tests that different edits are not equal.
+func TestChangeSetEqual(t *testing.T) {
+	a := NewChangeSet(5).Retain(1).Insert("x")
+	b := NewChangeSet(5).Retain(2).Insert("x")
+	c := NewChangeSet(6).Retain(1).Insert("x")
+
+	if a.Equal(b) {
+		t.Error("changesets inserting at different positions should differ")
+	}
+	if a.Equal(c) {
+		t.Error("changesets for different document lengths should differ")
+	}
+	if !NewChangeSet(3).Equal(NewChangeSet(3).Retain(3)) {
+		t.Error("empty changeset should equal an all-retain changeset")
+	}
+}
+
+// TestApplySequence tests applying a valid chain of changesets.
+func TestApplySequence(t *testing.T) {
+	cs1 := NewChangeSet(5).Retain(5).Insert(" world")
+	cs2 := NewChangeSet(11).Delete(1).Insert("H").Retain(10)
+	cs3 := NewChangeSet(11).Retain(11).Insert("!")
+
+	result, err := ApplySequence(New("hello"), cs1, cs2, cs3)
+	if err != nil {
+		t.Fatalf("ApplySequence failed: %v", err)
+	}
+	if result.String() != "Hello world!" {
+		t.Errorf("got %q, want %q", result.String(), "Hello world!")
+	}
+
+	// No changesets leaves the rope unchanged
+	result, err = ApplySequence(New("same"))
+	if err != nil || result.String() != "same" {
+		t.Errorf("empty sequence: got %q, %v", result.String(), err)
+	}
+}
+
+// TestApplySequence_LengthMismatch tests that a wrong lenBefore in the chain is reported.
+func TestApplySequence_LengthMismatch(t *testing.T) {
+	cs1 := NewChangeSet(5).Retain(5).Insert(" world")
+	// Built against the original length instead of cs1's LenAfter
+	cs2 := NewChangeSet(5).Delete(1).Insert("H").Retain(4)
+
+	result, err := ApplySequence(New("hello"), cs1, cs2)
+	if result != nil {
+		t.Errorf("expected no result, got %q", result.String())
+	}
+
+	var inputErr *ErrInvalidInput
+	if !errors.As(err, &inputErr) {
+		t.Fatalf("expected *ErrInvalidInput, got %v", err)
+	}
+	if inputErr.Parameter != "sets[1]" || inputErr.Value != 5 {
+		t.Errorf("unexpected error details: %v", err)
+	}
+	if !strings.Contains(err.Error(), "document length 11") {
+		t.Errorf("error should mention the document length: %v", err)
+	}
+}
+
+// TestChangeSetValidate tests detecting malformed changesets.
+func TestChangeSetValidate(t *testing.T) {
+	if err := NewChangeSet(5).Retain(2).Delete(1).Insert("xy").Validate(); err != nil {
+		t.Errorf("valid changeset rejected: %v", err)
+	}
+
+	// Retains run past the end of the document
+	cs := NewChangeSet(5).Retain(3).Delete(1).Retain(4)
+	var inputErr *ErrInvalidInput
+	if err := cs.Validate(); !errors.As(err, &inputErr) {
+		t.Fatalf("expected *ErrInvalidInput, got %v", err)
+	}
+	if inputErr.Parameter != "operations" || inputErr.Value != 8 {
+		t.Errorf("unexpected error details: %v", inputErr)
+	}
+
+	// lenAfter disagrees with the operations
+	cs = NewChangeSet(5).Retain(5).Insert("a")
+	cs.lenAfter = 7
+	if err := cs.Validate(); !errors.As(err, &inputErr) || inputErr.Parameter != "lenAfter" {
+		t.Errorf("expected lenAfter error, got %v", err)
+	}
+
+	// Negative lengths
+	cs = NewChangeSet(5).Retain(-1)
+	if err := cs.Validate(); err == nil {
+		t.Error("expected error for negative retain")
+	}
+}
+
+// TestChangeSetValidate_DebugApply tests that Apply fails fast when validation is enabled.
+func TestChangeSetValidate_DebugApply(t *testing.T) {
+	DebugValidateChangeSets.Store(true)
+	defer DebugValidateChangeSets.Store(false)
+
+	cs := NewChangeSet(5).Retain(3).Delete(1).Retain(4)
+	result, err := cs.Apply(New("hello"))
+	var inputErr *ErrInvalidInput
+	if !errors.As(err, &inputErr) {
+		t.Fatalf("expected *ErrInvalidInput, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected no result, got %q", result.String())
+	}
+
+	// Malformed changesets are not composed
+	valid := NewChangeSet(5).Retain(5).Insert("!")
+	composed := valid.Compose(NewChangeSet(6).Retain(9))
+	if !composed.Equal(valid) {
+		t.Errorf("expected composition to fall back to the first changeset")
+	}
+}
+
+// TestChangeSetApplyChecked tests that invalid changesets are rejected before any edit.
+func TestChangeSetApplyChecked(t *testing.T) {
+	doc := New("hello")
+
+	// Delete and retain run past the end of the document
+	cs := NewChangeSet(5).Retain(3).Delete(4).Insert("p")
+	result, err := cs.ApplyChecked(doc)
+	var inputErr *ErrInvalidInput
+	if !errors.As(err, &inputErr) {
+		t.Fatalf("expected *ErrInvalidInput, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected no result, got %q", result.String())
+	}
+	if doc.String() != "hello" {
+		t.Errorf("original rope changed to %q", doc.String())
+	}
+
+	// Built for a different document length
+	if _, err := NewChangeSet(4).Retain(4).ApplyChecked(doc); !errors.As(err, &inputErr) || inputErr.Parameter != "lenBefore" {
+		t.Errorf("expected lenBefore error, got %v", err)
+	}
+
+	// A valid changeset applies like Apply
+	result, err = NewChangeSet(5).Retain(4).Delete(1).Insert("p!").ApplyChecked(doc)
+	if err != nil || result.String() != "hellp!" {
+		t.Errorf("ApplyChecked() = %v, %v, want \"hellp!\"", result, err)
+	}
+}
+
+// TestChangeSetFinish tests catching an overrunning changeset while building it.
+func TestChangeSetFinish(t *testing.T) {
+	_, err := NewChangeSet(5).Retain(3).Insert("x").Retain(4).Finish()
+	var inputErr *ErrInvalidInput
+	if !errors.As(err, &inputErr) {
+		t.Fatalf("expected *ErrInvalidInput, got %v", err)
+	}
+	want := "invalid parameter operations[2]: ends at 7, past lenBefore 5 (retain 4)"
+	if err.Error() != want {
+		t.Errorf("Finish() error = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := NewChangeSet(2).Delete(3).Finish(); err == nil {
+		t.Error("expected an error for deleting past the end")
+	}
+
+	// A valid changeset is completed with a retain of the rest
+	cs, err := NewChangeSet(5).Retain(1).Delete(1).Insert("a").Finish()
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if got := cs.String(); got != `retain 1, delete 1, insert "a", retain 3` {
+		t.Errorf("Finish() = %s", got)
+	}
+	result, err := cs.Apply(New("hello"))
+	if err != nil || result.String() != "hallo" {
+		t.Errorf("Apply() = %v, %v, want \"hallo\"", result, err)
+	}
+}
+
+// TestChangeSetRebase tests moving a local insert past a remote insert.
+func TestChangeSetRebase(t *testing.T) {
+	doc := New("hello world")
+	local := NewChangeSet(11).Retain(6).Insert("big ")
+	remote := NewChangeSet(11).Insert("Oh, ").Retain(5).Delete(1).Insert("_")
+
+	rebased := local.Rebase(remote)
+	if got := rebased.String(); got != `retain 10, insert "big ", retain 5` {
+		t.Errorf("Rebase() = %s", got)
+	}
+
+	server, _ := remote.Apply(doc)
+	result, err := rebased.Apply(server)
+	if err != nil || result.String() != "Oh, hello_big world" {
+		t.Errorf("rebased Apply() = %v, %v, want \"Oh, hello_big world\"", result, err)
+	}
+
+	// Inserts at the same position: the remote text comes first
+	same := NewChangeSet(11).Retain(5).Insert("L").Rebase(NewChangeSet(11).Retain(5).Insert("R"))
+	if got := same.String(); got != `retain 6, insert "L", retain 6` {
+		t.Errorf("Rebase() at the same position = %s", got)
+	}
+}
+
+// TestChangeSetRebase_Converges tests the OT convergence property on random edits.
+func TestChangeSetRebase_Converges(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	for iter := 0; iter < 500; iter++ {
+		doc := New(randomString(rng.Intn(30)))
+		local := randomChangeSet(rng, doc.Length())
+		remote := randomChangeSet(rng, doc.Length())
+
+		afterRemote, err := remote.Apply(doc)
+		if err != nil {
+			t.Fatalf("remote.Apply failed: %v", err)
+		}
+		viaRemote, err := local.Rebase(remote).Apply(afterRemote)
+		if err != nil {
+			t.Fatalf("rebased local.Apply failed: %v", err)
+		}
+
+		afterLocal, err := local.Apply(doc)
+		if err != nil {
+			t.Fatalf("local.Apply failed: %v", err)
+		}
+		viaLocal, err := transformChangeSet(remote, local, true).Apply(afterLocal)
+		if err != nil {
+			t.Fatalf("transformed remote.Apply failed: %v", err)
+		}
+
+		if viaRemote.String() != viaLocal.String() {
+			t.Fatalf("iteration %d: diverged: %q vs %q (doc %q, local %v, remote %v)",
+				iter, viaRemote.String(), viaLocal.String(), doc.String(), local, remote)
+		}
+	}
+}
+
+// TestChangeSetInvertSelfContained tests undoing a SpliceTx without the original rope.
+func TestChangeSetInvertSelfContained(t *testing.T) {
+	doc := New("Hello, wörld!")
+
+	tx, err := SpliceTx(doc, 7, 12, "Go")
+	if err != nil {
+		t.Fatalf("SpliceTx() error = %v", err)
+	}
+	if got := tx.Selection().Primary(); got != Point(9) {
+		t.Errorf("SpliceTx() cursor = %v, want 9", got)
+	}
+	edited, err := tx.Apply(doc)
+	if err != nil || edited.String() != "Hello, Go!" {
+		t.Fatalf("Apply() = %v, %v, want \"Hello, Go!\"", edited, err)
+	}
+
+	// The deletion carries the deleted text
+	ops := tx.Changes().Operations()
+	if ops[1].OpType != OpDelete || ops[1].Text != "wörld" {
+		t.Errorf("delete operation = %+v, want captured \"wörld\"", ops[1])
+	}
+
+	// Undoing needs only the changeset, not the original rope
+	undo := tx.Changes().InvertSelfContained()
+	if undo == nil {
+		t.Fatal("InvertSelfContained() = nil")
+	}
+	restored, err := undo.Apply(edited)
+	if err != nil || restored.String() != "Hello, wörld!" {
+		t.Errorf("undo Apply() = %v, %v, want \"Hello, wörld!\"", restored, err)
+	}
+
+	// Captured text survives fusing adjacent deletions
+	fused := NewChangeSet(4).DeleteText("ab").DeleteText("c")
+	fused.fuse()
+	restored, _ = fused.InvertSelfContained().Apply(New("d"))
+	if restored.String() != "abcd" {
+		t.Errorf("fused undo = %q, want \"abcd\"", restored.String())
+	}
+
+	// Without captured text there is nothing to invert from
+	if NewChangeSet(4).Delete(2).InvertSelfContained() != nil {
+		t.Error("expected nil for a deletion without captured text")
+	}
+}
+
+// TestChangeSetOperations tests inspecting a built changeset.
+func TestChangeSetOperations(t *testing.T) {
+	cs := NewChangeSet(11).Retain(6).Delete(5).Insert("Gopher")
+
+	ops := cs.Operations()
+	want := []Operation{
+		{OpType: OpRetain, Length: 6},
+		{OpType: OpDelete, Length: 5},
+		{OpType: OpInsert, Text: "Gopher"},
+	}
+	if cs.OpCount() != len(want) || len(ops) != len(want) {
+		t.Fatalf("expected %d operations, got OpCount %d and %d ops", len(want), cs.OpCount(), len(ops))
+	}
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Errorf("op %d: expected %v, got %v", i, want[i], ops[i])
+		}
+	}
+
+	// The returned slice is a copy
+	ops[0].Length = 100
+	ops = append(ops[:1], Operation{OpType: OpDelete, Length: 1})
+	if got := cs.Operations(); got[0].Length != 6 || got[1] != want[1] {
+		t.Errorf("mutating the returned slice changed the changeset: %v", got)
+	}
+	result, err := cs.Apply(New("Hello World"))
+	if err != nil || result.String() != "Hello Gopher" {
+		t.Errorf("expected %q, got %v (err %v)", "Hello Gopher", result, err)
+	}
+
+	if NewChangeSet(3).OpCount() != 0 {
+		t.Errorf("expected a new changeset to have no operations")
+	}
+}
+
+// TestOperationString tests the debug form of operations.
+func TestOperationString(t *testing.T) {
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{Operation{OpType: OpRetain, Length: 5}, "retain 5"},
+		{Operation{OpType: OpDelete, Length: 2}, "delete 2"},
+		{Operation{OpType: OpInsert, Text: "a\"b\n"}, `insert "a\"b\n"`},
+	}
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
+// TestChangeSetString tests the compact string form of a changeset.
+func TestChangeSetString(t *testing.T) {
+	cs := NewChangeSet(10).Retain(5).Delete(2).Insert("abc")
+	if got, want := cs.String(), `retain 5, delete 2, insert "abc"`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got := NewChangeSet(3).String(); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
+
+// TestChangeSetPreview tests that the preview marks inserted and deleted text.
+func TestChangeSetPreview(t *testing.T) {
+	before := New("The quick brown fox jumps")
+	cs := NewChangeSet(before.Length()).
+		Retain(4).Delete(6).
+		Retain(6).Insert("red ").
+		Retain(4).Delete(5).Insert("leaps")
+
+	want := "@4: The [-quick -]brow\n" +
+		"@16: own {+red +}fox \n" +
+		"@20: fox [-jumps-]{+leaps+}"
+	if got := cs.Preview(before, 4); got != want {
+		t.Errorf("unexpected preview:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without context only the markers remain
+	if got := cs.Preview(before, 0); !strings.HasPrefix(got, "@4: [-quick -]\n@16: {+red +}\n") {
+		t.Errorf("unexpected preview without context:\n%s", got)
+	}
+
+	// A document of the wrong length falls back to String
+	if got := cs.Preview(New("short"), 4); got != cs.String() {
+		t.Errorf("expected fallback to String, got %q", got)
+	}
+}
+
+// TestChangeSetFromEdits tests building a changeset from absolute edits.
+func TestChangeSetFromEdits(t *testing.T) {
+	doc := New("the quick brown fox")
+
+	// Unsorted, disjoint edits, including an insertion where a deletion starts
+	cs, err := ChangeSetFromEdits(doc.Length(), []EditOperation{
+		{From: 16, To: 19, Text: "cat"},
+		{From: 4, To: 10},
+		{From: 0, To: 0, Text: "> "},
+		{From: 10, To: 10, Text: "very "},
+		{From: 10, To: 15, Text: "red"},
+	})
+	if err != nil {
+		t.Fatalf("ChangeSetFromEdits failed: %v", err)
+	}
+	if err := cs.Validate(); err != nil {
+		t.Fatalf("invalid changeset: %v", err)
+	}
+	if cs.LenBefore() != doc.Length() {
+		t.Errorf("LenBefore = %d, want %d", cs.LenBefore(), doc.Length())
+	}
+
+	result, err := cs.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got, want := result.String(), "> the very red cat"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if cs.LenAfter() != result.Length() {
+		t.Errorf("LenAfter = %d, want %d", cs.LenAfter(), result.Length())
+	}
+
+	identity, err := ChangeSetFromEdits(doc.Length(), nil)
+	if err != nil {
+		t.Fatalf("ChangeSetFromEdits failed: %v", err)
+	}
+	if unchanged, _ := identity.Apply(doc); unchanged.String() != doc.String() {
+		t.Errorf("no edits should leave the document unchanged, got %q", unchanged.String())
+	}
+}
+
+// TestChangeSetFromEdits_Invalid tests that bad edits are rejected.
+func TestChangeSetFromEdits_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		edits []EditOperation
+	}{
+		{"past the end", []EditOperation{{From: 3, To: 6}}},
+		{"negative", []EditOperation{{From: -1, To: 1}}},
+		{"reversed", []EditOperation{{From: 3, To: 1}}},
+		{"overlapping", []EditOperation{{From: 2, To: 4}, {From: 0, To: 3, Text: "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ChangeSetFromEdits(5, tt.edits); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
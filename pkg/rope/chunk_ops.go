@@ -2,6 +2,9 @@ package rope
 
 import (
 	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 )
 
 // ========== Chunk Operations ==========
@@ -91,8 +94,11 @@ func findChunkAtByte(n RopeNode, byteIdx int) (int, string) {
 		}
 		offset, text := findChunkAtByte(node.right, byteIdx-leftSize)
 		return leftSize + offset, text
+	case nil:
+		return 0, ""
+	default:
+		return 0, n.Slice(0, n.Length())
 	}
-	return 0, ""
 }
 
 // Chunks creates an iterator over the rope's chunks.
@@ -107,6 +113,17 @@ func (r *Rope) Chunks() *ChunksIterator {
 	}
 }
 
+// ChunksReverse creates an iterator over the rope's chunks from last to first.
+// Each chunk's text is still in forward order, and its ChunkInfo describes
+// its position in the rope as with Chunks.
+func (r *Rope) ChunksReverse() *ChunksIterator {
+	it := r.Chunks()
+	for i, j := 0, len(it.chunkInfos)-1; i < j; i, j = i+1, j-1 {
+		it.chunkInfos[i], it.chunkInfos[j] = it.chunkInfos[j], it.chunkInfos[i]
+	}
+	return it
+}
+
 // collectChunkInfos recursively collects all chunk information.
 func (r *Rope) collectChunkInfos() []ChunkInfo {
 	if r == nil || r.root == nil {
@@ -125,9 +142,14 @@ func (r *Rope) collectChunkInfos() []ChunkInfo {
 // collectChunks recursively collects chunks.
 func collectChunks(n RopeNode, infos *[]ChunkInfo, byteIdx, charIdx, lineIdx *int) {
 	switch node := n.(type) {
-	case *LeafNode:
-		textLen := len(node.text)
-		charCount := runeCount(node.text)
+	case nil:
+	case *InternalNode:
+		collectChunks(node.left, infos, byteIdx, charIdx, lineIdx)
+		collectChunks(node.right, infos, byteIdx, charIdx, lineIdx)
+	default:
+		text := nodeText(n)
+		textLen := len(text)
+		charCount := runeCount(text)
 
 		// Skip empty leaf nodes to match ropey behavior
 		// Empty rope should have 0 chunks, not 1 empty chunk
@@ -138,7 +160,7 @@ func collectChunks(n RopeNode, infos *[]ChunkInfo, byteIdx, charIdx, lineIdx *in
 				LineIdx: *lineIdx,
 				ByteLen: textLen,
 				CharLen: charCount,
-				Text:    node.text,
+				Text:    text,
 				IsEmpty: textLen == 0,
 			})
 		}
@@ -146,15 +168,11 @@ func collectChunks(n RopeNode, infos *[]ChunkInfo, byteIdx, charIdx, lineIdx *in
 		*byteIdx += textLen
 		*charIdx += charCount
 		// Update line count
-		for _, ch := range node.text {
+		for _, ch := range text {
 			if ch == '\n' {
 				*lineIdx++
 			}
 		}
-
-	case *InternalNode:
-		collectChunks(node.left, infos, byteIdx, charIdx, lineIdx)
-		collectChunks(node.right, infos, byteIdx, charIdx, lineIdx)
 	}
 }
 
@@ -371,8 +389,258 @@ func (r *Rope) ChunksAtLine(lineIdx int) (*ChunksIterator, int, int, int) {
 	return it, 0, 0, 0
 }
 
+// WalkLeaves calls fn with the text of every non-empty leaf of the rope, in
+// order, and the character offset where it starts, stopping early when fn
+// returns false. Unlike Chunks, nothing is collected up front, and only the
+// leaf text is exposed, never the nodes themselves.
+//
+// Example:
+//
+//	r.WalkLeaves(func(text string, charOffset int) bool {
+//	    fmt.Printf("%d: %q\n", charOffset, text)
+//	    return true
+//	})
+func (r *Rope) WalkLeaves(fn func(text string, charOffset int) bool) {
+	if r != nil {
+		walkLeaves(r.root, 0, fn)
+	}
+}
+
+// walkLeaves implements WalkLeaves for the subtree n starting at offset,
+// using the cached left subtree lengths to track offsets.
+func walkLeaves(n RopeNode, offset int, fn func(text string, charOffset int) bool) bool {
+	switch node := n.(type) {
+	case nil:
+		return true
+	case *InternalNode:
+		return walkLeaves(node.left, offset, fn) && walkLeaves(node.right, offset+node.length, fn)
+	case *LeafNode:
+		return node.text == "" || fn(node.text, offset)
+	default:
+		text := n.Slice(0, n.Length())
+		return text == "" || fn(text, offset)
+	}
+}
+
+// SplitAtLines divides the rope into consecutive character ranges of
+// whole lines of about targetChunkBytes bytes each, for handing the
+// document to parallel workers. Every range but the last ends just after a
+// '\n', so each starts at a line start; a range grows past the target only
+// to finish its last line. Together the ranges cover the whole document.
+// A targetChunkBytes of zero or less yields a single range.
+//
+// Example:
+//
+//	for _, rng := range r.SplitAtLines(64 * 1024) {
+//	    text, _ := r.Slice(rng.From(), rng.To())
+//	    go lex(text)
+//	}
+func (r *Rope) SplitAtLines(targetChunkBytes int) []Range {
+	if r == nil || r.length == 0 {
+		return nil
+	}
+	if targetChunkBytes <= 0 {
+		return []Range{NewRange(0, r.length)}
+	}
+
+	var ranges []Range
+	start, pos, chunkBytes := 0, 0, 0
+	forEachLeaf(r.root, func(text string) bool {
+		for text != "" {
+			// Cut at the first '\n' at or after the target byte
+			from := max(targetChunkBytes-chunkBytes-1, 0)
+			idx := -1
+			if from < len(text) {
+				if idx = strings.IndexByte(text[from:], '\n'); idx >= 0 {
+					idx += from
+				}
+			}
+			if idx < 0 {
+				chunkBytes += len(text)
+				pos += RuneCountInStringFast(text)
+				return true
+			}
+
+			pos += RuneCountInStringFast(text[:idx+1])
+			ranges = append(ranges, NewRange(start, pos))
+			start, chunkBytes = pos, 0
+			text = text[idx+1:]
+		}
+		return true
+	})
+
+	if start < r.length {
+		ranges = append(ranges, NewRange(start, r.length))
+	}
+	return ranges
+}
+
+// MapReduceChunks splits the rope into line-aligned pieces of about
+// chunkBytes bytes with SplitAtLines, calls mapFn on the text of every piece
+// concurrently on a pool of GOMAXPROCS workers, and combines the results
+// with reduceFn in document order, so reduceFn only has to be associative.
+// It returns the zero value of T for an empty rope.
+//
+// It is a function rather than a method because Go methods cannot have
+// type parameters.
+//
+// Example:
+//
+//	words := rope.MapReduceChunks(r, 1<<20,
+//	    func(text string) int { return len(strings.Fields(text)) },
+//	    func(a, b int) int { return a + b })
+func MapReduceChunks[T any](r *Rope, chunkBytes int, mapFn func(text string) T, reduceFn func(a, b T) T) T {
+	var result T
+	ranges := r.SplitAtLines(chunkBytes)
+	if len(ranges) == 0 {
+		return result
+	}
+
+	results := make([]T, len(ranges))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := min(runtime.GOMAXPROCS(0), len(ranges)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				text, _ := r.Slice(ranges[i].From(), ranges[i].To())
+				results[i] = mapFn(text)
+			}
+		}()
+	}
+	for i := range ranges {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result = results[0]
+	for _, next := range results[1:] {
+		result = reduceFn(result, next)
+	}
+	return result
+}
+
 // ========== Helper Functions ==========
 
+// forEachLeaf calls fn with the text of every leaf in order, stopping early
+// when fn returns false. Returns false if iteration was stopped.
+func forEachLeaf(n RopeNode, fn func(text string) bool) bool {
+	switch node := n.(type) {
+	case nil:
+		return true
+	case *LeafNode:
+		return fn(node.text)
+	case *InternalNode:
+		return forEachLeaf(node.left, fn) && forEachLeaf(node.right, fn)
+	default:
+		return fn(n.Slice(0, n.Length()))
+	}
+}
+
+// leafWalker yields the text of a rope's leaves one at a time, forward or
+// backward, without collecting them up front. Empty leaves are skipped.
+type leafWalker struct {
+	stack   []RopeNode
+	reverse bool
+}
+
+func newLeafWalker(r *Rope, reverse bool) *leafWalker {
+	w := &leafWalker{reverse: reverse}
+	if r != nil && r.root != nil {
+		w.stack = append(w.stack, r.root)
+	}
+	return w
+}
+
+// leafWalkerAt returns a forward walker starting with the leaf containing
+// character position pos, and the position where that leaf starts.
+func leafWalkerAt(r *Rope, pos int) (*leafWalker, int) {
+	w := &leafWalker{}
+	if r == nil || r.root == nil {
+		return w, 0
+	}
+
+	node, start := r.root, 0
+	for {
+		internal, ok := node.(*InternalNode)
+		if !ok {
+			break
+		}
+		if pos-start < internal.length {
+			w.stack = append(w.stack, internal.right)
+			node = internal.left
+		} else {
+			start += internal.length
+			node = internal.right
+		}
+	}
+	w.stack = append(w.stack, node)
+	return w, start
+}
+
+// next returns the next non-empty leaf text, or false when done.
+func (w *leafWalker) next() (string, bool) {
+	for len(w.stack) > 0 {
+		n := w.stack[len(w.stack)-1]
+		w.stack = w.stack[:len(w.stack)-1]
+
+		switch node := n.(type) {
+		case *InternalNode:
+			if w.reverse {
+				w.stack = append(w.stack, node.left, node.right)
+			} else {
+				w.stack = append(w.stack, node.right, node.left)
+			}
+		case *LeafNode:
+			if node.text != "" {
+				return node.text, true
+			}
+		case nil:
+		default:
+			if text := n.Slice(0, n.Length()); text != "" {
+				return text, true
+			}
+		}
+	}
+	return "", false
+}
+
+// collectRunes decodes the runes of n between start and end into dst,
+// descending only into subtrees that overlap the range.
+// Returns the number of runes written.
+func collectRunes(n RopeNode, start, end int, dst []rune) int {
+	switch node := n.(type) {
+	case nil:
+		return 0
+	case *InternalNode:
+		written := 0
+		if start < node.length {
+			written = collectRunes(node.left, start, min(end, node.length), dst)
+		}
+		if end > node.length {
+			written += collectRunes(node.right, max(start-node.length, 0), end-node.length, dst[written:])
+		}
+		return written
+	case *LeafNode:
+		written, pos := 0, 0
+		for _, ch := range node.text {
+			if pos >= end {
+				break
+			}
+			if pos >= start {
+				dst[written] = ch
+				written++
+			}
+			pos++
+		}
+		return written
+	default:
+		return copy(dst, []rune(n.Slice(start, end)))
+	}
+}
+
 // runeCount returns the number of runes in a string.
 func runeCount(s string) int {
 	count := 0
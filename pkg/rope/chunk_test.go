@@ -1,7 +1,9 @@
 package rope
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -311,3 +313,154 @@ func TestChunks_EmptyRope(t *testing.T) {
 	assert.Equal(t, 0, it.Count())
 	assert.False(t, it.Next())
 }
+
+func TestChunksReverse_ReproducesContent(t *testing.T) {
+	text := "héllo wörld, 世界 🎉 and more text"
+	r := chunkedRope(text, 4)
+
+	forward := r.Chunks().ToSlice()
+	reverse := r.ChunksReverse().ToSlice()
+	assert.Equal(t, len(forward), len(reverse))
+	assert.True(t, len(reverse) > 1)
+
+	// Reversing the chunk order and concatenating gives back the text
+	var joined string
+	for i := len(reverse) - 1; i >= 0; i-- {
+		joined += reverse[i]
+	}
+	assert.Equal(t, text, joined)
+
+	// The first chunk yielded is the last one, with its real position
+	it := r.ChunksReverse()
+	assert.True(t, it.Next())
+	info := it.CurrentInfo()
+	assert.Equal(t, forward[len(forward)-1], info.Text)
+	assert.Equal(t, r.Length()-info.CharLen, info.CharIdx)
+}
+
+func TestChunksReverse_SingleLeaf(t *testing.T) {
+	it := New("only leaf").ChunksReverse()
+	assert.True(t, it.Next())
+	assert.Equal(t, "only leaf", it.Current())
+	assert.False(t, it.Next())
+
+	var r *Rope
+	assert.False(t, r.ChunksReverse().Next())
+}
+
+// TestWalkLeaves tests that leaf texts and offsets reconstruct the document
+func TestWalkLeaves(t *testing.T) {
+	text := strings.Repeat("héllo wörld 你好\n", 20)
+	r := chunkedRope(text, 9)
+
+	var b strings.Builder
+	next := 0
+	leaves := 0
+	r.WalkLeaves(func(leaf string, charOffset int) bool {
+		assert.NotEmpty(t, leaf)
+		assert.Equal(t, next, charOffset)
+		b.WriteString(leaf)
+		next += utf8.RuneCountInString(leaf)
+		leaves++
+		return true
+	})
+	assert.Equal(t, text, b.String())
+	assert.Equal(t, r.Length(), next)
+	assert.Greater(t, leaves, 1)
+
+	// Early termination
+	calls := 0
+	r.WalkLeaves(func(string, int) bool {
+		calls++
+		return calls < 2
+	})
+	assert.Equal(t, 2, calls)
+
+	var empty *Rope
+	empty.WalkLeaves(func(string, int) bool {
+		t.Fatal("nil rope has no leaves")
+		return true
+	})
+	Empty().WalkLeaves(func(string, int) bool {
+		t.Fatal("empty rope has no leaves")
+		return true
+	})
+}
+
+// TestSplitAtLines tests that ranges hold whole lines of about the target size
+func TestSplitAtLines(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 500; i++ {
+		b.WriteString(strings.Repeat("wörd ", i%13))
+		b.WriteString("\n")
+	}
+	text := b.String()
+	const target = 256
+
+	for _, r := range []*Rope{New(text), chunkedRope(text, 17)} {
+		ranges := r.SplitAtLines(target)
+		assert.Greater(t, len(ranges), 1)
+
+		var joined strings.Builder
+		next := 0
+		for i, rng := range ranges {
+			assert.Equal(t, next, rng.From())
+			next = rng.To()
+
+			chunk := sliceRange(r, rng)
+			joined.WriteString(chunk)
+			if rng.From() > 0 {
+				ch, _ := r.CharAt(rng.From() - 1)
+				assert.Equal(t, '\n', ch)
+			}
+
+			// Each range reaches the target and ends with the line crossing it
+			if i < len(ranges)-1 {
+				assert.GreaterOrEqual(t, len(chunk), target)
+				lastLine := strings.LastIndexByte(chunk[:len(chunk)-1], '\n')
+				assert.Less(t, lastLine+1, target)
+			}
+		}
+		assert.Equal(t, text, joined.String())
+	}
+}
+
+// TestSplitAtLines_Edges tests small documents and targets
+func TestSplitAtLines_Edges(t *testing.T) {
+	r := New("ab\ncd\nef")
+
+	assert.Equal(t, []Range{NewRange(0, 3), NewRange(3, 6), NewRange(6, 8)}, r.SplitAtLines(1))
+	assert.Equal(t, []Range{NewRange(0, 8)}, r.SplitAtLines(100))
+	assert.Equal(t, []Range{NewRange(0, 8)}, r.SplitAtLines(0))
+	assert.Equal(t, []Range{NewRange(0, 11)}, New("no newlines").SplitAtLines(3))
+	assert.Nil(t, Empty().SplitAtLines(10))
+}
+
+// TestMapReduceChunks_WordCount tests a parallel word count against a sequential one
+func TestMapReduceChunks_WordCount(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		b.WriteString(strings.Repeat("lorem ipsum ", i%7))
+		b.WriteString("dölor\n")
+	}
+	text := b.String()
+	r := chunkedRope(text, 97)
+
+	count := func(text string) int { return len(strings.Fields(text)) }
+	sum := func(a, b int) int { return a + b }
+	assert.Equal(t, count(text), MapReduceChunks(r, 512, count, sum))
+	assert.Equal(t, count(text), MapReduceChunks(r, 0, count, sum))
+	assert.Equal(t, 0, MapReduceChunks(Empty(), 512, count, sum))
+}
+
+// TestMapReduceChunks_Order tests that results are reduced in document order
+func TestMapReduceChunks_Order(t *testing.T) {
+	text := strings.Repeat("line of text\n", 1000)
+	r := New(text)
+
+	identity := func(text string) string { return text }
+	concat := func(a, b string) string { return a + b }
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, text, MapReduceChunks(r, 100, identity, concat))
+	}
+}
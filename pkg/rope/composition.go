@@ -1,5 +1,9 @@
 package rope
 
+import (
+	"unicode/utf8"
+)
+
 // Compose composes this changeset with another, producing a changeset that
 // represents applying this changeset followed by the other.
 // This implementation follows Helix editor's approach with OT-based composition.
@@ -32,8 +36,8 @@ func (cs *ChangeSet) Compose(other *ChangeSet) *ChangeSet {
 
 	// KEY INVARIANT: csFinal.lenAfter must equal otherFinal.lenBefore
 	// This is the fundamental requirement for composition
-	if csFinal.lenAfter != otherFinal.lenBefore {
-		// Cannot compose - length mismatch
+	if csFinal.lenAfter != otherFinal.lenBefore || (DebugValidateChangeSets.Load() && (csFinal.Validate() != nil || otherFinal.Validate() != nil)) {
+		// Cannot compose - length mismatch (or malformed changeset when debugging)
 		// Return a changeset that just applies cs (fallback)
 		result := NewChangeSet(csFinal.lenBefore)
 		result.operations = make([]Operation, len(csFinal.operations))
@@ -47,9 +51,9 @@ func (cs *ChangeSet) Compose(other *ChangeSet) *ChangeSet {
 	secondOps := otherFinal.operations
 	i, j := 0, 0
 
-	// Helix-style composition loop
+	// Helix-style composition loop. Both operation lists are private copies,
+	// so partially consumed operations are shortened in place.
 	for i < len(firstOps) || j < len(secondOps) {
-		// Get current operations (use placeholder if exhausted)
 		var firstOp, secondOp *Operation
 		if i < len(firstOps) {
 			firstOp = &firstOps[i]
@@ -58,79 +62,32 @@ func (cs *ChangeSet) Compose(other *ChangeSet) *ChangeSet {
 			secondOp = &secondOps[j]
 		}
 
-		// Rule 1: Deletion in first (A) has highest priority
-		if firstOp != nil && firstOp.OpType == OpDelete {
-			// Check if secondOp is also Delete
-			if secondOp != nil && secondOp.OpType == OpDelete {
-				// Delete(A) + Delete(B): merge them
-				// Delete(B) wants to delete deleteLen chars, Delete(A) deletes deleteALen chars
-				// These are deleting the same content, so output Delete(deleteALen)
-				// and reduce Delete(B) by deleteALen
-				deleteALen := firstOp.Length
-				deleteBLen := secondOp.Length
-
-				if deleteALen < deleteBLen {
-					// Delete(A) is smaller - output Delete(deleteALen), reduce Delete(B)
-					result.addOperation(*firstOp)
-					i++
-					// Put back reduced Delete(B)
-					secondOps[j] = Operation{OpType: OpDelete, Length: deleteBLen - deleteALen}
-					continue
-				} else if deleteALen == deleteBLen {
-					// Delete(A) == Delete(B) - output Delete(deleteALen), consume both
-					result.addOperation(*firstOp)
-					i++
-					j++
-					continue
-				} else {
-					// Delete(A) is larger - output Delete(deleteBLen), reduce Delete(A)
-					result.addOperation(Operation{OpType: OpDelete, Length: deleteBLen})
-					j++
-					// Put back reduced Delete(A)
-					i++
-					firstOps[i-1] = Operation{OpType: OpDelete, Length: deleteALen - deleteBLen}
-					continue
-				}
-			} else {
-				// Delete(A) with non-Delete(B): output Delete(A) as-is
-				result.addOperation(*firstOp)
-				i++
-				// Don't increment j - keep second operation for next iteration
-				continue
-			}
-		}
-
-		// Rule 2: Insertion in second (B) has highest priority
-		// But NOT when first is Delete (Delete won't be skipped)
-		if secondOp != nil && secondOp.OpType == OpInsert {
-			if firstOp == nil || firstOp.OpType != OpDelete {
-				result.addOperation(*secondOp)
-				j++
-				// Don't increment i - keep first operation for next iteration
-				continue
-			}
-			// If first is Delete, fall through - Delete(A) already handled this
-		}
-
-		// If we get here, both operations are present and neither is Delete(A) nor Insert(B)
-		if firstOp == nil {
-			// Only second operations remaining
+		switch {
+		case firstOp != nil && firstOp.OpType == OpDelete:
+			// Deletions in A remove original text B never sees
+			result.addOperation(*firstOp)
+			i++
+		case secondOp != nil && secondOp.OpType == OpInsert:
+			// Insertions in B add text A never saw
 			result.addOperation(*secondOp)
 			j++
-			continue
-		}
-
-		if secondOp == nil {
-			// Only first operations remaining
+		case firstOp == nil:
+			// Lengths match, so only a trailing remainder can be left
+			result.addOperation(*secondOp)
+			j++
+		case secondOp == nil:
 			result.addOperation(*firstOp)
 			i++
-			continue
-		}
-
-		// Compose the two operations
-		composed := composeOperations(*firstOp, *secondOp, &i, &j, firstOps, secondOps)
-		if composed != nil {
-			result.addOperation(*composed)
+		default:
+			if composed, ok := composeOperations(firstOp, secondOp); ok {
+				result.addOperation(composed)
+			}
+			if operationLen(*firstOp) == 0 {
+				i++
+			}
+			if secondOp.Length == 0 {
+				j++
+			}
 		}
 	}
 
@@ -148,152 +105,39 @@ func (cs *ChangeSet) clone() *ChangeSet {
 	return clone
 }
 
-// composeOperations composes two individual operations.
-// Returns the composed operation, or nil if operations should be skipped.
-// Updates indices i and j as needed.
-// This implements the remaining cases after handling Delete(A) and Insert(B) priority.
-func composeOperations(firstOp, secondOp Operation, i, j *int, firstOps, secondOps []Operation) *Operation {
-	switch firstOp.OpType {
-	case OpInsert:
-		// First operation inserts text
-		insertText := firstOp.Text
-		insertLen := len([]rune(insertText))
-
-		switch secondOp.OpType {
-		case OpDelete:
-			// Delete in second operation
-			deleteLen := secondOp.Length
-
-			if insertLen < deleteLen {
-				// Insert is shorter than delete - both operations cancel out (insert is deleted)
-				*i++
-				*j++
-				// Put back remaining delete
-				remainingDelete := deleteLen - insertLen
-				if remainingDelete > 0 {
-					*j--
-					secondOps[*j] = Operation{OpType: OpDelete, Length: remainingDelete}
-				}
-				return nil // Skip the Insert (it's deleted)
-			} else if insertLen == deleteLen {
-				// Exact match - both operations cancel out
-				*i++
-				*j++
-				return nil // Skip both
-			} else {
-				// Insert is longer than delete - part of insert is deleted
-				*i++
-				*j++
-				// Put back the remaining insert
-				remainingInsert := string([]rune(insertText)[deleteLen:])
-				*i--
-				firstOps[*i] = Operation{OpType: OpInsert, Text: remainingInsert}
-				return nil // Skip the Delete (consumed by insert)
-			}
-
-		case OpRetain:
-			// Second operation retains
-			// Insert doesn't consume characters, Retain does
-			// So we should output Insert, and the Retain will be processed later
-			result := Operation{OpType: OpInsert, Text: insertText}
-			*i++
-			// Don't increment j - the Retain will consume characters from Retain(A) or Delete(A) operations
-			return &result
-
-		case OpInsert:
-			// This shouldn't happen - Insert(B) is handled with priority
-			// But if we get here, just return the second insert
-			*j++
-			return &secondOp
+// composeOperations composes an Insert or Retain of the first changeset
+// with a Retain or Delete of the second, which both cover the same text of
+// the intermediate document. It consumes the overlapping part of both
+// operations, shortening them in place, and returns the composed operation,
+// or false if the overlap disappears entirely (text inserted by the first
+// changeset and deleted by the second).
+func composeOperations(firstOp, secondOp *Operation) (Operation, bool) {
+	n := min(operationLen(*firstOp), secondOp.Length)
+	secondOp.Length -= n
+
+	if firstOp.OpType == OpInsert {
+		runes := []rune(firstOp.Text)
+		firstOp.Text = string(runes[n:])
+		if secondOp.OpType == OpDelete {
+			return Operation{}, false
 		}
+		return Operation{OpType: OpInsert, Text: string(runes[:n])}, true
+	}
 
-	case OpRetain:
-		// First operation retains some characters
-		retainLen := firstOp.Length
-
-		switch secondOp.OpType {
-		case OpDelete:
-			// Delete in second operation
-			deleteLen := secondOp.Length
-
-			if retainLen < deleteLen {
-				// Retain less than delete - delete the retained content
-				result := Operation{OpType: OpDelete, Length: retainLen}
-				*i++
-				// Put back delete with reduced length
-				*j++
-				remainingDelete := deleteLen - retainLen
-				if remainingDelete > 0 {
-					*j--
-					secondOps[*j] = Operation{OpType: OpDelete, Length: remainingDelete}
-				}
-				return &result
-			} else if retainLen == deleteLen {
-				// Exact match - delete the retained content
-				result := Operation{OpType: OpDelete, Length: deleteLen}
-				*i++
-				*j++
-				return &result
-			} else {
-				// Retain more than delete - delete some, retain the rest
-				result := Operation{OpType: OpDelete, Length: deleteLen}
-				*i++
-				*j++
-
-				// Put back remaining retain
-				remainingRetain := retainLen - deleteLen
-				if remainingRetain > 0 {
-					*i--
-					firstOps[*i] = Operation{OpType: OpRetain, Length: remainingRetain}
-				}
-				return &result
-			}
-
-		case OpRetain:
-			// Both retain - take minimum
-			minLen := retainLen
-			if secondOp.Length < minLen {
-				minLen = secondOp.Length
-			}
-
-			result := Operation{OpType: OpRetain, Length: minLen}
-			*i++
-			*j++
-
-			// Handle remaining parts
-			firstRemaining := retainLen - minLen
-			secondRemaining := secondOp.Length - minLen
-
-			if firstRemaining > 0 && secondRemaining > 0 {
-				// Both have remaining - put back both
-				*i--
-				*j--
-				firstOps[*i] = Operation{OpType: OpRetain, Length: firstRemaining}
-				secondOps[*j] = Operation{OpType: OpRetain, Length: secondRemaining}
-				return &result
-			} else if firstRemaining > 0 {
-				// First has remaining - put it back
-				*i--
-				firstOps[*i] = Operation{OpType: OpRetain, Length: firstRemaining}
-				return &result
-			} else if secondRemaining > 0 {
-				// Second has remaining - put it back
-				*j--
-				secondOps[*j] = Operation{OpType: OpRetain, Length: secondRemaining}
-				return &result
-			}
-
-			return &result
-
-		case OpInsert:
-			// This shouldn't happen - Insert(B) is handled with priority
-			// But if we get here, just return the insert
-			return &secondOp
-		}
+	firstOp.Length -= n
+	if secondOp.OpType == OpDelete {
+		return Operation{OpType: OpDelete, Length: n}, true
 	}
+	return Operation{OpType: OpRetain, Length: n}, true
+}
 
-	// Should not reach here
-	return nil
+// operationLen returns the number of characters an operation covers in the
+// document it produces: the inserted text for Insert, Length otherwise.
+func operationLen(op Operation) int {
+	if op.OpType == OpInsert {
+		return utf8.RuneCountInString(op.Text)
+	}
+	return op.Length
 }
 
 // addOperation adds an operation to the changeset with fusion.
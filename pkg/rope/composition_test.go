@@ -1,6 +1,7 @@
 package rope
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -27,11 +28,11 @@ func TestCompose_Basic(t *testing.T) {
 
 	// Changeset B: delete 10 chars, insert "世orld", retain 5
 	// This matches the Helix test exactly
-	// Delete(10) removes "hello te" from "hello test! abc"
+	// Delete(10) removes "hello test" from "hello test! abc"
 	// Insert("世orld") inserts replacement
 	// Retain(5) keeps "! abc"
 	cs2 := NewChangeSet(cs1.LenAfter()).
-		Delete(10).      // "hello te"
+		Delete(10).      // "hello test"
 		Insert("世orld"). // 5 chars
 		Retain(5)        // "! abc"
 
@@ -44,17 +45,104 @@ func TestCompose_Basic(t *testing.T) {
 	if result.String() != expected {
 		t.Errorf("Expected %q, got %q", expected, result.String())
 	}
+	sequential, _ := cs2.Apply(result1)
+	if result.String() != sequential.String() {
+		t.Errorf("Expected composition to match sequential application %q, got %q", sequential.String(), result.String())
+	}
+	if composed.LenBefore() != doc.Length() || composed.LenAfter() != cs2.LenAfter() {
+		t.Errorf("Expected lengths %d -> %d, got %d -> %d",
+			doc.Length(), cs2.LenAfter(), composed.LenBefore(), composed.LenAfter())
+	}
+}
+
+// TestCompose_InsertThenDelete tests text inserted by the first changeset and
+// deleted by the second, and deletions on both sides
+func TestCompose_InsertThenDelete(t *testing.T) {
+	doc := New("abcdef")
+
+	// "abcdef" -> "aXYZdef" -> "aXf"
+	cs1 := NewChangeSet(doc.Length()).Retain(1).Delete(2).Insert("XYZ")
+	cs2 := NewChangeSet(cs1.LenAfter()).Retain(2).Delete(4)
+
+	composed := cs1.Compose(cs2)
+	result, err := composed.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result.String() != "aXf" {
+		t.Errorf("Expected %q, got %q", "aXf", result.String())
+	}
+
+	want := NewChangeSet(doc.Length()).Retain(1).Delete(4).Insert("X")
+	if !composed.Equal(want) {
+		t.Errorf("Expected %v, got %v", want.Normalize().operations, composed.Normalize().operations)
+	}
+}
+
+// randomChangeSet builds a random changeset over a document of length n.
+func randomChangeSet(rng *rand.Rand, n int) *ChangeSet {
+	const charset = "ab世界\n"
+	cs := NewChangeSet(n)
+	for pos := 0; pos < n || rng.Intn(3) == 0; {
+		switch k := rng.Intn(3); {
+		case k == 0:
+			runes := []rune(charset)
+			text := make([]rune, 1+rng.Intn(4))
+			for i := range text {
+				text[i] = runes[rng.Intn(len(runes))]
+			}
+			cs.Insert(string(text))
+		case pos == n:
+			return cs
+		case k == 1:
+			l := 1 + rng.Intn(n-pos)
+			cs.Retain(l)
+			pos += l
+		default:
+			l := 1 + rng.Intn(n-pos)
+			cs.Delete(l)
+			pos += l
+		}
+	}
+	return cs
 }
 
-// NOTE: The following tests have incorrect expectations for composition.
-// Compose(cs1, cs2) creates a changeset that applies cs1 THEN cs2, where cs2's operations
-// are based on the document state AFTER cs1, not the original concordia.
-// These tests expect cs2 to work on the original document, which is wrong.
-// The correct way to combine changesets that work on the same document is via Transform, not Compose.
+// TestCompose_EqualsSequentialApply tests that applying Compose(a, b) equals
+// applying a and then b, for random changesets
+func TestCompose_EqualsSequentialApply(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for iter := 0; iter < 500; iter++ {
+		doc := New(randomString(rng.Intn(30)))
+		a := randomChangeSet(rng, doc.Length())
+		afterA, err := a.Apply(doc)
+		if err != nil {
+			t.Fatalf("a.Apply failed: %v", err)
+		}
+		b := randomChangeSet(rng, afterA.Length())
+		want, err := b.Apply(afterA)
+		if err != nil {
+			t.Fatalf("b.Apply failed: %v", err)
+		}
+
+		composed := a.Compose(b)
+		if err := composed.Validate(); err != nil {
+			t.Fatalf("iteration %d: invalid composition: %v", iter, err)
+		}
+		got, err := composed.Apply(doc)
+		if err != nil {
+			t.Fatalf("iteration %d: Apply failed: %v", iter, err)
+		}
+		if got.String() != want.String() {
+			t.Fatalf("iteration %d: Compose(a, b).Apply(%q) = %q, want %q",
+				iter, doc.String(), got.String(), want.String())
+		}
+	}
+}
 
-// TestCompose_InsertInsert - DISABLED (incorrect expectations)
-// TestCompose_DeleteDelete - DISABLED (incorrect expectations)
-// TestCompose_Optimization - DISABLED (incorrect expectations)
+// NOTE: Compose(cs1, cs2) creates a changeset that applies cs1 THEN cs2, where cs2's
+// operations are based on the document state AFTER cs1, not the original document.
+// Changesets that both work on the same document are combined via Transform, not Compose.
 
 // TestCompose_Empty tests composition with empty changesets
 func TestCompose_Empty(t *testing.T) {
@@ -61,19 +61,13 @@ func cloneNode(node RopeNode) RopeNode {
 	}
 
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
 		// Leaf nodes are immutable, so we can share them
-		return leaf
+		return node
 	}
 
 	internal := node.(*InternalNode)
 	// Recursively clone children
-	return &InternalNode{
-		left:   cloneNode(internal.left),
-		right:  cloneNode(internal.right),
-		length: internal.length,
-		size:   internal.size,
-	}
+	return newInternalNode(cloneNode(internal.left), cloneNode(internal.right))
 }
 
 // ========== Optimized Rope with COW ==========
@@ -87,7 +81,7 @@ type CowRope struct {
 
 // NewCowRope creates a new COW rope.
 func NewCowRope(text string) *CowRope {
-	node := &LeafNode{text: text}
+	node := newLeafNode(text)
 	return &CowRope{
 		root:   NewCowNode(node),
 		length: utf8.RuneCountInString(text),
@@ -126,7 +120,7 @@ func nodeToString(node RopeNode) string {
 	}
 
 	if node.IsLeaf() {
-		return node.(*LeafNode).text
+		return asLeafNode(node).text
 	}
 
 	internal := node.(*InternalNode)
@@ -163,12 +157,12 @@ func (r *CowRope) Insert(pos int, text string) (*CowRope, error) {
 // cowInsert performs COW insertion.
 func cowInsert(node RopeNode, pos int, text string) RopeNode {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 		if pos == 0 {
-			return concatNodes(&LeafNode{text: text}, leaf)
+			return concatNodes(newLeafNode(text), leaf)
 		}
 		if pos == leaf.Length() {
-			return concatNodes(leaf, &LeafNode{text: text})
+			return concatNodes(leaf, newLeafNode(text))
 		}
 		return splitAndInsert(leaf, pos, text)
 	}
@@ -178,21 +172,11 @@ func cowInsert(node RopeNode, pos int, text string) RopeNode {
 
 	if pos <= leftLen {
 		newLeft := cowInsert(internal.left, pos, text)
-		return &InternalNode{
-			left:   newLeft,
-			right:  internal.right, // Share right subtree
-			length: newLeft.Length(),
-			size:   newLeft.Size(),
-		}
+		return newInternalNode(newLeft, internal.right)
 	}
 
 	newRight := cowInsert(internal.right, pos-leftLen, text)
-	return &InternalNode{
-		left:   internal.left, // Share left subtree
-		right:  newRight,
-		length: internal.left.Length(),
-		size:   internal.left.Size(),
-	}
+	return newInternalNode(internal.left, newRight)
 }
 
 // splitAndInsert splits leaf and inserts text.
@@ -202,8 +186,8 @@ func splitAndInsert(leaf *LeafNode, pos int, text string) RopeNode {
 	rightText := string(runes[pos:])
 
 	return concatNodes(
-		&LeafNode{text: leftText + text},
-		&LeafNode{text: rightText},
+		newLeafNode(leftText+text),
+		newLeafNode(rightText),
 	)
 }
 
@@ -241,10 +225,10 @@ func (r *CowRope) Delete(start, end int) (*CowRope, error) {
 // cowDelete performs COW deletion.
 func cowDelete(node RopeNode, start, end int) RopeNode {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 		runes := []rune(leaf.text)
 		newText := string(runes[:start]) + string(runes[end:])
-		return &LeafNode{text: newText}
+		return newLeafNode(newText)
 	}
 
 	internal := node.(*InternalNode)
@@ -255,12 +239,7 @@ func cowDelete(node RopeNode, start, end int) RopeNode {
 		if newLeft.Length() == 0 {
 			return internal.right
 		}
-		return &InternalNode{
-			left:   newLeft,
-			right:  internal.right,
-			length: newLeft.Length(),
-			size:   newLeft.Size(),
-		}
+		return newInternalNode(newLeft, internal.right)
 	}
 
 	if start >= leftLen {
@@ -268,12 +247,7 @@ func cowDelete(node RopeNode, start, end int) RopeNode {
 		if newRight.Length() == 0 {
 			return internal.left
 		}
-		return &InternalNode{
-			left:   internal.left,
-			right:  newRight,
-			length: internal.left.Length(),
-			size:   internal.left.Size(),
-		}
+		return newInternalNode(internal.left, newRight)
 	}
 
 	// Spans both subtrees
@@ -285,7 +259,7 @@ func cowDelete(node RopeNode, start, end int) RopeNode {
 // nodeSlice extracts substring from node.
 func nodeSlice(node RopeNode, start, end int) string {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 		runes := []rune(leaf.text)
 		return string(runes[start:end])
 	}
@@ -0,0 +1,209 @@
+package rope
+
+// ========== Edit Scripts ==========
+//
+// An EditScript is a recorded macro: a list of steps that move a cursor and
+// insert or delete text at it. Unlike a ChangeSet, which spells out every
+// position of one particular document, the steps are relative to the cursor
+// and to the lines around it, so a script can be replayed on any document.
+// Scripts serialize to JSON with encoding/json.
+
+// ScriptOp is the kind of an edit script step.
+type ScriptOp string
+
+const (
+	// ScriptMove moves the cursor to an anchor, plus an offset.
+	ScriptMove ScriptOp = "move"
+
+	// ScriptInsert inserts text at the cursor and moves the cursor after it.
+	ScriptInsert ScriptOp = "insert"
+
+	// ScriptDelete deletes Count characters after the cursor, or before it
+	// if Count is negative.
+	ScriptDelete ScriptOp = "delete"
+)
+
+// ScriptAnchor is a position a ScriptMove step moves the cursor to.
+type ScriptAnchor string
+
+const (
+	// AnchorCursor is the cursor itself, for moves by an offset only.
+	AnchorCursor ScriptAnchor = "cursor"
+
+	// AnchorDocStart is the start of the document.
+	AnchorDocStart ScriptAnchor = "doc-start"
+
+	// AnchorDocEnd is the end of the document.
+	AnchorDocEnd ScriptAnchor = "doc-end"
+
+	// AnchorLineStart is the start of the cursor's line.
+	AnchorLineStart ScriptAnchor = "line-start"
+
+	// AnchorLineEnd is the end of the cursor's line, before its line ending.
+	AnchorLineEnd ScriptAnchor = "line-end"
+)
+
+// ScriptStep is a single step of an EditScript.
+type ScriptStep struct {
+	Op     ScriptOp     `json:"op"`
+	To     ScriptAnchor `json:"to,omitempty"`     // ScriptMove: where to move
+	Offset int          `json:"offset,omitempty"` // ScriptMove: characters past To
+	Text   string       `json:"text,omitempty"`   // ScriptInsert: text to insert
+	Count  int          `json:"count,omitempty"`  // ScriptDelete: characters to delete
+}
+
+// EditScript records a sequence of cursor-relative edits for replaying on
+// any document.
+//
+// Example:
+//
+//	quote := rope.NewEditScript().
+//	    MoveTo(rope.AnchorLineStart).Insert(`"`).
+//	    MoveTo(rope.AnchorLineEnd).Insert(`"`).
+//	    ForEachLine()
+//	r2, _ := quote.Replay(rope.New("a\nb"))
+//	fmt.Println(r2.String()) // "\"a\"\n\"b\""
+type EditScript struct {
+	Steps []ScriptStep `json:"steps"`
+
+	// EachLine replays the steps once for every line, with the cursor
+	// starting at the line's start, instead of once at the document start.
+	EachLine bool `json:"eachLine,omitempty"`
+}
+
+// NewEditScript creates an empty edit script.
+func NewEditScript() *EditScript {
+	return &EditScript{}
+}
+
+// MoveTo records moving the cursor to an anchor.
+func (s *EditScript) MoveTo(anchor ScriptAnchor) *EditScript {
+	return s.record(ScriptStep{Op: ScriptMove, To: anchor})
+}
+
+// Move records moving the cursor by offset characters.
+func (s *EditScript) Move(offset int) *EditScript {
+	return s.record(ScriptStep{Op: ScriptMove, To: AnchorCursor, Offset: offset})
+}
+
+// Insert records inserting text at the cursor.
+func (s *EditScript) Insert(text string) *EditScript {
+	return s.record(ScriptStep{Op: ScriptInsert, Text: text})
+}
+
+// Delete records deleting count characters after the cursor, or before it
+// if count is negative.
+func (s *EditScript) Delete(count int) *EditScript {
+	return s.record(ScriptStep{Op: ScriptDelete, Count: count})
+}
+
+// ForEachLine makes the script replay once per line.
+func (s *EditScript) ForEachLine() *EditScript {
+	s.EachLine = true
+	return s
+}
+
+func (s *EditScript) record(step ScriptStep) *EditScript {
+	s.Steps = append(s.Steps, step)
+	return s
+}
+
+// Replay runs the script against r and returns the edited document.
+//
+// Moves and deletions are clamped to the document, so a script recorded on
+// one document never fails on a shorter one. With EachLine set, lines are
+// processed from last to first, so the edits on one line never shift the
+// lines still to be processed.
+func (s *EditScript) Replay(r *Rope) (*Rope, error) {
+	if r == nil {
+		r = Empty()
+	}
+	if !s.EachLine {
+		return s.run(r, 0)
+	}
+
+	var err error
+	for line := r.LineCount() - 1; line >= 0; line-- {
+		if r, err = s.run(r, r.LineStart(line)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// run replays the steps once with the cursor starting at cursor.
+func (s *EditScript) run(r *Rope, cursor int) (*Rope, error) {
+	var err error
+	for _, step := range s.Steps {
+		switch step.Op {
+		case ScriptMove:
+			base, err := scriptAnchorPos(r, cursor, step.To)
+			if err != nil {
+				return nil, err
+			}
+			cursor = min(max(base+step.Offset, 0), r.Length())
+		case ScriptInsert:
+			if r, err = r.Insert(cursor, step.Text); err != nil {
+				return nil, err
+			}
+			cursor += len([]rune(step.Text))
+		case ScriptDelete:
+			start, end := cursor, min(cursor+step.Count, r.Length())
+			if step.Count < 0 {
+				start, end = max(cursor+step.Count, 0), cursor
+			}
+			if r, err = r.Delete(start, end); err != nil {
+				return nil, err
+			}
+			cursor = start
+		default:
+			return nil, &ErrInvalidInput{
+				Parameter: "op",
+				Value:     step.Op,
+				Reason:    "unknown edit script operation",
+			}
+		}
+	}
+	return r, nil
+}
+
+// scriptAnchorPos returns the position of anchor for a cursor at cursor.
+func scriptAnchorPos(r *Rope, cursor int, anchor ScriptAnchor) (int, error) {
+	switch anchor {
+	case AnchorCursor, "":
+		return cursor, nil
+	case AnchorDocStart:
+		return 0, nil
+	case AnchorDocEnd:
+		return r.Length(), nil
+	}
+
+	// The line holding cursor is the number of line breaks before it
+	if r.Length() == 0 {
+		return 0, nil
+	}
+	line := lineBreaksBefore(r.root, cursor)
+	if line >= r.LineCount() {
+		return r.Length(), nil
+	}
+
+	switch anchor {
+	case AnchorLineStart:
+		return r.LineStart(line), nil
+	case AnchorLineEnd:
+		end, err := r.LineEnd(line)
+		if err != nil {
+			return 0, err
+		}
+		if ch, err := r.CharAt(end - 1); err == nil && end > r.LineStart(line) && ch == '\r' {
+			end--
+		}
+		return end, nil
+	default:
+		return 0, &ErrInvalidInput{
+			Parameter: "to",
+			Value:     anchor,
+			Reason:    "unknown edit script anchor",
+		}
+	}
+}
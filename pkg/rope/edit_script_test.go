@@ -0,0 +1,77 @@
+package rope
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quoteLines is the "wrap each line in quotes" macro.
+func quoteLines() *EditScript {
+	return NewEditScript().
+		MoveTo(AnchorLineStart).Insert(`"`).
+		MoveTo(AnchorLineEnd).Insert(`"`).
+		ForEachLine()
+}
+
+// TestEditScript_QuoteLines tests replaying one recorded script on different files
+func TestEditScript_QuoteLines(t *testing.T) {
+	script := quoteLines()
+
+	first, err := script.Replay(New("alpha\nbeta\ngamma\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "\"alpha\"\n\"beta\"\n\"gamma\"\n", first.String())
+
+	second, err := script.Replay(New("héllo wörld\r\n\r\nlast line"))
+	require.NoError(t, err)
+	assert.Equal(t, "\"héllo wörld\"\r\n\"\"\r\n\"last line\"", second.String())
+
+	empty, err := script.Replay(Empty())
+	require.NoError(t, err)
+	assert.Equal(t, "", empty.String())
+}
+
+// TestEditScript_JSON tests that a script survives a JSON round trip
+func TestEditScript_JSON(t *testing.T) {
+	data, err := json.Marshal(quoteLines())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"steps":[
+		{"op":"move","to":"line-start"},
+		{"op":"insert","text":"\""},
+		{"op":"move","to":"line-end"},
+		{"op":"insert","text":"\""}
+	],"eachLine":true}`, string(data))
+
+	var script EditScript
+	require.NoError(t, json.Unmarshal(data, &script))
+	result, err := script.Replay(New("x\ny"))
+	require.NoError(t, err)
+	assert.Equal(t, "\"x\"\n\"y\"", result.String())
+}
+
+// TestEditScript_MovesAndDeletes tests cursor moves and deletions, clamped to the document
+func TestEditScript_MovesAndDeletes(t *testing.T) {
+	script := NewEditScript().
+		MoveTo(AnchorDocEnd).Delete(-3).Insert("!").
+		MoveTo(AnchorDocStart).Move(2).Delete(100)
+
+	result, err := script.Replay(New("hello world"))
+	require.NoError(t, err)
+	assert.Equal(t, "he", result.String())
+
+	result, err = NewEditScript().Move(-5).Delete(-1).Insert("a").Replay(New("b"))
+	require.NoError(t, err)
+	assert.Equal(t, "ab", result.String())
+
+	// Trim the first two characters of every line
+	result, err = NewEditScript().Delete(2).ForEachLine().Replay(New("- one\n- two\nx"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", result.String())
+
+	_, err = (&EditScript{Steps: []ScriptStep{{Op: "jump"}}}).Replay(New("a"))
+	assert.Error(t, err)
+	_, err = NewEditScript().MoveTo("nowhere").Replay(New("a"))
+	assert.Error(t, err)
+}
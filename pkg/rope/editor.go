@@ -0,0 +1,193 @@
+package rope
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Editor is a mutable editing session over an immutable Rope.
+//
+// It holds the current document, its selection and an undo History.
+// Every edit replaces the document, records a revision and remaps the
+// selection, so callers never have to reassign ropes themselves.
+// Editor is not safe for concurrent use.
+//
+// Example:
+//
+//	ed := rope.NewEditor(rope.New(""))
+//	ed.Insert("hello")
+//	ed.Undo()
+//	fmt.Println(ed.String()) // ""
+type Editor struct {
+	doc       *Rope
+	history   *History
+	selection *Selection
+}
+
+// NewEditor creates an editor for r with the cursor at the start.
+func NewEditor(r *Rope) *Editor {
+	if r == nil {
+		r = Empty()
+	}
+	return &Editor{
+		doc:       r,
+		history:   NewHistory(),
+		selection: NewSelection(Point(0)),
+	}
+}
+
+// Rope returns the current document.
+func (e *Editor) Rope() *Rope {
+	return e.doc
+}
+
+// String returns the current document text.
+func (e *Editor) String() string {
+	return e.doc.String()
+}
+
+// Selection returns the current selection.
+func (e *Editor) Selection() *Selection {
+	return e.selection
+}
+
+// History returns the undo history of the session.
+func (e *Editor) History() *History {
+	return e.history
+}
+
+// Cursor returns the head of the primary selection range.
+func (e *Editor) Cursor() int {
+	return e.selection.Primary().Head
+}
+
+// SetSelection replaces the selection. Positions are clamped to the document.
+func (e *Editor) SetSelection(sel *Selection) {
+	ranges := make([]Range, sel.Len())
+	for i, rng := range sel.Iter() {
+		ranges[i] = rng.Clamp(e.doc.Length())
+	}
+	e.selection = NewSelectionWithPrimary(ranges, sel.PrimaryIndex())
+}
+
+// MoveCursor collapses the selection to a cursor at pos, clamped to the document.
+func (e *Editor) MoveCursor(pos int) {
+	e.selection = NewSelection(Point(pos).Clamp(e.doc.Length()))
+}
+
+// Insert replaces the text of every selection range with text, or inserts
+// it at every cursor, and leaves each cursor after its inserted text.
+// All ranges are edited atomically as a single revision.
+func (e *Editor) Insert(text string) error {
+	n := utf8.RuneCountInString(text)
+	return e.editRanges(func(rng Range, _ int) (int, int, string) {
+		return rng.From(), rng.To(), text
+	}, n)
+}
+
+// Delete removes n characters before every cursor, like Backspace. Ranges
+// that are not empty have their selected text removed instead. Deletions
+// stop at the document start and at the end of the previous range's edit.
+// All ranges are edited atomically as a single revision.
+func (e *Editor) Delete(n int) error {
+	if n < 0 {
+		return &ErrInvalidInput{
+			Parameter: "n",
+			Value:     n,
+			Reason:    "must not be negative",
+		}
+	}
+
+	return e.editRanges(func(rng Range, prevEnd int) (int, int, string) {
+		from, to := rng.From(), rng.To()
+		if rng.IsCursor() {
+			from = max(to-n, prevEnd)
+		}
+		return from, to, ""
+	}, 0)
+}
+
+// editRanges builds one ChangeSet replacing a region for every selection
+// range and applies it. edit returns the region and replacement for a
+// range, given the end of the previous region in document order. Each
+// range becomes a cursor textLen characters after the start of its region.
+func (e *Editor) editRanges(edit func(rng Range, prevEnd int) (int, int, string), textLen int) error {
+	ranges := e.selection.Iter()
+	order := make([]int, len(ranges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ranges[order[a]].From() < ranges[order[b]].From()
+	})
+
+	edits := make([]EditOperation, 0, len(ranges))
+	cursors := make([]Range, len(ranges))
+	prevEnd, shift := 0, 0
+	for _, idx := range order {
+		rng := ranges[idx].Clamp(e.doc.Length())
+		from, to, text := edit(rng, prevEnd)
+
+		// Overlapping ranges collapse onto the previous edit
+		from = max(from, prevEnd)
+		to = max(to, from)
+		if from < to || text != "" {
+			edits = append(edits, EditOperation{From: from, To: to, Text: text})
+		}
+		cursors[idx] = Point(from + shift + textLen)
+		shift += textLen - (to - from)
+		prevEnd = to
+	}
+
+	if len(edits) == 0 {
+		return nil
+	}
+	cs := changeSetFromEdits(e.doc.Length(), edits)
+	return e.apply(cs, NewSelectionWithPrimary(cursors, e.selection.PrimaryIndex()))
+}
+
+// Undo reverts the last revision. Returns false if there is nothing to undo.
+func (e *Editor) Undo() bool {
+	return e.step(e.history.Undo())
+}
+
+// Redo reapplies the last undone revision. Returns false if there is nothing to redo.
+func (e *Editor) Redo() bool {
+	return e.step(e.history.Redo())
+}
+
+// apply applies cs to the document, records it in the history and installs sel.
+func (e *Editor) apply(cs *ChangeSet, sel *Selection) error {
+	doc, err := cs.Apply(e.doc)
+	if err != nil {
+		return err
+	}
+	tx := NewTransaction(cs).WithSelection(sel)
+	if err := e.history.CommitRevision(tx, e.doc); err != nil {
+		return err
+	}
+	e.doc = doc
+	e.selection = sel
+	return nil
+}
+
+// step applies a transaction produced by the history. Transactions without
+// a selection remap the current selection through their changes.
+func (e *Editor) step(tx *Transaction) bool {
+	if tx == nil {
+		return false
+	}
+	doc, err := tx.Apply(e.doc)
+	if err != nil {
+		return false
+	}
+
+	sel := tx.Selection()
+	if sel == nil {
+		sel = mapSelection(e.selection, tx.Changes())
+	}
+
+	e.doc = doc
+	e.selection = sel
+	return true
+}
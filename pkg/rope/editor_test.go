@@ -0,0 +1,178 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEditor_TypeUndoRedo tests typing text, undoing and redoing with the cursor tracked
+func TestEditor_TypeUndoRedo(t *testing.T) {
+	ed := NewEditor(New(""))
+
+	for _, ch := range []string{"h", "e", "y"} {
+		require.NoError(t, ed.Insert(ch))
+	}
+	assert.Equal(t, "hey", ed.String())
+	assert.Equal(t, 3, ed.Cursor())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "he", ed.String())
+	assert.Equal(t, 2, ed.Cursor())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "h", ed.String())
+	assert.Equal(t, 1, ed.Cursor())
+
+	require.True(t, ed.Redo())
+	assert.Equal(t, "he", ed.String())
+	assert.Equal(t, 2, ed.Cursor())
+
+	require.True(t, ed.Redo())
+	assert.Equal(t, "hey", ed.String())
+	assert.Equal(t, 3, ed.Cursor())
+
+	assert.False(t, ed.Redo())
+}
+
+// TestEditor_MoveCursorAndInsert tests inserting in the middle of the document
+func TestEditor_MoveCursorAndInsert(t *testing.T) {
+	ed := NewEditor(New("hello world"))
+
+	ed.MoveCursor(5)
+	require.NoError(t, ed.Insert(","))
+	assert.Equal(t, "hello, world", ed.String())
+	assert.Equal(t, 6, ed.Cursor())
+
+	// Moving past the end clamps to the document length
+	ed.MoveCursor(100)
+	assert.Equal(t, 12, ed.Cursor())
+
+	// Undo remaps the cursor, which was moved to the end
+	require.True(t, ed.Undo())
+	assert.Equal(t, "hello world", ed.String())
+	assert.Equal(t, 11, ed.Cursor())
+}
+
+// TestEditor_Delete tests backspacing and undoing a deletion
+func TestEditor_Delete(t *testing.T) {
+	ed := NewEditor(New("abcdef"))
+	ed.MoveCursor(4)
+
+	require.NoError(t, ed.Delete(2))
+	assert.Equal(t, "abef", ed.String())
+	assert.Equal(t, 2, ed.Cursor())
+
+	// Deleting more than is available stops at the document start
+	require.NoError(t, ed.Delete(10))
+	assert.Equal(t, "ef", ed.String())
+	assert.Equal(t, 0, ed.Cursor())
+
+	// Nothing left before the cursor: no revision is recorded
+	require.NoError(t, ed.Delete(1))
+	assert.Equal(t, 2, ed.History().Len())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "abef", ed.String())
+	assert.Equal(t, 2, ed.Cursor())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "abcdef", ed.String())
+	assert.Equal(t, 4, ed.Cursor())
+
+	assert.False(t, ed.Undo())
+
+	var inputErr *ErrInvalidInput
+	assert.ErrorAs(t, ed.Delete(-1), &inputErr)
+}
+
+// TestEditor_ReplaceSelection tests that inserting and deleting act on a selection
+func TestEditor_ReplaceSelection(t *testing.T) {
+	ed := NewEditor(New("one two three"))
+
+	ed.SetSelection(NewSelection(NewRange(4, 7)))
+	require.NoError(t, ed.Insert("2"))
+	assert.Equal(t, "one 2 three", ed.String())
+	assert.Equal(t, 5, ed.Cursor())
+
+	ed.SetSelection(NewSelection(NewRange(5, 11)))
+	require.NoError(t, ed.Delete(1))
+	assert.Equal(t, "one 2", ed.String())
+	assert.Equal(t, 5, ed.Cursor())
+}
+
+// TestEditor_NewRevisionDropsRedo tests that editing after an undo discards the redo branch
+func TestEditor_NewRevisionDropsRedo(t *testing.T) {
+	ed := NewEditor(New(""))
+	require.NoError(t, ed.Insert("a"))
+	require.NoError(t, ed.Insert("b"))
+
+	require.True(t, ed.Undo())
+	require.NoError(t, ed.Insert("c"))
+	assert.Equal(t, "ac", ed.String())
+	assert.False(t, ed.History().CanRedo())
+	assert.Equal(t, 2, ed.History().Len())
+}
+
+// TestEditor_MultiCursorTypeAndUndo tests typing with three cursors and undoing once
+func TestEditor_MultiCursorTypeAndUndo(t *testing.T) {
+	ed := NewEditor(New("a\nb\nc"))
+	ed.SetSelection(NewSelectionWithPrimary([]Range{Point(1), Point(3), Point(5)}, 1))
+
+	for _, ch := range []string{"x", "y"} {
+		require.NoError(t, ed.Insert(ch))
+	}
+	assert.Equal(t, "axy\nbxy\ncxy", ed.String())
+	assert.Equal(t, []Range{Point(3), Point(7), Point(11)}, ed.Selection().Iter())
+	assert.Equal(t, 7, ed.Cursor())
+
+	// One undo reverts the last keystroke at every cursor
+	require.True(t, ed.Undo())
+	assert.Equal(t, "ax\nbx\ncx", ed.String())
+	assert.Equal(t, []Range{Point(2), Point(5), Point(8)}, ed.Selection().Iter())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "a\nb\nc", ed.String())
+	assert.Equal(t, []Range{Point(1), Point(3), Point(5)}, ed.Selection().Iter())
+	assert.Equal(t, 1, ed.Selection().PrimaryIndex())
+
+	require.True(t, ed.Redo())
+	assert.Equal(t, "ax\nbx\ncx", ed.String())
+	assert.Equal(t, []Range{Point(2), Point(5), Point(8)}, ed.Selection().Iter())
+}
+
+// TestEditor_MultiCursorWord tests typing a whole word at three cursors in one revision
+func TestEditor_MultiCursorWord(t *testing.T) {
+	ed := NewEditor(New("1 2 3"))
+	ed.SetSelection(NewSelection(Point(5), Point(0), Point(2)))
+
+	require.NoError(t, ed.Insert("go"))
+	assert.Equal(t, "go1 go2 3go", ed.String())
+	// Cursors keep their original order in the selection
+	assert.Equal(t, []Range{Point(11), Point(2), Point(6)}, ed.Selection().Iter())
+	assert.Equal(t, 1, ed.History().Len())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "1 2 3", ed.String())
+	assert.Equal(t, []Range{Point(5), Point(0), Point(2)}, ed.Selection().Iter())
+}
+
+// TestEditor_MultiCursorDelete tests backspacing at several cursors and selections
+func TestEditor_MultiCursorDelete(t *testing.T) {
+	ed := NewEditor(New("ab cd ef"))
+	ed.SetSelection(NewSelection(Point(2), NewRange(3, 5), Point(8)))
+
+	require.NoError(t, ed.Delete(1))
+	assert.Equal(t, "a  e", ed.String())
+	assert.Equal(t, []Range{Point(1), Point(2), Point(4)}, ed.Selection().Iter())
+
+	// Overlapping deletions stop at the previous cursor's edit
+	ed.SetSelection(NewSelection(Point(1), Point(2)))
+	require.NoError(t, ed.Delete(5))
+	assert.Equal(t, " e", ed.String())
+	assert.Equal(t, []Range{Point(0), Point(0)}, ed.Selection().Iter())
+
+	require.True(t, ed.Undo())
+	assert.Equal(t, "a  e", ed.String())
+}
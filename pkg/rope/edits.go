@@ -1,5 +1,9 @@
 package rope
 
+import (
+	"sort"
+)
+
 // EditOperation represents a single edit operation with (from, to, replacement).
 type EditOperation struct {
 	From int
@@ -12,3 +16,85 @@ type Deletion struct {
 	From int
 	To   int
 }
+
+// changeSetFromEdits builds a ChangeSet for a document of length lenBefore
+// from edits that are sorted by position and do not overlap.
+// Positions are character positions in the original document.
+func changeSetFromEdits(lenBefore int, edits []EditOperation) *ChangeSet {
+	cs := NewChangeSet(lenBefore)
+	pos := 0
+	for _, e := range edits {
+		if e.From > pos {
+			cs.Retain(e.From - pos)
+		}
+		if e.To > e.From {
+			cs.Delete(e.To - e.From)
+		}
+		if e.Text != "" {
+			cs.Insert(e.Text)
+		}
+		pos = e.To
+	}
+	if pos < lenBefore {
+		cs.Retain(lenBefore - pos)
+	}
+	return cs
+}
+
+// ChangeSetFromEdits builds the ChangeSet that makes edits to a document
+// of length lenBefore. Each edit replaces the characters [From, To) of the
+// original document with Text; positions are absolute, so there are no
+// retain lengths to get wrong. Edits may come in any order but must not
+// overlap. Insertions at the same position keep their relative order.
+//
+// Example:
+//
+//	cs, _ := rope.ChangeSetFromEdits(11, []rope.EditOperation{
+//	    {From: 6, To: 11, Text: "there"},
+//	    {From: 0, To: 0, Text: "> "},
+//	})
+//	r, _ := cs.Apply(rope.New("hello world"))
+//	fmt.Println(r.String()) // "> hello there"
+func ChangeSetFromEdits(lenBefore int, edits []EditOperation) (*ChangeSet, error) {
+	sorted := make([]EditOperation, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		return a.From < b.From || a.From == b.From && a.To < b.To
+	})
+
+	pos := 0
+	for _, e := range sorted {
+		if e.From < 0 || e.To < e.From || e.To > lenBefore {
+			return nil, &ErrInvalidRange{
+				Operation: "ChangeSetFromEdits",
+				Start:     e.From,
+				End:       e.To,
+				ValidMax:  lenBefore,
+			}
+		}
+		if e.From < pos {
+			return nil, &ErrInvalidInput{
+				Parameter: "edits",
+				Value:     e,
+				Reason:    "overlaps another edit",
+			}
+		}
+		pos = e.To
+	}
+	return changeSetFromEdits(lenBefore, sorted), nil
+}
+
+// applyEdits applies sorted, non-overlapping edits to the rope and returns
+// the edited rope together with the ChangeSet that describes the edits.
+func (r *Rope) applyEdits(edits []EditOperation) (*Rope, *ChangeSet, error) {
+	cs := changeSetFromEdits(r.Length(), edits)
+	if len(edits) == 0 {
+		return r, cs, nil
+	}
+	result, err := cs.Apply(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, cs, nil
+}
@@ -68,6 +68,26 @@ func (e *ErrInvalidInput) Error() string {
 	return fmt.Sprintf("invalid parameter %s: %s (%v)", e.Parameter, e.Reason, e.Value)
 }
 
+// ErrInvalidUTF8 is returned when text that must be valid UTF-8 is not.
+type ErrInvalidUTF8 struct {
+	// Offset is the byte offset of the first invalid sequence
+	Offset int
+}
+
+func (e *ErrInvalidUTF8) Error() string {
+	return fmt.Sprintf("invalid UTF-8 at byte offset %d", e.Offset)
+}
+
+// ErrPatchFailed is returned when some hunks of a patch could not be applied.
+type ErrPatchFailed struct {
+	// Hunks holds the indexes of the hunks that were not applied
+	Hunks []int
+}
+
+func (e *ErrPatchFailed) Error() string {
+	return fmt.Sprintf("patch: %d hunk(s) failed to apply: %v", len(e.Hunks), e.Hunks)
+}
+
 // Helper functions to create common errors
 
 func errSliceOutOfBounds(start, end, max int) error {
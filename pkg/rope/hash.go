@@ -1,7 +1,11 @@
 package rope
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"hash/fnv"
+	"io"
 )
 
 // ========== Hash Support ==========
@@ -95,6 +99,37 @@ func CombineHash64(codes ...uint64) uint64 {
 	return result
 }
 
+// ========== Content Hashing ==========
+
+// ContentHash returns the SHA-256 digest of the rope's UTF-8 content.
+//
+// The definition is stable across versions and independent of the tree
+// shape: it is exactly sha256.Sum256([]byte(r.String())), with no seed,
+// prefix or length framing, so it can be stored on disk and used for
+// content addressing, deduplication and cache keys. The content is
+// streamed leaf by leaf rather than materialized.
+//
+// Use HashCode64 when a fast, non-cryptographic hash is enough.
+func (r *Rope) ContentHash() [32]byte {
+	h := sha256.New()
+	if r != nil {
+		forEachLeaf(r.root, func(text string) bool {
+			io.WriteString(h, text)
+			return true
+		})
+	}
+
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// ContentHashHex returns ContentHash as a lowercase hexadecimal string.
+func (r *Rope) ContentHashHex() string {
+	sum := r.ContentHash()
+	return hex.EncodeToString(sum[:])
+}
+
 // ========== Chunk-based Hashing ==========
 
 // ChunkHashes returns hash codes for each chunk in the rope.
@@ -117,6 +152,33 @@ func (r *Rope) ChunkHashes() []uint32 {
 	return hashes
 }
 
+// LineHashes returns a hash code for each line, computed in one pass.
+// A line's hash covers its text without the line ending and equals the
+// HashCode32 of a rope holding just that text, so an editor can cache the
+// hashes and compare them after an edit to find the lines that changed.
+//
+// Example:
+//
+//	old := r.LineHashes()
+//	r2, _ := r.Insert(pos, "x")
+//	for i, h := range r2.LineHashes() {
+//	    if i >= len(old) || h != old[i] {
+//	        rehighlight(i)
+//	    }
+//	}
+func (r *Rope) LineHashes() []uint32 {
+	var hashes []uint32
+	r.ForEachLine(func(_ int, line string) bool {
+		hash := uint32(0)
+		if line != "" {
+			hash = HashString(line)
+		}
+		hashes = append(hashes, hash)
+		return true
+	})
+	return hashes
+}
+
 // CombinedChunkHash combines all chunk hashes into a single hash.
 func (r *Rope) CombinedChunkHash() uint32 {
 	hashes := r.ChunkHashes()
@@ -213,6 +275,41 @@ func (r *Rope) HashKey() uint32 {
 	return r.HashCode()
 }
 
+// CacheKey returns a 64-bit key identifying the rope's content, for caching
+// expensive per-document results such as parse trees in a map.
+//
+// The key is the first 8 bytes of ContentHash, so it is independent of the
+// tree shape, stable across versions and collision-resistant enough to use
+// as a map key without comparing content. It is computed on the first call
+// and remembered by the rope; edited ropes compute their own.
+//
+// Example:
+//
+//	if tree, ok := parsed[r.CacheKey()]; ok {
+//	    return tree
+//	}
+func (r *Rope) CacheKey() uint64 {
+	if r == nil {
+		return Empty().CacheKey()
+	}
+
+	caches := r.lazyCaches()
+	caches.mu.Lock()
+	key, ok := caches.cacheKey, caches.hasCacheKey
+	caches.mu.Unlock()
+	if ok {
+		return key
+	}
+
+	sum := r.ContentHash()
+	key = binary.BigEndian.Uint64(sum[:8])
+
+	caches.mu.Lock()
+	caches.cacheKey, caches.hasCacheKey = key, true
+	caches.mu.Unlock()
+	return key
+}
+
 // ========== Hash Set Utilities ==========
 
 // HashSlice returns a slice of hash codes for a slice of ropes.
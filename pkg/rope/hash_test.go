@@ -1,9 +1,15 @@
 package rope
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestHash_Consistency verifies that ropes with same content
@@ -689,3 +695,113 @@ func TestHash_NilRope(t *testing.T) {
 	assert.Equal(t, "0", r.HashString())
 	assert.Equal(t, uint32(0), r.HashKey())
 }
+
+// TestContentHash_MatchesSHA256 tests that ContentHash is the SHA-256 of the content
+func TestContentHash_MatchesSHA256(t *testing.T) {
+	texts := []string{
+		"",
+		"Hello, World!",
+		"héllo 世界 🎉\nsecond line\r\n",
+		strings.Repeat("The quick brown fox 狐狸 jumps.\n", 500),
+	}
+
+	for _, text := range texts {
+		want := sha256.Sum256([]byte(text))
+		for _, r := range []*Rope{New(text), chunkedRope(text, 7)} {
+			assert.Equal(t, want, r.ContentHash())
+			assert.Equal(t, hex.EncodeToString(want[:]), r.ContentHashHex())
+		}
+	}
+}
+
+// TestContentHash_KnownValue tests the hash against a fixed digest
+func TestContentHash_KnownValue(t *testing.T) {
+	// The definition must never change: stored hashes depend on it
+	assert.Equal(t,
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		Empty().ContentHashHex())
+	assert.Equal(t,
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		New("hello").ContentHashHex())
+
+	var nilRope *Rope
+	assert.Equal(t, Empty().ContentHash(), nilRope.ContentHash())
+}
+
+// TestHash_CacheKey tests that cache keys follow content, not tree shape
+func TestHash_CacheKey(t *testing.T) {
+	text := strings.Repeat("func main() {}\n", 500)
+	single := New(text)
+	chunked := chunkedRope(text, 7)
+	built, err := New(text[:100]).Insert(100, text[100:])
+	require.NoError(t, err)
+
+	key := single.CacheKey()
+	assert.Equal(t, key, chunked.CacheKey())
+	assert.Equal(t, key, built.CacheKey())
+	assert.Equal(t, key, single.CacheKey(), "the remembered key is stable")
+
+	sum := sha256.Sum256([]byte(text))
+	assert.Equal(t, binary.BigEndian.Uint64(sum[:8]), key)
+
+	edited, err := single.Insert(0, "x")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, edited.CacheKey())
+	assert.NotEqual(t, New("a").CacheKey(), New("b").CacheKey())
+	assert.Equal(t, Empty().CacheKey(), (*Rope)(nil).CacheKey())
+
+	cache := map[uint64]string{key: "parsed"}
+	assert.Equal(t, "parsed", cache[chunked.CacheKey()])
+}
+
+// TestHash_CacheKeyConcurrent tests filling the lazy caches while the rope is cloned
+func TestHash_CacheKeyConcurrent(t *testing.T) {
+	r := New(strings.Repeat("héllo wörld\n", 1000))
+	want := New(r.String()).CacheKey()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, want, r.CacheKey())
+			r.GraphemeLength()
+			r.GetBytePosCached(5000)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, want, r.WithLineEnding(LineEndingCRLF).CacheKey())
+			r.WithCharClassifier(DefaultCharClass).GraphemeLength()
+		}()
+	}
+	wg.Wait()
+}
+
+// TestHash_LineHashes tests that an edit changes only the hash of its line
+func TestHash_LineHashes(t *testing.T) {
+	lines := []string{"package main", "", "import \"fmt\"", "func main() {", "\tfmt.Println(\"hi\")", "}"}
+	text := strings.Join(lines, "\n")
+
+	hashes := New(text).LineHashes()
+	require.Len(t, hashes, len(lines))
+	assert.Equal(t, hashes, chunkedRope(text, 5).LineHashes())
+	for i, line := range lines {
+		assert.Equal(t, New(line).HashCode32(), hashes[i], "line %d", i)
+	}
+
+	// Edit line 3
+	r := New(text)
+	edited, err := r.Insert(r.LineStart(3)+4, "tion")
+	require.NoError(t, err)
+	after := edited.LineHashes()
+	require.Len(t, after, len(hashes))
+	for i := range hashes {
+		if i == 3 {
+			assert.NotEqual(t, hashes[i], after[i])
+		} else {
+			assert.Equal(t, hashes[i], after[i], "line %d", i)
+		}
+	}
+
+	assert.Empty(t, Empty().LineHashes())
+}
@@ -0,0 +1,242 @@
+package rope
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// revision is a single entry in the undo history.
+type revision struct {
+	transaction *Transaction // Redoes the revision
+	inversion   *Transaction // Undoes the revision
+	timestamp   time.Time
+}
+
+// History is a linear undo/redo history of transactions.
+//
+// Committing a revision after undoing discards the revisions that could
+// have been redone. History is not safe for concurrent use.
+type History struct {
+	revisions []revision
+	current   int // Number of revisions currently applied
+}
+
+// NewHistory creates an empty history.
+func NewHistory() *History {
+	return &History{}
+}
+
+// CommitRevision records a transaction that was applied to original.
+// The inverse is computed from original, so original must be the document
+// before the transaction.
+func (h *History) CommitRevision(tx *Transaction, original *Rope) error {
+	inversion, err := tx.Invert(original)
+	if err != nil {
+		return err
+	}
+
+	h.revisions = append(h.revisions[:h.current], revision{
+		transaction: tx,
+		inversion:   inversion,
+		timestamp:   time.Now(),
+	})
+	h.current = len(h.revisions)
+	return nil
+}
+
+// CommitRevisionCoalesced records a transaction like CommitRevision, but
+// merges it into the latest revision when policy allows, so that a single
+// undo reverts both. Only the newest revision is merged into, and never
+// after an undo. The merged revision keeps the new transaction's selection
+// and the time of the latest edit.
+func (h *History) CommitRevisionCoalesced(tx *Transaction, original *Rope, policy CoalescePolicy) error {
+	if policy == nil || h.current == 0 || h.current != len(h.revisions) {
+		return h.CommitRevision(tx, original)
+	}
+
+	prev := &h.revisions[h.current-1]
+	if !policy.ShouldCoalesce(prev.transaction, tx, time.Since(prev.timestamp)) {
+		return h.CommitRevision(tx, original)
+	}
+
+	// Rebuild the merged edit from the documents on either side of it
+	before, err := prev.inversion.Apply(original)
+	if err != nil {
+		return err
+	}
+	after, err := tx.Apply(original)
+	if err != nil {
+		return err
+	}
+	merged := NewTransaction(Diff(before, after)).WithSelection(tx.selection)
+	inversion, err := merged.Invert(before)
+	if err != nil {
+		return err
+	}
+
+	*prev = revision{
+		transaction: merged,
+		inversion:   inversion,
+		timestamp:   time.Now(),
+	}
+	return nil
+}
+
+// Undo steps back one revision and returns the transaction that reverts it,
+// or nil if there is nothing to undo.
+func (h *History) Undo() *Transaction {
+	if !h.CanUndo() {
+		return nil
+	}
+	h.current--
+	return h.revisions[h.current].inversion
+}
+
+// Redo steps forward one revision and returns the transaction that
+// reapplies it, or nil if there is nothing to redo.
+func (h *History) Redo() *Transaction {
+	if !h.CanRedo() {
+		return nil
+	}
+	tx := h.revisions[h.current].transaction
+	h.current++
+	return tx
+}
+
+// CanUndo returns true if there is a revision to undo.
+func (h *History) CanUndo() bool {
+	return h.current > 0
+}
+
+// CanRedo returns true if there is a revision to redo.
+func (h *History) CanRedo() bool {
+	return h.current < len(h.revisions)
+}
+
+// Len returns the number of recorded revisions, including undone ones.
+func (h *History) Len() int {
+	return len(h.revisions)
+}
+
+// CurrentRevision returns the number of revisions currently applied.
+func (h *History) CurrentRevision() int {
+	return h.current
+}
+
+// ========== Undo Coalescing ==========
+
+// CoalescePolicy decides whether a new transaction is merged into the
+// previous revision instead of becoming its own undo step.
+type CoalescePolicy interface {
+	// ShouldCoalesce reports whether next, committed dt after prev, should
+	// be merged into prev.
+	ShouldCoalesce(prev, next *Transaction, dt time.Duration) bool
+}
+
+// DefaultCoalesceWindow is the time window used by DefaultCoalescePolicy.
+const DefaultCoalesceWindow = time.Second
+
+// DefaultCoalescePolicy coalesces edits the way most editors group typing:
+// a run of single-character insertions, each right after the text typed
+// before it, becomes one undo step, as does a run of adjacent deletions
+// (Backspace or Delete key).
+//
+// Typing continues any previous insertion without line breaks, since a run
+// already merged looks the same as a paste. So typing over a selection or
+// right after a paste joins that edit, and one undo removes both. A paste
+// or other multi-character insertion never joins the edit before it.
+// Insertions and deletions never merge with each other, nor do line
+// breaks, multi-cursor edits or edits further apart than Window.
+type DefaultCoalescePolicy struct {
+	Window time.Duration
+}
+
+// NewDefaultCoalescePolicy creates a policy with DefaultCoalesceWindow.
+func NewDefaultCoalescePolicy() *DefaultCoalescePolicy {
+	return &DefaultCoalescePolicy{Window: DefaultCoalesceWindow}
+}
+
+// ShouldCoalesce implements CoalescePolicy.
+func (p *DefaultCoalescePolicy) ShouldCoalesce(prev, next *Transaction, dt time.Duration) bool {
+	if dt > p.Window {
+		return false
+	}
+
+	a, ok := singleEdit(prev.Changes())
+	if !ok {
+		return false
+	}
+	b, ok := singleEdit(next.Changes())
+	if !ok {
+		return false
+	}
+
+	switch {
+	case a.insertsText() && b.isTyping():
+		// The next character follows the previously typed text
+		return b.pos == a.pos+a.inserted
+	case a.isDeletion() && b.isDeletion():
+		// Backspace ends where the last deletion started; Delete starts there
+		return b.pos+b.deleted == a.pos || b.pos == a.pos
+	default:
+		return false
+	}
+}
+
+// editSpan describes a changeset that makes a single contiguous edit.
+type editSpan struct {
+	pos      int    // Position of the edit in the original document
+	deleted  int    // Number of characters deleted
+	inserted int    // Number of characters inserted
+	text     string // Inserted text
+}
+
+// isTyping reports whether the edit inserts one character other than a
+// line break, as a keystroke does.
+func (e editSpan) isTyping() bool {
+	return e.deleted == 0 && e.inserted == 1 && e.text != "\n"
+}
+
+// insertsText reports whether the edit inserts text without line breaks,
+// possibly replacing a selection.
+func (e editSpan) insertsText() bool {
+	return e.inserted > 0 && !strings.Contains(e.text, "\n")
+}
+
+// isDeletion reports whether the edit only deletes.
+func (e editSpan) isDeletion() bool {
+	return e.deleted > 0 && e.inserted == 0
+}
+
+// singleEdit returns the edit made by cs, or false if cs makes no edit or
+// edits more than one place.
+func singleEdit(cs *ChangeSet) (editSpan, bool) {
+	var span editSpan
+	pos, found, done := 0, false, false
+	for _, op := range cs.operations {
+		switch op.OpType {
+		case OpRetain:
+			if found {
+				done = true
+			}
+			pos += op.Length
+		case OpDelete, OpInsert:
+			if done {
+				return editSpan{}, false
+			}
+			if !found {
+				span.pos = pos
+				found = true
+			}
+			if op.OpType == OpDelete {
+				span.deleted += op.Length
+				pos += op.Length
+			} else {
+				span.inserted += utf8.RuneCountInString(op.Text)
+				span.text += op.Text
+			}
+		}
+	}
+	return span, found
+}
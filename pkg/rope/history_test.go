@@ -0,0 +1,148 @@
+package rope
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertsOnlyPolicy coalesces insertions and breaks on anything else.
+type insertsOnlyPolicy struct{}
+
+func (insertsOnlyPolicy) ShouldCoalesce(prev, next *Transaction, dt time.Duration) bool {
+	a, okA := singleEdit(prev.Changes())
+	b, okB := singleEdit(next.Changes())
+	return okA && okB && a.deleted == 0 && b.deleted == 0
+}
+
+// commitEdit applies cs to doc and records it with the given policy.
+func commitEdit(t *testing.T, h *History, doc *Rope, cs *ChangeSet, policy CoalescePolicy) *Rope {
+	result, err := cs.Apply(doc)
+	require.NoError(t, err)
+	require.NoError(t, h.CommitRevisionCoalesced(NewTransaction(cs), doc, policy))
+	return result
+}
+
+// undo applies the history's undo transaction to doc.
+func undo(t *testing.T, h *History, doc *Rope) *Rope {
+	tx := h.Undo()
+	require.NotNil(t, tx)
+	result, err := tx.Apply(doc)
+	require.NoError(t, err)
+	return result
+}
+
+// TestHistory_CoalesceInsertsBreakOnDelete tests undo granularity with a custom policy
+func TestHistory_CoalesceInsertsBreakOnDelete(t *testing.T) {
+	h := NewHistory()
+	doc := New("")
+	policy := insertsOnlyPolicy{}
+
+	for i, ch := range []string{"h", "e", "l", "l", "o"} {
+		doc = commitEdit(t, h, doc, NewChangeSet(i).Retain(i).Insert(ch), policy)
+	}
+	assert.Equal(t, "hello", doc.String())
+	assert.Equal(t, 1, h.Len())
+
+	// A delete starts a new revision, and the next insert another one
+	doc = commitEdit(t, h, doc, NewChangeSet(5).Retain(4).Delete(1), policy)
+	doc = commitEdit(t, h, doc, NewChangeSet(4).Retain(4).Insert("p"), policy)
+	doc = commitEdit(t, h, doc, NewChangeSet(5).Retain(5).Insert("!"), policy)
+	assert.Equal(t, "hellp!", doc.String())
+	assert.Equal(t, 3, h.Len())
+
+	doc = undo(t, h, doc)
+	assert.Equal(t, "hell", doc.String())
+	doc = undo(t, h, doc)
+	assert.Equal(t, "hello", doc.String())
+	doc = undo(t, h, doc)
+	assert.Equal(t, "", doc.String())
+	assert.False(t, h.CanUndo())
+
+	// Redo replays the merged typing run in one step
+	tx := h.Redo()
+	require.NotNil(t, tx)
+	doc, err := tx.Apply(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", doc.String())
+}
+
+// TestHistory_CoalesceNotAfterUndo tests that an edit after undo never merges
+func TestHistory_CoalesceNotAfterUndo(t *testing.T) {
+	h := NewHistory()
+	doc := New("")
+	policy := insertsOnlyPolicy{}
+
+	doc = commitEdit(t, h, doc, NewChangeSet(0).Insert("a"), policy)
+	doc = commitEdit(t, h, doc, NewChangeSet(1).Retain(1).Insert("b"), policy)
+	doc = commitEdit(t, h, doc, NewChangeSet(2).Retain(2).Insert("\n"), nil)
+	doc = undo(t, h, doc)
+
+	doc = commitEdit(t, h, doc, NewChangeSet(2).Retain(2).Insert("c"), policy)
+	assert.Equal(t, "abc", doc.String())
+	assert.Equal(t, 2, h.Len())
+
+	doc = undo(t, h, doc)
+	assert.Equal(t, "ab", doc.String())
+}
+
+// TestDefaultCoalescePolicy tests the built-in typing and deletion rules
+func TestDefaultCoalescePolicy(t *testing.T) {
+	p := NewDefaultCoalescePolicy()
+	tx := func(cs *ChangeSet) *Transaction { return NewTransaction(cs) }
+
+	typedAB := tx(NewChangeSet(3).Retain(1).Insert("ab").Retain(2))
+	typeC := tx(NewChangeSet(5).Retain(3).Insert("c").Retain(2))
+	assert.True(t, p.ShouldCoalesce(typedAB, typeC, 10*time.Millisecond))
+
+	// Too slow, not adjacent, a paste, or a line break
+	assert.False(t, p.ShouldCoalesce(typedAB, typeC, 2*time.Second))
+	assert.False(t, p.ShouldCoalesce(typedAB, tx(NewChangeSet(5).Retain(4).Insert("c").Retain(1)), 0))
+	assert.False(t, p.ShouldCoalesce(typedAB, tx(NewChangeSet(5).Retain(3).Insert("cd").Retain(2)), 0))
+	assert.False(t, p.ShouldCoalesce(typedAB, tx(NewChangeSet(5).Retain(3).Insert("\n").Retain(2)), 0))
+
+	// Backspace and Delete runs coalesce, but not with typing
+	backspace := tx(NewChangeSet(5).Retain(3).Delete(1).Retain(1))
+	assert.True(t, p.ShouldCoalesce(backspace, tx(NewChangeSet(4).Retain(2).Delete(1).Retain(1)), 0))
+	assert.True(t, p.ShouldCoalesce(backspace, tx(NewChangeSet(4).Retain(3).Delete(1)), 0))
+	assert.False(t, p.ShouldCoalesce(typedAB, backspace, 0))
+	assert.False(t, p.ShouldCoalesce(backspace, typeC, 0))
+
+	// Multi-cursor edits are never merged
+	multi := tx(NewChangeSet(5).Insert("x").Retain(2).Insert("x").Retain(3))
+	assert.False(t, p.ShouldCoalesce(typedAB, multi, 0))
+}
+
+// TestCommit tests applying a changeset, remapping the selection and recording history at once
+func TestCommit(t *testing.T) {
+	h := NewHistory()
+	doc := New("hello world")
+	sel := NewSelectionWithPrimary([]Range{Point(5), NewRange(6, 11)}, 1)
+
+	cs := NewChangeSet(doc.Length()).Retain(5).Insert(",").Retain(6)
+	edited, mapped, err := Commit(doc, cs, sel, h)
+	require.NoError(t, err)
+	assert.Equal(t, "hello, world", edited.String())
+	assert.Equal(t, []Range{Point(6), NewRange(7, 12)}, mapped.Iter())
+	assert.Equal(t, 1, mapped.PrimaryIndex())
+	assert.Equal(t, "hello world", doc.String())
+
+	// The revision can be undone and redone with its selection
+	assert.Equal(t, "hello world", undo(t, h, edited).String())
+	redo := h.Redo()
+	require.NotNil(t, redo)
+	assert.Equal(t, mapped, redo.Selection())
+
+	// A changeset that does not fit the document leaves the history alone
+	_, _, err = Commit(doc, NewChangeSet(3).Retain(3), sel, h)
+	assert.Error(t, err)
+	assert.Equal(t, 1, h.Len())
+
+	// Selection and history are optional
+	edited, mapped, err = Commit(doc, cs, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "hello, world", edited.String())
+	assert.Nil(t, mapped)
+}
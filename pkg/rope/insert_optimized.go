@@ -32,28 +32,20 @@ func (r *Rope) InsertOptimized(pos int, text string) (*Rope, error) {
 	}
 
 	newRoot := insertNodeOptimized(r.root, pos, text)
-	return &Rope{
-		root:   newRoot,
-		length: r.length + utf8.RuneCountInString(text),
-		size:   r.size + len(text),
-	}, nil
+	return r.derive(newRoot, r.length+utf8.RuneCountInString(text), r.size+len(text)), nil
 }
 
 // insertNodeOptimized performs optimized insertion.
 func insertNodeOptimized(node RopeNode, pos int, text string) RopeNode {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 
 		// Optimized: use byte operations instead of rune[] conversion
 		oldText := leaf.text
 		oldBytes := []byte(oldText)
 
 		// Find byte position
-		bytePos := 0
-		for i := 0; i < pos; i++ {
-			_, size := utf8.DecodeRune(oldBytes[bytePos:])
-			bytePos += size
-		}
+		bytePos := leaf.bytePos(pos)
 
 		// Create new text with insertion
 		newText := make([]byte, 0, len(oldBytes)+len(text))
@@ -61,7 +53,7 @@ func insertNodeOptimized(node RopeNode, pos int, text string) RopeNode {
 		newText = append(newText, text...)
 		newText = append(newText, oldBytes[bytePos:]...)
 
-		return &LeafNode{text: string(newText)}
+		return newLeafNode(string(newText))
 	}
 
 	// Internal node
@@ -71,22 +63,12 @@ func insertNodeOptimized(node RopeNode, pos int, text string) RopeNode {
 	if pos <= leftLen {
 		// Insert into left subtree
 		newLeft := insertNodeOptimized(internal.left, pos, text)
-		return &InternalNode{
-			left:   newLeft,
-			right:  internal.right,
-			length: newLeft.Length(),
-			size:   newLeft.Size(),
-		}
+		return newInternalNode(newLeft, internal.right)
 	}
 
 	// Insert into right subtree
 	newRight := insertNodeOptimized(internal.right, pos-leftLen, text)
-	return &InternalNode{
-		left:   internal.left,
-		right:  newRight,
-		length: internal.left.Length(),
-		size:   internal.left.Size(),
-	}
+	return newInternalNode(internal.left, newRight)
 }
 
 // DeleteOptimized removes characters from start to end (exclusive).
@@ -114,41 +96,28 @@ func (r *Rope) DeleteOptimized(start, end int) (*Rope, error) {
 	}
 
 	newRoot := deleteNodeOptimized(r.root, start, end)
-	return &Rope{
-		root:   newRoot,
-		length: r.length - utf8.RuneCountInString(deletedText),
-		size:   r.size - len(deletedText),
-	}, nil
+	return r.derive(newRoot, r.length-utf8.RuneCountInString(deletedText), r.size-len(deletedText)), nil
 }
 
 // deleteNodeOptimized performs optimized deletion.
 func deleteNodeOptimized(node RopeNode, start, end int) RopeNode {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
+		leaf := asLeafNode(node)
 
 		// Optimized: use byte operations
 		oldText := leaf.text
 		oldBytes := []byte(oldText)
 
 		// Find byte positions
-		startByte := 0
-		for i := 0; i < start; i++ {
-			_, size := utf8.DecodeRune(oldBytes[startByte:])
-			startByte += size
-		}
-
-		endByte := startByte
-		for i := start; i < end; i++ {
-			_, size := utf8.DecodeRune(oldBytes[endByte:])
-			endByte += size
-		}
+		startByte := leaf.bytePos(start)
+		endByte := leaf.bytePos(end)
 
 		// Create new text without deleted range
 		newText := make([]byte, 0, len(oldBytes)-(endByte-startByte))
 		newText = append(newText, oldBytes[:startByte]...)
 		newText = append(newText, oldBytes[endByte:]...)
 
-		return &LeafNode{text: string(newText)}
+		return newLeafNode(string(newText))
 	}
 
 	// Internal node
@@ -158,23 +127,13 @@ func deleteNodeOptimized(node RopeNode, start, end int) RopeNode {
 	// Entirely in left subtree
 	if end <= leftLen {
 		newLeft := deleteNodeOptimized(internal.left, start, end)
-		return &InternalNode{
-			left:   newLeft,
-			right:  internal.right,
-			length: newLeft.Length(),
-			size:   newLeft.Size(),
-		}
+		return newInternalNode(newLeft, internal.right)
 	}
 
 	// Entirely in right subtree
 	if start >= leftLen {
 		newRight := deleteNodeOptimized(internal.right, start-leftLen, end-leftLen)
-		return &InternalNode{
-			left:   internal.left,
-			right:  newRight,
-			length: internal.left.Length(),
-			size:   internal.left.Size(),
-		}
+		return newInternalNode(internal.left, newRight)
 	}
 
 	// Spans both subtrees - need to split and merge
@@ -182,12 +141,7 @@ func deleteNodeOptimized(node RopeNode, start, end int) RopeNode {
 	rightPart := internal.right.Slice(0, end-leftLen)
 
 	// Concatenate left and right parts
-	return &InternalNode{
-		left:   New(leftPart).root,
-		right:  New(rightPart).root,
-		length: 0, // Will be calculated by parent
-		size:   0,
-	}
+	return newInternalNode(New(leftPart).root, New(rightPart).root)
 }
 
 // ReplaceOptimized replaces characters from start to end (exclusive) with the given text.
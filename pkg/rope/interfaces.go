@@ -132,4 +132,6 @@ var (
 	_ Balanceable        = (*Rope)(nil)
 	_ DocumentMetrics    = (*Rope)(nil)
 	_ FullDocument       = (*Rope)(nil)
+
+	_ ReadOnly = (*RopeView)(nil)
 )
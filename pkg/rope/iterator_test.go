@@ -7,6 +7,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ========== Iterator Tests Aligned with Ropey ==========
@@ -474,3 +475,65 @@ func TestIterator_DeepTree(t *testing.T) {
 
 	assert.Equal(t, 100, count)
 }
+
+// TestNewIteratorAt_MatchesCharAt tests starting an iterator in the middle of a large rope
+func TestNewIteratorAt_MatchesCharAt(t *testing.T) {
+	text := strings.Repeat("abc ünï 世界 🎉\n", 2000)
+	r := chunkedRope(text, 100)
+
+	for _, pos := range []int{0, 1, 99, 100, 101, r.Length() / 2, r.Length() - 3} {
+		it, err := r.NewIteratorAt(pos)
+		require.NoError(t, err)
+
+		for i := pos; i < pos+3; i++ {
+			require.True(t, it.Next())
+			expected, err := r.CharAt(i)
+			require.NoError(t, err)
+			assert.Equal(t, expected, it.Current(), "rune at %d", i)
+			assert.Equal(t, i+1, it.Position())
+		}
+	}
+
+	// Iterating to the end crosses leaves and yields the rest of the text
+	it, err := r.NewIteratorAt(r.Length() / 2)
+	require.NoError(t, err)
+	assert.Equal(t, []rune(text)[r.Length()/2:], it.Collect())
+}
+
+// TestNewIteratorAt_Bounds tests the end position and out-of-bounds errors
+func TestNewIteratorAt_Bounds(t *testing.T) {
+	r := New("hello")
+
+	it, err := r.NewIteratorAt(5)
+	require.NoError(t, err)
+	assert.False(t, it.Next())
+	assert.False(t, it.HasNext())
+
+	it, err = r.NewIteratorAt(4)
+	require.NoError(t, err)
+	assert.True(t, it.HasNext())
+	assert.True(t, it.Next())
+	assert.Equal(t, 'o', it.Current())
+	assert.False(t, it.HasNext())
+
+	var boundsErr *ErrOutOfBounds
+	_, err = r.NewIteratorAt(-1)
+	assert.ErrorAs(t, err, &boundsErr)
+	_, err = r.NewIteratorAt(6)
+	assert.ErrorAs(t, err, &boundsErr)
+}
+
+// TestNewIteratorAt_PeekAcrossLeaves tests that peeking at a leaf boundary does not skip text
+func TestNewIteratorAt_PeekAcrossLeaves(t *testing.T) {
+	r := chunkedRope("abcdef", 2)
+
+	it, err := r.NewIteratorAt(1)
+	require.NoError(t, err)
+	require.True(t, it.Next())
+	assert.Equal(t, 'b', it.Current())
+
+	next, ok := it.Peek()
+	require.True(t, ok)
+	assert.Equal(t, 'c', next)
+	assert.Equal(t, []rune("cdef"), it.Collect())
+}
@@ -0,0 +1,87 @@
+package rope
+
+// DefaultJumpListSize is the number of positions a JumpList keeps when
+// NewJumpList is given a size of zero or less.
+const DefaultJumpListSize = 100
+
+// JumpList records cursor positions for "go back" and "go forward"
+// navigation, as editors do for jumps such as go-to-definition or search.
+//
+// Record the cursor position before each jump; Back then returns to it.
+// Like browser history, recording after going back discards the positions
+// that were ahead. When the document changes, Map keeps the recorded
+// positions pointing at the same text. JumpList is not safe for concurrent
+// use.
+//
+// Example:
+//
+//	jumps := rope.NewJumpList(0)
+//	jumps.Record(cursor)
+//	cursor = definitionPos
+//	if pos, ok := jumps.Back(); ok {
+//	    cursor = pos
+//	}
+type JumpList struct {
+	positions []int
+	current   int // Index of the current entry; len(positions) when not navigating
+	max       int
+}
+
+// NewJumpList creates an empty jump list keeping at most size positions.
+func NewJumpList(size int) *JumpList {
+	if size <= 0 {
+		size = DefaultJumpListSize
+	}
+	return &JumpList{max: size}
+}
+
+// Record adds pos as the newest position, discarding any positions ahead
+// of the one last returned by Back or Forward. Recording the same position
+// twice in a row keeps a single entry, and the oldest entry is dropped once
+// the list is full.
+func (j *JumpList) Record(pos int) {
+	j.positions = j.positions[:min(j.current+1, len(j.positions))]
+	if n := len(j.positions); n == 0 || j.positions[n-1] != pos {
+		j.positions = append(j.positions, pos)
+	}
+	if over := len(j.positions) - j.max; over > 0 {
+		j.positions = append(j.positions[:0], j.positions[over:]...)
+	}
+	j.current = len(j.positions)
+}
+
+// Back steps to the previous recorded position and returns it, or false if
+// there is none.
+func (j *JumpList) Back() (int, bool) {
+	if j.current == 0 {
+		return 0, false
+	}
+	j.current--
+	return j.positions[j.current], true
+}
+
+// Forward steps to the next recorded position after going back and returns
+// it, or false if there is none.
+func (j *JumpList) Forward() (int, bool) {
+	if j.current >= len(j.positions)-1 {
+		return 0, false
+	}
+	j.current++
+	return j.positions[j.current], true
+}
+
+// Len returns the number of recorded positions.
+func (j *JumpList) Len() int {
+	return len(j.positions)
+}
+
+// Map remaps every recorded position through cs, so the list stays valid
+// after the document is edited. A position inside deleted text moves to
+// the start of the deletion.
+func (j *JumpList) Map(cs *ChangeSet) {
+	if cs == nil || len(j.positions) == 0 {
+		return
+	}
+	mapped := NewPositionMapper(cs).AddPositions(j.positions, nil).Map()
+	copy(j.positions, mapped)
+}
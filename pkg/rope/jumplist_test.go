@@ -0,0 +1,85 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJumpList_BackForward tests navigating recorded positions
+func TestJumpList_BackForward(t *testing.T) {
+	j := NewJumpList(0)
+	_, ok := j.Back()
+	assert.False(t, ok)
+
+	for _, pos := range []int{10, 20, 20, 30} {
+		j.Record(pos)
+	}
+	assert.Equal(t, 3, j.Len())
+
+	for _, want := range []int{30, 20, 10} {
+		pos, ok := j.Back()
+		require.True(t, ok)
+		assert.Equal(t, want, pos)
+	}
+	_, ok = j.Back()
+	assert.False(t, ok)
+
+	pos, ok := j.Forward()
+	require.True(t, ok)
+	assert.Equal(t, 20, pos)
+
+	// Recording after going back drops the positions ahead
+	j.Record(25)
+	_, ok = j.Forward()
+	assert.False(t, ok)
+	assert.Equal(t, 3, j.Len())
+	pos, _ = j.Back()
+	assert.Equal(t, 25, pos)
+	pos, _ = j.Back()
+	assert.Equal(t, 20, pos)
+}
+
+// TestJumpList_Bounded tests that the oldest positions are dropped
+func TestJumpList_Bounded(t *testing.T) {
+	j := NewJumpList(3)
+	for pos := 1; pos <= 5; pos++ {
+		j.Record(pos)
+	}
+	assert.Equal(t, 3, j.Len())
+
+	var got []int
+	for pos, ok := j.Back(); ok; pos, ok = j.Back() {
+		got = append(got, pos)
+	}
+	assert.Equal(t, []int{5, 4, 3}, got)
+}
+
+// TestJumpList_Map tests that positions follow their text through an edit
+func TestJumpList_Map(t *testing.T) {
+	doc := New("alpha beta gamma delta")
+	j := NewJumpList(0)
+	j.Record(6)  // "beta"
+	j.Record(17) // "delta"
+	j.Record(12) // inside "gamma"
+
+	// Insert before beta and delete "gamma "
+	cs, err := ChangeSetFromEdits(doc.Length(), []EditOperation{
+		{From: 0, To: 0, Text: "// "},
+		{From: 11, To: 17},
+	})
+	require.NoError(t, err)
+	edited, err := cs.Apply(doc)
+	require.NoError(t, err)
+	j.Map(cs)
+
+	pos, _ := j.Back()
+	assert.Equal(t, 14, pos) // Where "gamma " was
+	pos, _ = j.Back()
+	word, _ := edited.Slice(pos, pos+5)
+	assert.Equal(t, "delta", word)
+	pos, _ = j.Back()
+	word, _ = edited.Slice(pos, pos+4)
+	assert.Equal(t, "beta", word)
+}
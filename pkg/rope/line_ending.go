@@ -0,0 +1,270 @@
+package rope
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LineEndingMode selects which character sequences line operations treat as
+// line breaks.
+type LineEndingMode int
+
+const (
+	// LineEndingLF breaks lines at '\n' only. A '\r' before it stays part
+	// of the line. This is the default.
+	LineEndingLF LineEndingMode = iota
+
+	// LineEndingCRLF breaks lines at "\r\n" only.
+	LineEndingCRLF
+
+	// LineEndingCR breaks lines at '\r' only, as in classic Mac OS files.
+	LineEndingCR
+
+	// LineEndingAuto breaks lines at "\r\n", '\r' and '\n', treating "\r\n"
+	// as a single break. Use it for files with mixed line endings.
+	LineEndingAuto
+)
+
+// LineEndingUnicode can be combined with any mode to additionally break
+// lines at U+0085 (NEXT LINE), U+2028 (LINE SEPARATOR) and U+2029
+// (PARAGRAPH SEPARATOR), the other mandatory breaks of Unicode UAX #14.
+// It is off by default.
+//
+// Example:
+//
+//	r = r.WithLineEnding(rope.LineEndingAuto | rope.LineEndingUnicode)
+const LineEndingUnicode LineEndingMode = 1 << 4
+
+// isUnicodeLineBreak reports whether ch is a line break recognized only
+// with LineEndingUnicode.
+func isUnicodeLineBreak(ch rune) bool {
+	return ch == '\u0085' || ch == '\u2028' || ch == '\u2029'
+}
+
+// String returns the string representation of LineEndingMode
+func (m LineEndingMode) String() string {
+	if m&LineEndingUnicode != 0 {
+		return (m &^ LineEndingUnicode).String() + "+Unicode"
+	}
+	switch m {
+	case LineEndingLF:
+		return "LF"
+	case LineEndingCRLF:
+		return "CRLF"
+	case LineEndingCR:
+		return "CR"
+	case LineEndingAuto:
+		return "Auto"
+	default:
+		return "Unknown"
+	}
+}
+
+// WithLineEnding returns a rope with the same content whose line
+// operations (LineCount, LineStart, LineEnd, Line, the lines iterator and
+// the functions built on them) break lines according to mode.
+//
+// Like WithCharClassifier, the mode is kept by the ropes produced by editing
+// the returned rope: Insert, Delete, Replace, Split, Concat, ChangeSet.Apply
+// and the editing commands built on them. Functions that build a rope from
+// new text, such as New or Map, use LineEndingLF.
+//
+// Example:
+//
+//	r := rope.New("one\rtwo\rthree").WithLineEnding(rope.LineEndingAuto)
+//	fmt.Println(r.LineCount()) // 3
+func (r *Rope) WithLineEnding(mode LineEndingMode) *Rope {
+	if r == nil {
+		r = Empty()
+	}
+	clone := r.derive(r.root, r.length, r.size)
+	clone.lineEnding = mode
+	clone.caches.Store(r.caches.Load())
+	return clone
+}
+
+// LineEndingMode returns the line ending mode used by the rope's line
+// operations.
+func (r *Rope) LineEndingMode() LineEndingMode {
+	if r == nil {
+		return LineEndingLF
+	}
+	return r.lineEnding
+}
+
+// forEachLineBreak calls fn with the character range [start, end) of every
+// line break in the rope, in order, until fn returns false.
+func (r *Rope) forEachLineBreak(fn func(start, end int) bool) {
+	r.forEachLineBreakBytes(func(start, end, _, _ int) bool {
+		return fn(start, end)
+	})
+}
+
+// forEachLineBreakBytes is forEachLineBreak that also passes the byte range
+// [startByte, endByte) of every line break.
+func (r *Rope) forEachLineBreakBytes(fn func(start, end, startByte, endByte int) bool) {
+	if r == nil || r.length == 0 {
+		return
+	}
+
+	mode := r.lineEnding &^ LineEndingUnicode
+	unicodeBreaks := r.lineEnding&LineEndingUnicode != 0
+	pos, base := 0, 0
+	pendingCR, pendingCRByte := -1, 0 // A '\r' that may start a "\r\n" pair
+	stopped := false
+	emit := func(start, end, startByte, endByte int) bool {
+		stopped = !fn(start, end, startByte, endByte)
+		return !stopped
+	}
+
+	forEachLeaf(r.root, func(text string) bool {
+		for i, ch := range text {
+			at := base + i
+			if pendingCR >= 0 {
+				cr := pendingCR
+				pendingCR = -1
+				if ch == '\n' {
+					if !emit(cr, pos+1, pendingCRByte, at+1) {
+						return false
+					}
+					pos++
+					continue
+				}
+				if mode == LineEndingAuto && !emit(cr, cr+1, pendingCRByte, pendingCRByte+1) {
+					return false
+				}
+			}
+
+			switch {
+			case ch == '\r' && mode == LineEndingCR:
+				if !emit(pos, pos+1, at, at+1) {
+					return false
+				}
+			case ch == '\r' && (mode == LineEndingCRLF || mode == LineEndingAuto):
+				pendingCR, pendingCRByte = pos, at
+			case ch == '\n' && (mode == LineEndingLF || mode == LineEndingAuto),
+				unicodeBreaks && isUnicodeLineBreak(ch):
+				if !emit(pos, pos+1, at, at+utf8.RuneLen(ch)) {
+					return false
+				}
+			}
+			pos++
+		}
+		base += len(text)
+		return true
+	})
+
+	if !stopped && pendingCR >= 0 && mode == LineEndingAuto {
+		fn(pendingCR, pendingCR+1, pendingCRByte, pendingCRByte+1)
+	}
+}
+
+// lineBreakCount returns the number of line breaks in the rope and whether
+// the rope ends with one.
+func (r *Rope) lineBreakCount() (count int, trailing bool) {
+	if r == nil || r.length == 0 {
+		return 0, false
+	}
+
+	if r.lineEnding == LineEndingLF {
+		last, _ := r.CharAt(r.length - 1)
+		return nodeLineBreaks(r.root), last == '\n'
+	}
+
+	r.forEachLineBreak(func(start, end int) bool {
+		count++
+		trailing = end == r.length
+		return true
+	})
+	return count, trailing
+}
+
+// lineSpan returns where line lineNum starts, where its content ends and
+// where its line break ends. The caller checks that lineNum is valid.
+func (r *Rope) lineSpan(lineNum int) (start, contentEnd, end int) {
+	contentEnd, end = r.Length(), r.Length()
+	if r.lineEnding == LineEndingLF {
+		if lineNum > 0 {
+			start = lineBreakPos(r.root, lineNum) + 1
+		}
+		if lineNum < nodeLineBreaks(r.root) {
+			contentEnd = lineBreakPos(r.root, lineNum+1)
+			end = contentEnd + 1
+		}
+		return start, contentEnd, end
+	}
+
+	line := 0
+	r.forEachLineBreak(func(breakStart, breakEnd int) bool {
+		if line == lineNum {
+			contentEnd, end = breakStart, breakEnd
+			return false
+		}
+		line++
+		start = breakEnd
+		return true
+	})
+	return start, contentEnd, end
+}
+
+// lineBreakPos returns the character position of the n-th '\n' (counting
+// from 1) in node, descending by the cached line counts. The caller checks
+// that node has at least n line breaks.
+func lineBreakPos(node RopeNode, n int) int {
+	pos := 0
+	for {
+		internal, ok := node.(*InternalNode)
+		if !ok {
+			break
+		}
+		if n <= internal.lines {
+			node = internal.left
+		} else {
+			n -= internal.lines
+			pos += internal.length
+			node = internal.right
+		}
+	}
+
+	text := nodeText(node)
+	offset := 0
+	for ; n > 0; n-- {
+		offset += strings.IndexByte(text[offset:], '\n') + 1
+	}
+	return pos + utf8.RuneCountInString(text[:offset-1])
+}
+
+// lineBreaksBefore returns the number of '\n' characters among the first
+// pos characters of node, descending by the cached line counts.
+func lineBreaksBefore(node RopeNode, pos int) int {
+	lines := 0
+	for {
+		internal, ok := node.(*InternalNode)
+		if !ok {
+			break
+		}
+		if pos < internal.length {
+			node = internal.left
+		} else {
+			lines += internal.lines
+			pos -= internal.length
+			node = internal.right
+		}
+	}
+
+	text := nodeText(node)
+	offset := 0
+	for i := 0; i < pos && offset < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return lines + strings.Count(text[:offset], "\n")
+}
+
+// nodeText returns the text of a leaf node.
+func nodeText(node RopeNode) string {
+	if leaf, ok := node.(*LeafNode); ok {
+		return leaf.text
+	}
+	return node.Slice(0, node.Length())
+}
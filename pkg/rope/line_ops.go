@@ -41,23 +41,56 @@ func (r *Rope) LineWithEnding(lineNum int) (string, error) {
 		}
 	}
 
-	start := r.LineStart(lineNum)
-	end := start + r.LineLength(lineNum)
+	start, _, end := r.lineSpan(lineNum)
 	return r.Slice(start, end)
 }
 
+// LineSlice returns the characters [startCol, endCol) of a line, without
+// its line ending, reading only that window of the rope. Use it instead of
+// Line to show part of a very long line, such as minified code, in a
+// viewport. A window reaching past the end of the line is cut off there.
+//
+// Example:
+//
+//	r := rope.New("hello world\nnext")
+//	s, _ := r.LineSlice(0, 6, 100)
+//	fmt.Println(s) // "world"
+func (r *Rope) LineSlice(lineNum, startCol, endCol int) (string, error) {
+	lineCount := r.LineCount()
+	if lineNum < 0 || lineNum >= lineCount {
+		return "", &ErrOutOfBounds{
+			Operation: "LineSlice",
+			Position:  lineNum,
+			Min:       0,
+			Max:       lineCount,
+		}
+	}
+
+	start, contentEnd, _ := r.lineSpan(lineNum)
+	if startCol < 0 || startCol > endCol {
+		return "", &ErrInvalidRange{
+			Operation: "LineSlice",
+			Start:     startCol,
+			End:       endCol,
+			ValidMax:  contentEnd - start,
+		}
+	}
+	from := min(start+startCol, contentEnd)
+	return r.Slice(from, min(start+endCol, contentEnd))
+}
+
 // LineCount returns the total number of lines in the rope.
 // An empty rope has 0 lines. A rope with content has at least 1 line.
+// Line breaks are recognized according to the rope's LineEndingMode.
 func (r *Rope) LineCount() int {
-	if r.length == 0 {
+	if r.Length() == 0 {
 		return 0
 	}
 
-	content := r.String()
-	count := strings.Count(content, "\n")
+	count, trailing := r.lineBreakCount()
 
-	// If content doesn't end with newline, add 1 for the last line
-	if !strings.HasSuffix(content, "\n") {
+	// If content doesn't end with a line break, add 1 for the last line
+	if !trailing {
 		return count + 1
 	}
 
@@ -75,22 +108,8 @@ func (r *Rope) LineStart(lineNum int) int {
 		return 0
 	}
 
-	it := r.NewIterator()
-	currentLine := 0
-
-	for it.Next() {
-		if it.Current() == '\n' {
-			currentLine++
-			if currentLine == lineNum {
-				// Return position AFTER the newline
-				// Position() returns charPos + 1, which is after the newline
-				return it.Position()
-			}
-		}
-	}
-
-	// Should not reach here
-	return r.Length()
+	start, _, _ := r.lineSpan(lineNum)
+	return start
 }
 
 // LineEnd returns the character position where the specified line ends (exclusive).
@@ -106,21 +125,55 @@ func (r *Rope) LineEnd(lineNum int) (int, error) {
 		}
 	}
 
-	start := r.LineStart(lineNum)
+	_, end, _ := r.lineSpan(lineNum)
+	return end, nil
+}
 
-	// Find the next newline after start
-	for i := start; i < r.Length(); i++ {
-		ch, err := r.CharAt(i)
-		if err != nil {
-			return 0, err
-		}
-		if ch == '\n' {
-			return i, nil
+// LineIsBlank returns true if the specified line is empty or contains only
+// whitespace, not counting its line ending. Lines out of bounds are not
+// blank.
+func (r *Rope) LineIsBlank(lineNum int) bool {
+	if lineNum < 0 || lineNum >= r.LineCount() {
+		return false
+	}
+
+	start, end, _ := r.lineSpan(lineNum)
+	it, err := r.NewIteratorAt(start)
+	if err != nil {
+		return false
+	}
+	return blankRunes(it, end-start)
+}
+
+// LinesText returns the text of lines startLine..endLine (inclusive) as a
+// single string. Line endings between the lines are kept; the ending of the
+// last line is not. What counts as the ending follows LineEndingMode, so in
+// the default LineEndingLF mode a '\r' before the last line's '\n' is kept,
+// as in Line. This is cheaper than joining Line(n) results, as it
+// reads only the requested part of the rope with one Slice.
+//
+// Example:
+//
+//	r := rope.New("a\nb\nc\nd")
+//	text, _ := r.LinesText(1, 2)
+//	fmt.Println(text) // "b\nc"
+func (r *Rope) LinesText(startLine, endLine int) (string, error) {
+	lineCount := r.LineCount()
+	if startLine < 0 || endLine >= lineCount || startLine > endLine {
+		return "", &ErrInvalidRange{
+			Operation: "LinesText",
+			Start:     startLine,
+			End:       endLine,
+			ValidMax:  lineCount,
 		}
 	}
 
-	// No newline found, this is the last line
-	return r.Length(), nil
+	start := r.LineStart(startLine)
+	end, err := r.LineEnd(endLine)
+	if err != nil {
+		return "", err
+	}
+	return r.Slice(start, end)
 }
 
 // LineLength returns the length of the specified line in characters (excluding line ending).
@@ -143,20 +196,7 @@ func (r *Rope) LineWithEndingLength(lineNum int) (int, error) {
 		}
 	}
 
-	start := r.LineStart(lineNum)
-	end := start + r.LineLength(lineNum)
-
-	// Add 1 for the newline if it exists
-	if end < r.Length() {
-		ch, err := r.CharAt(end)
-		if err != nil {
-			return 0, err
-		}
-		if ch == '\n' {
-			return (end - start) + 1, nil
-		}
-	}
-
+	start, _, end := r.lineSpan(lineNum)
 	return end - start, nil
 }
 
@@ -315,16 +355,21 @@ func (r *Rope) LineAtChar(pos int) int {
 		return 0
 	}
 
-	// Use iterator for efficient traversal (avoids expensive CharAt calls)
-	lineNum := 0
-	it := r.NewIterator()
-	for i := 0; i <= pos && it.Next(); i++ {
-		if it.Current() == '\n' {
-			lineNum++
-		}
+	// Counts the line breaks up to and including pos via the cached counts
+	if r.lineEnding == LineEndingLF {
+		return lineBreaksBefore(r.root, min(pos+1, r.Length()))
 	}
 
-	return lineNum
+	// The cached counts only cover '\n', so other modes scan for the breaks
+	line := 0
+	r.forEachLineBreak(func(start, _ int) bool {
+		if start > pos {
+			return false
+		}
+		line++
+		return true
+	})
+	return line
 }
 
 // ColumnAtChar returns the column number (0-indexed) within the line
@@ -469,6 +514,41 @@ func (r *Rope) SplitLines() ([]string, error) {
 	return it.ToSlice()
 }
 
+// SplitLinesIter returns an iterator over the lines, yielding each line's
+// number and the character range of its content without the line ending,
+// in a single pass and without copying any text. It visits the same lines
+// as SplitLines, so a caller can materialize only the lines it needs with
+// Slice. It has the shape of iter.Seq2 for Go 1.23+ for-range loops.
+//
+// Example:
+//
+//	for n, rng := range r.SplitLinesIter() {
+//	    if n == target {
+//	        line, _ := r.Slice(rng.From(), rng.To())
+//	        fmt.Println(line)
+//	        break
+//	    }
+//	}
+func (r *Rope) SplitLinesIter() func(yield func(int, Range) bool) {
+	return func(yield func(int, Range) bool) {
+		lineNum, start := 0, 0
+		stopped := false
+		r.forEachLineBreak(func(breakStart, breakEnd int) bool {
+			if !yield(lineNum, NewRange(start, breakStart)) {
+				stopped = true
+				return false
+			}
+			lineNum++
+			start = breakEnd
+			return true
+		})
+		// The text after the last line break, if any, is the last line
+		if !stopped && start < r.Length() {
+			yield(lineNum, NewRange(start, r.Length()))
+		}
+	}
+}
+
 // IndentLines adds indentation to all lines.
 // prefix is added to the beginning of each line.
 // Returns a new Rope, leaving the original unchanged.
@@ -543,6 +623,67 @@ func leadingWhitespaceCount(s string) int {
 	return count
 }
 
+// TailLines returns the last n lines of the rope (without line endings), in
+// document order. If the rope has fewer lines, all of them are returned.
+// The rope is read backward from the end and only as far as the requested
+// lines reach, so this is cheap on large documents such as logs.
+//
+// Example:
+//
+//	lines, _ := r.TailLines(3)
+//	for _, line := range lines {
+//	    fmt.Println(line)
+//	}
+func (r *Rope) TailLines(n int) ([]string, error) {
+	if n < 0 {
+		return nil, &ErrInvalidInput{
+			Parameter: "n",
+			Value:     n,
+			Reason:    "must not be negative",
+		}
+	}
+	if n == 0 || r.Length() == 0 {
+		return []string{}, nil
+	}
+
+	if r.lineEnding != LineEndingLF {
+		lineCount := r.LineCount()
+		lines := make([]string, 0, min(n, lineCount))
+		err := r.ForEachLineRange(max(lineCount-n, 0), lineCount, func(_ int, line string) bool {
+			lines = append(lines, line)
+			return true
+		})
+		return lines, err
+	}
+
+	// Collect leaves from the end until they hold n line breaks, not
+	// counting a trailing one, which does not start a line
+	w := newLeafWalker(r, true)
+	var chunks []string
+	want, newlines := n, 0
+	for newlines < want {
+		text, ok := w.next()
+		if !ok {
+			break
+		}
+		if len(chunks) == 0 && strings.HasSuffix(text, "\n") {
+			want++
+		}
+		chunks = append(chunks, text)
+		newlines += strings.Count(text, "\n")
+	}
+
+	var sb strings.Builder
+	for i := len(chunks) - 1; i >= 0; i-- {
+		sb.WriteString(chunks[i])
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
 // ========== Paragraph Operations ==========
 
 // ParagraphCount returns the number of paragraphs (separated by blank lines).
@@ -569,3 +710,187 @@ func (r *Rope) Paragraph(paraNum int) string {
 
 	return paragraphs[paraNum]
 }
+
+// ========== Line Callbacks ==========
+
+// ForEachLine calls fn with the number and text (without line ending) of
+// every line, stopping early when fn returns false. Lines are produced in a
+// single walk over the rope's leaves rather than by repeated Line(n) lookups.
+//
+// The text passed to fn matches Line(lineNum).
+//
+// Example:
+//
+//	r.ForEachLine(func(lineNum int, line string) bool {
+//	    fmt.Printf("%d: %s\n", lineNum, line)
+//	    return true
+//	})
+func (r *Rope) ForEachLine(fn func(lineNum int, line string) bool) error {
+	if r == nil {
+		return nil
+	}
+	return r.ForEachLineRange(0, r.LineCount(), fn)
+}
+
+// ForEachLineRange is like ForEachLine but only visits lines in
+// [start, end). Lines before start are skipped without building their text,
+// and the walk stops once line end is reached.
+func (r *Rope) ForEachLineRange(start, end int, fn func(lineNum int, line string) bool) error {
+	lineCount := 0
+	if r != nil {
+		lineCount = r.LineCount()
+	}
+	if start < 0 || end > lineCount || start > end {
+		return &ErrInvalidRange{
+			Operation: "ForEachLineRange",
+			Start:     start,
+			End:       end,
+			ValidMax:  lineCount,
+		}
+	}
+	if start == end {
+		return nil
+	}
+	if r.lineEnding != LineEndingLF {
+		return r.forEachLineSpan(start, end, fn)
+	}
+
+	var line strings.Builder
+	lineNum := 0
+	done := false
+
+	forEachLeaf(r.root, func(text string) bool {
+		for text != "" {
+			idx := strings.IndexByte(text, '\n')
+			if idx < 0 {
+				if lineNum >= start {
+					line.WriteString(text)
+				}
+				return true
+			}
+
+			if lineNum >= start {
+				line.WriteString(text[:idx])
+				if !fn(lineNum, line.String()) {
+					done = true
+					return false
+				}
+				line.Reset()
+			}
+			text = text[idx+1:]
+			lineNum++
+			if lineNum >= end {
+				done = true
+				return false
+			}
+		}
+		return true
+	})
+
+	// The last line has no trailing newline
+	if !done && lineNum < end {
+		fn(lineNum, line.String())
+	}
+	return nil
+}
+
+// ForEachLineBytes calls fn with the number and the byte range of every
+// line, stopping early when fn returns false. The range [startByte,
+// endByte) covers the line's text without its line break, as returned by
+// Line, so it can be used to build a byte offset index for seeking in the
+// file the rope was loaded from.
+//
+// In LineEndingLF mode the offsets are found by scanning each leaf for
+// '\n' and adding up leaf sizes, so no characters are decoded. Other modes
+// decode each character once and keep a running byte offset.
+//
+// Example:
+//
+//	r.ForEachLineBytes(func(lineNum, startByte, endByte int) bool {
+//	    index = append(index, int64(startByte))
+//	    return true
+//	})
+func (r *Rope) ForEachLineBytes(fn func(lineNum int, startByte, endByte int) bool) {
+	if r == nil || r.length == 0 {
+		return
+	}
+
+	lineNum, lineStart := 0, 0
+	if r.lineEnding != LineEndingLF {
+		r.forEachLineBreakBytes(func(_, _, breakStart, breakEnd int) bool {
+			if !fn(lineNum, lineStart, breakStart) {
+				lineStart = -1
+				return false
+			}
+			lineNum++
+			lineStart = breakEnd
+			return true
+		})
+	} else {
+		offset := 0
+		forEachLeaf(r.root, func(text string) bool {
+			for i := 0; ; {
+				idx := strings.IndexByte(text[i:], '\n')
+				if idx < 0 {
+					break
+				}
+				end := offset + i + idx
+				if !fn(lineNum, lineStart, end) {
+					lineStart = -1
+					return false
+				}
+				lineNum++
+				lineStart = end + 1
+				i += idx + 1
+			}
+			offset += len(text)
+			return true
+		})
+	}
+
+	// The last line has no line break; lineStart is -1 if fn stopped early
+	if lineStart >= 0 && lineStart < r.size {
+		fn(lineNum, lineStart, r.size)
+	}
+}
+
+// LineByteRanges returns the byte range of every line, without its line
+// break. See ForEachLineBytes.
+func (r *Rope) LineByteRanges() []ByteRange {
+	var ranges []ByteRange
+	r.ForEachLineBytes(func(_ int, startByte, endByte int) bool {
+		ranges = append(ranges, ByteRange{Start: startByte, End: endByte})
+		return true
+	})
+	return ranges
+}
+
+// forEachLineSpan implements ForEachLineRange for line ending modes other
+// than LineEndingLF, slicing each line at the breaks the mode recognizes.
+func (r *Rope) forEachLineSpan(start, end int, fn func(lineNum int, line string) bool) error {
+	lineNum, lineStart := 0, 0
+	var err error
+	visit := func(contentEnd int) bool {
+		if lineNum >= start {
+			var line string
+			if line, err = r.Slice(lineStart, contentEnd); err != nil || !fn(lineNum, line) {
+				return false
+			}
+		}
+		lineNum++
+		return lineNum < end
+	}
+
+	done := false
+	r.forEachLineBreak(func(breakStart, breakEnd int) bool {
+		done = !visit(breakStart)
+		lineStart = breakEnd
+		return !done
+	})
+
+	// The last line has no trailing line break
+	if !done && lineNum < end {
+		visit(r.Length())
+	}
+	return err
+}
@@ -1,9 +1,14 @@
 package rope
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestRange_Line tests getting lines
@@ -18,6 +23,22 @@ func TestRange_GetLine(t *testing.T) {
 	assert.Equal(t, "Line 3", lines[2])
 }
 
+// TestLines_LineEndingMode tests that Lines splits like Line in each mode
+func TestLines_LineEndingMode(t *testing.T) {
+	r := New("a\r\nb\rc\n")
+
+	assert.Equal(t, []string{"a\r\n", "b\rc\n", ""}, r.Lines())
+	assert.Equal(t, []string{"a\r\n", "b\r", "c\n", ""}, r.WithLineEnding(LineEndingAuto).Lines())
+	assert.Equal(t, []string{"a\r", "\nb\r", "c\n"}, r.WithLineEnding(LineEndingCR).Lines())
+
+	// Without a trailing break there is one entry per line
+	r = New("a\r\nb\rc\nd")
+	for _, mode := range []LineEndingMode{LineEndingLF, LineEndingCRLF, LineEndingCR, LineEndingAuto} {
+		doc := r.WithLineEnding(mode)
+		assert.Len(t, doc.Lines(), doc.LineCount(), mode.String())
+	}
+}
+
 // TestRange_LineAt tests getting line at specific index
 func TestRange_LineAt(t *testing.T) {
 	text := "Line 1\nLine 2\nLine 3"
@@ -62,3 +83,527 @@ func TestLineInfo_LineAtChar(t *testing.T) {
 	lineNum = r.LineAtChar(13)
 	assert.Equal(t, 2, lineNum)
 }
+
+// TestForEachLine_CountsLines tests visiting every line through the callback
+func TestForEachLine_CountsLines(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&sb, "line %d\r\n", i)
+	}
+	sb.WriteString("last")
+	r := New(sb.String())
+
+	calls := 0
+	err := r.ForEachLine(func(lineNum int, line string) bool {
+		// Each line is delivered once, in order, matching Line(n)
+		assert.Equal(t, calls, lineNum)
+		expected, lineErr := r.Line(lineNum)
+		require.NoError(t, lineErr)
+		assert.Equal(t, expected, line)
+		calls++
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, r.LineCount(), calls)
+}
+
+// TestForEachLine_EarlyStop tests stopping the iteration at line 5
+func TestForEachLine_EarlyStop(t *testing.T) {
+	r := New(strings.Repeat("x\n", 20))
+
+	var seen []int
+	err := r.ForEachLine(func(lineNum int, line string) bool {
+		seen = append(seen, lineNum)
+		return lineNum < 5
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, seen)
+}
+
+// TestForEachLine_TrailingNewline tests that no extra line follows a final newline
+func TestForEachLine_TrailingNewline(t *testing.T) {
+	var lines []string
+	require.NoError(t, New("a\n\nb\n").ForEachLine(func(_ int, line string) bool {
+		lines = append(lines, line)
+		return true
+	}))
+	assert.Equal(t, []string{"a", "", "b"}, lines)
+
+	calls := 0
+	require.NoError(t, Empty().ForEachLine(func(int, string) bool {
+		calls++
+		return true
+	}))
+	assert.Zero(t, calls)
+}
+
+// TestForEachLineRange tests visiting a sub-range of lines
+func TestForEachLineRange(t *testing.T) {
+	r := New("l0\nl1\nl2\nl3\nl4")
+
+	var lines []string
+	err := r.ForEachLineRange(1, 4, func(lineNum int, line string) bool {
+		lines = append(lines, fmt.Sprintf("%d:%s", lineNum, line))
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:l1", "2:l2", "3:l3"}, lines)
+
+	lines = nil
+	require.NoError(t, r.ForEachLineRange(4, 5, func(lineNum int, line string) bool {
+		lines = append(lines, line)
+		return true
+	}))
+	assert.Equal(t, []string{"l4"}, lines)
+
+	var rangeErr *ErrInvalidRange
+	assert.ErrorAs(t, r.ForEachLineRange(2, 6, func(int, string) bool { return true }), &rangeErr)
+	assert.ErrorAs(t, r.ForEachLineRange(3, 2, func(int, string) bool { return true }), &rangeErr)
+}
+
+// TestLinesText_Viewport tests fetching a block of lines from a large document
+func TestLinesText_Viewport(t *testing.T) {
+	lines := make([]string, 10000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d: ünïcode 世界", i)
+	}
+	r := chunkedRope(strings.Join(lines, "\n")+"\n", 997)
+
+	text, err := r.LinesText(100, 120)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(lines[100:121], "\n"), text)
+
+	// Single line and the last line
+	text, err = r.LinesText(9999, 9999)
+	require.NoError(t, err)
+	assert.Equal(t, lines[9999], text)
+}
+
+// TestLinesText_InvalidRange tests rejecting bad line ranges
+func TestLinesText_InvalidRange(t *testing.T) {
+	r := New("a\r\nb\r\nc")
+
+	text, err := r.LinesText(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "a\r\nb\r", text)
+	text, err = r.WithLineEnding(LineEndingCRLF).LinesText(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "a\r\nb", text)
+
+	var rangeErr *ErrInvalidRange
+	_, err = r.LinesText(-1, 1)
+	assert.ErrorAs(t, err, &rangeErr)
+	_, err = r.LinesText(0, 3)
+	assert.ErrorAs(t, err, &rangeErr)
+	_, err = r.LinesText(2, 1)
+	assert.ErrorAs(t, err, &rangeErr)
+	_, err = Empty().LinesText(0, 0)
+	assert.ErrorAs(t, err, &rangeErr)
+}
+
+// TestLineEnding_CROnly tests a classic Mac document that LF mode sees as one line
+func TestLineEnding_CROnly(t *testing.T) {
+	r := New("first\rsecond\rthird")
+	assert.Equal(t, 1, r.LineCount())
+
+	cr := r.WithLineEnding(LineEndingCR)
+	assert.Equal(t, 3, cr.LineCount())
+	assert.Equal(t, 6, cr.LineStart(1))
+	end, err := cr.LineEnd(1)
+	require.NoError(t, err)
+	assert.Equal(t, 12, end)
+
+	line, err := cr.Line(2)
+	require.NoError(t, err)
+	assert.Equal(t, "third", line)
+	withEnding, err := cr.LineWithEnding(0)
+	require.NoError(t, err)
+	assert.Equal(t, "first\r", withEnding)
+
+	lines, err := cr.LinesIterator().ToSlice()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, lines)
+
+	// Auto recognizes the same breaks
+	assert.Equal(t, 3, r.WithLineEnding(LineEndingAuto).LineCount())
+}
+
+// TestLineEnding_Modes tests each mode on a document with mixed line endings
+func TestLineEnding_Modes(t *testing.T) {
+	r := New("a\r\nb\nc\rd\r\n")
+
+	tests := []struct {
+		mode  LineEndingMode
+		lines []string
+	}{
+		{LineEndingLF, []string{"a\r", "b", "c\rd\r"}},
+		{LineEndingCRLF, []string{"a", "b\nc\rd"}},
+		{LineEndingCR, []string{"a", "\nb\nc", "d", "\n"}},
+		{LineEndingAuto, []string{"a", "b", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			doc := r.WithLineEnding(tt.mode)
+			assert.Equal(t, tt.mode, doc.LineEndingMode())
+			require.Equal(t, len(tt.lines), doc.LineCount())
+
+			for i, want := range tt.lines {
+				line, err := doc.Line(i)
+				require.NoError(t, err)
+				assert.Equal(t, want, line, "line %d", i)
+			}
+
+			var visited []string
+			require.NoError(t, doc.ForEachLine(func(_ int, line string) bool {
+				visited = append(visited, line)
+				return true
+			}))
+			assert.Equal(t, tt.lines, visited)
+		})
+	}
+
+	// Edits keep the mode
+	auto := r.WithLineEnding(LineEndingAuto)
+	edited, err := auto.Insert(0, "x")
+	require.NoError(t, err)
+	assert.Equal(t, LineEndingAuto, edited.LineEndingMode())
+	assert.Equal(t, 4, edited.LineCount())
+
+	edited, err = auto.Delete(0, 3)
+	require.NoError(t, err)
+	assert.Equal(t, LineEndingAuto, edited.LineEndingMode())
+
+	left, right, err := auto.Split(4)
+	require.NoError(t, err)
+	assert.Equal(t, LineEndingAuto, left.LineEndingMode())
+	assert.Equal(t, LineEndingAuto, right.LineEndingMode())
+	assert.Equal(t, LineEndingAuto, left.Concat(right).LineEndingMode())
+
+	edited, err = NewChangeSet(auto.Length()).Insert(">").Retain(auto.Length()).Apply(auto)
+	require.NoError(t, err)
+	assert.Equal(t, LineEndingAuto, edited.LineEndingMode())
+
+	// New ropes use the default mode
+	assert.Equal(t, LineEndingLF, New(auto.String()).LineEndingMode())
+}
+
+// TestLineEnding_LineAtChar tests line and column lookups in each mode
+func TestLineEnding_LineAtChar(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Rope
+	}{
+		{"CR", New("one\rtwo\rthree").WithLineEnding(LineEndingCR)},
+		{"CRLF", New("one\r\ntwo\r\nthree").WithLineEnding(LineEndingCRLF)},
+		{"Unicode", New("one\u2028two\u2029three").WithLineEnding(LineEndingLF | LineEndingUnicode)},
+		{"Auto", chunkedRope("one\r\ntwo\rthree", 4).WithLineEnding(LineEndingAuto)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.r
+			require.Equal(t, 3, r.LineCount())
+
+			for line := 0; line < r.LineCount(); line++ {
+				start := r.LineStart(line)
+				end, err := r.LineEnd(line)
+				require.NoError(t, err)
+				for pos := start; pos <= end; pos++ {
+					if pos == end && line < r.LineCount()-1 {
+						break // The line break itself counts as the next line
+					}
+					assert.Equal(t, line, r.LineAtChar(pos), "pos %d", pos)
+					assert.Equal(t, pos-start, r.ColumnAtChar(pos), "pos %d", pos)
+				}
+			}
+			assert.Equal(t, 1, r.LineAtChar(r.LineStart(1)+1))
+			assert.Equal(t, 1, r.ColumnAtChar(r.LineStart(1)+1))
+		})
+	}
+}
+
+// TestLineEnding_UnicodeSeparators tests U+2028 and friends with the option on and off
+func TestLineEnding_UnicodeSeparators(t *testing.T) {
+	r := New("one\u2028two\u2029three\nfour\u0085five")
+
+	assert.Equal(t, 2, r.LineCount())
+	assert.Equal(t, 2, r.WithLineEnding(LineEndingAuto).LineCount())
+
+	uni := r.WithLineEnding(LineEndingLF | LineEndingUnicode)
+	assert.Equal(t, "LF+Unicode", uni.LineEndingMode().String())
+	assert.Equal(t, 5, uni.LineCount())
+	assert.Equal(t, 4, uni.LineStart(1))
+	assert.Equal(t, 8, uni.LineStart(2))
+
+	var visited []string
+	require.NoError(t, uni.ForEachLine(func(_ int, line string) bool {
+		visited = append(visited, line)
+		return true
+	}))
+	assert.Equal(t, []string{"one", "two", "three", "four", "five"}, visited)
+
+	// A trailing separator ends the last line
+	trailing := New("a\u2028").WithLineEnding(LineEndingAuto | LineEndingUnicode)
+	assert.Equal(t, 1, trailing.LineCount())
+}
+
+// TestTailLines tests fetching the last lines of a large document
+func TestTailLines(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&sb, "log entry %d\n", i)
+	}
+	r := chunkedRope(sb.String(), 64)
+
+	lines, err := r.TailLines(3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log entry 49997", "log entry 49998", "log entry 49999"}, lines)
+
+	// Without a trailing newline the last line is partial
+	r2, err := r.Insert(r.Length(), "partial")
+	require.NoError(t, err)
+	lines, err = r2.TailLines(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log entry 49999", "partial"}, lines)
+}
+
+// TestTailLines_MoreThanExist tests requesting more lines than the rope has
+func TestTailLines_MoreThanExist(t *testing.T) {
+	r := chunkedRope("one\ntwo\n\nfour", 2)
+
+	lines, err := r.TailLines(10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "", "four"}, lines)
+
+	lines, err = New("a\r\nb").WithLineEnding(LineEndingCRLF).TailLines(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, lines)
+
+	lines, err = Empty().TailLines(3)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+
+	_, err = r.TailLines(-1)
+	assert.Error(t, err)
+
+	// Matches the lines from the front
+	for n := 0; n <= 5; n++ {
+		lines, err := r.TailLines(n)
+		require.NoError(t, err)
+		all, err := r.LinesIterator().ToSlice()
+		require.NoError(t, err)
+		assert.Equal(t, all[max(len(all)-n, 0):], lines, "n=%d", n)
+	}
+}
+
+// TestLineByteRanges tests byte ranges against counting bytes by hand
+func TestLineByteRanges(t *testing.T) {
+	lines := []string{"plain ascii", "héllo wörld", "", "日本語のテキスト", "emoji 🎉 end"}
+	text := strings.Join(lines, "\n")
+
+	var want []ByteRange
+	offset := 0
+	for _, line := range lines {
+		want = append(want, ByteRange{Start: offset, End: offset + len(line)})
+		offset += len(line) + 1
+	}
+
+	for _, r := range []*Rope{New(text), chunkedRope(text, 5), New(text + "\n")} {
+		ranges := r.LineByteRanges()
+		assert.Equal(t, want, ranges)
+		for i, rng := range ranges {
+			assert.Equal(t, lines[i], text[rng.Start:rng.End])
+		}
+	}
+
+	// Early termination
+	var visited []int
+	chunkedRope(text, 3).ForEachLineBytes(func(lineNum, _, _ int) bool {
+		visited = append(visited, lineNum)
+		return lineNum < 1
+	})
+	assert.Equal(t, []int{0, 1}, visited)
+
+	assert.Empty(t, Empty().LineByteRanges())
+}
+
+// TestLineByteRanges_CRLF tests byte ranges in other line ending modes
+func TestLineByteRanges_CRLF(t *testing.T) {
+	r := New("é\r\nab\rc").WithLineEnding(LineEndingAuto)
+	assert.Equal(t, []ByteRange{{0, 2}, {4, 6}, {7, 8}}, r.LineByteRanges())
+
+	// In LF mode the CR stays part of the line, as in Line
+	assert.Equal(t, []ByteRange{{0, 3}, {4, 8}}, New("é\r\nab\rc").LineByteRanges())
+
+	// Offsets carry across leaves and multi-byte breaks
+	text := strings.Repeat("é\r\nx\u2028", 50)
+	doc := chunkedRope(text, 3).WithLineEnding(LineEndingAuto | LineEndingUnicode)
+	ranges := doc.LineByteRanges()
+	require.Len(t, ranges, doc.LineCount())
+	for i, rng := range ranges {
+		line, err := doc.Line(i)
+		require.NoError(t, err)
+		assert.Equal(t, line, text[rng.Start:rng.End], "line %d", i)
+	}
+}
+
+// TestLineAtChar_MillionLines tests line lookups on a 1M-line document against scanning
+func TestLineAtChar_MillionLines(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping large document test in short mode")
+	}
+
+	const lineCount = 1_000_000
+	var sb strings.Builder
+	starts := make([]int, lineCount+1)
+	for i := 0; i < lineCount; i++ {
+		line := fmt.Sprintf("line %d é\n", i%1000)
+		sb.WriteString(line)
+		starts[i+1] = starts[i] + utf8.RuneCountInString(line)
+	}
+	r := New(sb.String())
+
+	assert.Equal(t, lineCount, r.LineCount())
+	for _, line := range []int{0, 1, 999, 123456, 500000, lineCount - 1} {
+		assert.Equal(t, starts[line], r.LineStart(line), "line %d", line)
+		end, err := r.LineEnd(line)
+		require.NoError(t, err)
+		assert.Equal(t, starts[line+1]-1, end, "line %d", line)
+	}
+
+	// Against a single scan counting the line breaks up to each position
+	lineNum := 0
+	it := r.NewIterator()
+	for pos := 0; it.Next(); pos++ {
+		if it.Current() == '\n' {
+			lineNum++
+		}
+		if pos%997 == 0 && pos > 0 {
+			require.Equal(t, lineNum, r.LineAtChar(pos), "pos %d", pos)
+		}
+	}
+	for _, line := range []int{1, 2, 777777, lineCount - 1} {
+		assert.Equal(t, line, r.LineAtChar(starts[line]))
+		assert.Equal(t, line-1, r.LineAtChar(starts[line]-2))
+	}
+	assert.Equal(t, lineCount, r.LineAtChar(r.Length()))
+
+	// Edits keep the cached counts up to date
+	edited, err := r.Insert(starts[500000], "new\nlines\n")
+	require.NoError(t, err)
+	edited, err = edited.Delete(starts[10], starts[20])
+	require.NoError(t, err)
+	assert.Equal(t, lineCount+2-10, edited.LineCount())
+	line, err := edited.Line(500000 - 10)
+	require.NoError(t, err)
+	assert.Equal(t, "new", line)
+	assert.Equal(t, 500000-10, edited.LineAtChar(edited.LineStart(500000-10)))
+
+	lookups := 10000
+	begin := time.Now()
+	for i := 0; i < lookups; i++ {
+		r.LineAtChar(starts[(i*7919)%lineCount])
+	}
+	t.Logf("LineAtChar: %v per lookup", time.Since(begin)/time.Duration(lookups))
+}
+
+// BenchmarkLineAtChar_MillionLines measures line lookups on a 1M-line document
+func BenchmarkLineAtChar_MillionLines(b *testing.B) {
+	r := New(strings.Repeat("a line of text\n", 1_000_000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.LineAtChar((i * 7919) % r.Length())
+	}
+}
+
+// TestLineSlice_LongLine tests fetching a column window of a very long line
+func TestLineSlice_LongLine(t *testing.T) {
+	long := strings.Repeat("var x=\"é\";", 20000)
+	r := New("first\n" + long + "\nlast")
+
+	full, err := r.Line(1)
+	assert.NoError(t, err)
+	want := string([]rune(full)[1000:1100])
+
+	got, err := r.LineSlice(1, 1000, 1100)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, 100, utf8.RuneCountInString(got))
+
+	// Windows past the line end stop before the line break
+	n := utf8.RuneCountInString(long)
+	got, err = r.LineSlice(1, n-3, n+50)
+	assert.NoError(t, err)
+	assert.Equal(t, "é\";", got)
+	got, err = r.LineSlice(0, 10, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "", got)
+}
+
+// TestLineSlice_Errors tests invalid lines and column ranges
+func TestLineSlice_Errors(t *testing.T) {
+	r := New("one\ntwo")
+
+	_, err := r.LineSlice(2, 0, 1)
+	var boundsErr *ErrOutOfBounds
+	assert.ErrorAs(t, err, &boundsErr)
+
+	_, err = r.LineSlice(0, 2, 1)
+	var rangeErr *ErrInvalidRange
+	assert.ErrorAs(t, err, &rangeErr)
+	_, err = r.LineSlice(0, -1, 1)
+	assert.ErrorAs(t, err, &rangeErr)
+}
+
+// TestSplitLinesIter tests that line ranges match SplitLines and LineEnd
+func TestSplitLinesIter(t *testing.T) {
+	texts := []string{
+		"",
+		"single",
+		"one\ntwo\r\nthree",
+		"trailing\n",
+		"\n\nblank lines\n\n",
+		strings.Repeat("Grüße 世界\n", 500),
+	}
+	for _, text := range texts {
+		r := chunkedRope(text, 37)
+		lines, err := r.SplitLines()
+		require.NoError(t, err)
+
+		count := 0
+		r.SplitLinesIter()(func(n int, rng Range) bool {
+			require.Equal(t, count, n)
+			assert.Equal(t, r.LineStart(n), rng.From())
+			end, err := r.LineEnd(n)
+			require.NoError(t, err)
+			assert.Equal(t, end, rng.To())
+
+			line, err := r.Slice(rng.From(), rng.To())
+			require.NoError(t, err)
+			assert.Equal(t, lines[n], line)
+			count++
+			return true
+		})
+		assert.Equal(t, len(lines), count, "text %q", text)
+	}
+}
+
+// TestSplitLinesIter_Selected tests materializing only some lines and stopping early
+func TestSplitLinesIter_Selected(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	r := New(sb.String())
+
+	var got []string
+	r.SplitLinesIter()(func(n int, rng Range) bool {
+		if n%2500 == 0 {
+			line, _ := r.Slice(rng.From(), rng.To())
+			got = append(got, line)
+		}
+		return n < 5000
+	})
+	assert.Equal(t, []string{"line 0", "line 2500", "line 5000"}, got)
+}
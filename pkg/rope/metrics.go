@@ -0,0 +1,83 @@
+package rope
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// ========== Operation Metrics ==========
+
+// Metrics counts rope operations while installed with SetMetrics, to find
+// editor hotspots such as a loop calling CharAt or Slice once per
+// character. Calls made internally by other rope functions are counted
+// too, which is what makes hidden O(n) work visible.
+//
+// Metrics is safe for concurrent use.
+//
+// Example:
+//
+//	m := &rope.Metrics{}
+//	prev := rope.SetMetrics(m)
+//	defer rope.SetMetrics(prev)
+//	runEditorCommand()
+//	fmt.Println(m.Snapshot())
+type Metrics struct {
+	inserts     atomic.Int64
+	deletes     atomic.Int64
+	slices      atomic.Int64
+	charAts     atomic.Int64
+	bytesCopied atomic.Int64
+}
+
+// MetricsSnapshot holds the counter values of a Metrics at one moment.
+type MetricsSnapshot struct {
+	Inserts     int64 // Calls to Insert
+	Deletes     int64 // Calls to Delete
+	Slices      int64 // Calls to Slice
+	CharAts     int64 // Calls to CharAt
+	BytesCopied int64 // Bytes copied out of ropes by Slice, String, Bytes and Delete
+}
+
+// String returns a one-line summary of the counters.
+func (s MetricsSnapshot) String() string {
+	return fmt.Sprintf("Inserts: %d, Deletes: %d, Slices: %d, CharAts: %d, BytesCopied: %d",
+		s.Inserts, s.Deletes, s.Slices, s.CharAts, s.BytesCopied)
+}
+
+// Snapshot returns the current counter values.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Inserts:     m.inserts.Load(),
+		Deletes:     m.deletes.Load(),
+		Slices:      m.slices.Load(),
+		CharAts:     m.charAts.Load(),
+		BytesCopied: m.bytesCopied.Load(),
+	}
+}
+
+// Reset sets every counter back to zero.
+func (m *Metrics) Reset() {
+	m.inserts.Store(0)
+	m.deletes.Store(0)
+	m.slices.Store(0)
+	m.charAts.Store(0)
+	m.bytesCopied.Store(0)
+}
+
+// activeMetrics is the collector installed by SetMetrics, or nil.
+var activeMetrics atomic.Pointer[Metrics]
+
+// SetMetrics installs m as the package-wide collector counting operations
+// on all ropes, and returns the previously installed one. Passing nil
+// turns counting off, which is the default; then each counted operation
+// costs a single atomic load.
+func SetMetrics(m *Metrics) *Metrics {
+	return activeMetrics.Swap(m)
+}
+
+// countCopy records n bytes copied out of a rope.
+func countCopy(n int) {
+	if m := activeMetrics.Load(); m != nil {
+		m.bytesCopied.Add(int64(n))
+	}
+}
@@ -0,0 +1,53 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetrics_CountsOperations tests that counters follow a sequence of operations
+func TestMetrics_CountsOperations(t *testing.T) {
+	m := &Metrics{}
+	prev := SetMetrics(m)
+	defer SetMetrics(prev)
+
+	r := New("hello world")
+	r, err := r.Insert(5, ",")
+	require.NoError(t, err)
+	r, err = r.Delete(0, 1)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = r.CharAt(i)
+		require.NoError(t, err)
+	}
+	_, err = r.Slice(0, 4)
+	require.NoError(t, err)
+	_ = r.String()
+
+	assert.Equal(t, MetricsSnapshot{
+		Inserts:     1,
+		Deletes:     1,
+		Slices:      1,
+		CharAts:     3,
+		BytesCopied: 1 + 4 + 11, // Deleted "h", the slice and the whole text
+	}, m.Snapshot())
+
+	m.Reset()
+	assert.Equal(t, MetricsSnapshot{}, m.Snapshot())
+}
+
+// TestMetrics_Disabled tests that nothing is counted without a collector
+func TestMetrics_Disabled(t *testing.T) {
+	m := &Metrics{}
+	prev := SetMetrics(m)
+	assert.Same(t, m, SetMetrics(nil))
+
+	r, err := New("abc").Insert(0, "x")
+	require.NoError(t, err)
+	_, _ = r.CharAt(0)
+	assert.Equal(t, MetricsSnapshot{}, m.Snapshot())
+
+	SetMetrics(prev)
+}
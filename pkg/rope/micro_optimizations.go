@@ -105,7 +105,7 @@ func (r *Rope) SliceFast(start, end int) (string, error) {
 
 	// Fast path 3: Single leaf
 	if r.root.IsLeaf() {
-		leaf := r.root.(*LeafNode)
+		leaf := asLeafNode(r.root)
 		return sliceSingleLeaf(leaf, start, end), nil
 	}
 
@@ -127,29 +127,21 @@ func (r *Rope) SliceToRope(start, end int) (*Rope, error) {
 // insertIntoSingleLeaf optimizes insertion into a single leaf.
 // This avoids tree traversal overhead.
 func insertIntoSingleLeaf(r *Rope, pos int, text string) *Rope {
-	leaf := r.root.(*LeafNode)
+	leaf := asLeafNode(r.root)
 
 	// Fast path for small insertions at string boundaries
 	if pos == 0 {
 		// Prepend to leaf
 		newLeaf := AcquireLeaf()
-		newLeaf.text = text + leaf.text
-		return &Rope{
-			root:   newLeaf,
-			length: r.length + utf8.RuneCountInString(text),
-			size:   r.size + len(text),
-		}
+		newLeaf.setText(text + leaf.text)
+		return r.derive(newLeaf, r.length+utf8.RuneCountInString(text), r.size+len(text))
 	}
 
 	if pos == r.length {
 		// Append to leaf
 		newLeaf := AcquireLeaf()
-		newLeaf.text = leaf.text + text
-		return &Rope{
-			root:   newLeaf,
-			length: r.length + utf8.RuneCountInString(text),
-			size:   r.size + len(text),
-		}
+		newLeaf.setText(leaf.text + text)
+		return r.derive(newLeaf, r.length+utf8.RuneCountInString(text), r.size+len(text))
 	}
 
 	// Standard insertion in middle
@@ -159,11 +151,11 @@ func insertIntoSingleLeaf(r *Rope, pos int, text string) *Rope {
 
 // deleteFromSingleLeaf optimizes deletion from a single leaf.
 func deleteFromSingleLeaf(r *Rope, start, end int) *Rope {
-	leaf := r.root.(*LeafNode)
+	leaf := asLeafNode(r.root)
 
 	// Fast path: Delete entire content
 	if start == 0 && end == r.length {
-		return Empty()
+		return r.derive(newLeafNode(""), 0, 0)
 	}
 
 	// Fast path: Delete from beginning
@@ -171,12 +163,8 @@ func deleteFromSingleLeaf(r *Rope, start, end int) *Rope {
 		newLeaf := AcquireLeaf()
 		// Find byte position
 		endByte := findBytePosInString(leaf.text, end)
-		newLeaf.text = leaf.text[endByte:]
-		return &Rope{
-			root:   newLeaf,
-			length: r.length - utf8.RuneCountInString(leaf.text[:endByte]),
-			size:   r.size - endByte,
-		}
+		newLeaf.setText(leaf.text[endByte:])
+		return r.derive(newLeaf, r.length-utf8.RuneCountInString(leaf.text[:endByte]), r.size-endByte)
 	}
 
 	// Fast path: Delete from end
@@ -184,12 +172,8 @@ func deleteFromSingleLeaf(r *Rope, start, end int) *Rope {
 		newLeaf := AcquireLeaf()
 		// Find byte position
 		startByte := findBytePosInString(leaf.text, start)
-		newLeaf.text = leaf.text[:startByte]
-		return &Rope{
-			root:   newLeaf,
-			length: start,
-			size:   startByte,
-		}
+		newLeaf.setText(leaf.text[:startByte])
+		return r.derive(newLeaf, start, startByte)
 	}
 
 	// Standard deletion
@@ -245,6 +229,7 @@ func findBytePosInString(s string, charPos int) int {
 
 // BatchInsert performs multiple insertions efficiently.
 // Positions are relative to the original rope (not updated after each insertion).
+// It is InsertBatch without the ChangeSet.
 func (r *Rope) BatchInsert(inserts []Insertion) (*Rope, error) {
 	if len(inserts) == 0 {
 		return r, nil
@@ -255,26 +240,45 @@ func (r *Rope) BatchInsert(inserts []Insertion) (*Rope, error) {
 		return r.InsertFast(inserts[0].Pos, inserts[0].Text)
 	}
 
-	// Sort inserts by position (descending to avoid position recalculation)
-	sortedInserts := make([]Insertion, len(inserts))
-	copy(sortedInserts, inserts)
-
-	// Use efficient sorting for larger arrays
-	sort.Slice(sortedInserts, func(i, j int) bool {
-		return sortedInserts[i].Pos > sortedInserts[j].Pos // Descending order
-	})
+	result, _, err := r.InsertBatch(inserts)
+	return result, err
+}
 
-	// Apply inserts from right to left (positions stay valid)
-	result := r
-	var err error
-	for _, ins := range sortedInserts {
-		result, err = result.InsertFast(ins.Pos, ins.Text)
-		if err != nil {
-			return nil, err
+// InsertBatch performs multiple insertions as one edit, such as filling in
+// the placeholders of a snippet, and also returns the ChangeSet of the edit.
+// Positions are relative to the original rope; insertions at the same
+// position keep the order in which they are given.
+//
+// Example:
+//
+//	r2, cs, _ := rope.New("f(, )").InsertBatch([]rope.Insertion{
+//	    {Pos: 2, Text: "a"},
+//	    {Pos: 4, Text: "b"},
+//	})
+//	fmt.Println(r2.String()) // "f(a, b)"
+func (r *Rope) InsertBatch(inserts []Insertion) (*Rope, *ChangeSet, error) {
+	if r == nil {
+		r = Empty()
+	}
+
+	edits := make([]EditOperation, 0, len(inserts))
+	for _, ins := range inserts {
+		if ins.Pos < 0 || ins.Pos > r.Length() {
+			return nil, nil, &ErrOutOfBounds{
+				Operation: "InsertBatch",
+				Position:  ins.Pos,
+				Min:       0,
+				Max:       r.Length() + 1,
+			}
+		}
+		if ins.Text != "" {
+			edits = append(edits, EditOperation{From: ins.Pos, To: ins.Pos, Text: ins.Text})
 		}
 	}
-
-	return result, nil
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].From < edits[j].From
+	})
+	return r.applyEdits(edits)
 }
 
 // BatchDelete performs multiple deletions efficiently.
@@ -317,7 +321,7 @@ type Insertion struct {
 	Text string
 }
 
-// Range represents a range [Start, End).
+// ByteRange represents a range of byte offsets [Start, End).
 type ByteRange struct {
 	Start int
 	End   int
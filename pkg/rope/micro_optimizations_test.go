@@ -1,9 +1,11 @@
 package rope
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestInsertFast_BasicInsertion tests basic InsertFast operations
@@ -406,6 +408,45 @@ func TestBatchInsert_WithUnicode(t *testing.T) {
 	assert.Equal(t, "A你好B🌍C", result.String())
 }
 
+// TestInsertBatch_Snippet tests inserting several snippets as one edit
+func TestInsertBatch_Snippet(t *testing.T) {
+	r := New("for  :=  {\n}")
+
+	result, cs, err := r.InsertBatch([]Insertion{
+		{Pos: 11, Text: "\tbody()\n"},
+		{Pos: 4, Text: "i"},
+		{Pos: 8, Text: "range xs"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "for i := range xs {\n\tbody()\n}", result.String())
+
+	assert.Equal(t, `retain 4, insert "i", retain 4, insert "range xs", retain 3, insert "\tbody()\n", retain 1`, cs.String())
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+
+	// Original positions map past the inserted text
+	assert.Equal(t, 5, cs.MapPosition(4, AssocAfter))
+	assert.Equal(t, 17, cs.MapPosition(8, AssocAfter))
+}
+
+// TestInsertBatch_SamePosition tests that inserts at one position keep their order
+func TestInsertBatch_SamePosition(t *testing.T) {
+	r := New("ab")
+
+	result, _, err := r.InsertBatch([]Insertion{{Pos: 1, Text: "1"}, {Pos: 1, Text: "2"}, {Pos: 0, Text: "0"}})
+	require.NoError(t, err)
+	assert.Equal(t, "0a12b", result.String())
+
+	same, cs, err := r.InsertBatch(nil)
+	require.NoError(t, err)
+	assert.Same(t, r, same)
+	assert.Equal(t, 2, cs.LenAfter())
+
+	_, _, err = r.InsertBatch([]Insertion{{Pos: 0, Text: "x"}, {Pos: 3, Text: "y"}})
+	assert.Error(t, err)
+}
+
 // TestBatchDelete_SingleDeletion tests single deletion via BatchDelete
 func TestBatchDelete_SingleDeletion(t *testing.T) {
 	r := New("Hello Beautiful World")
@@ -673,3 +714,94 @@ func TestRange_FromTo(t *testing.T) {
 	assert.Equal(t, 5, r.From())
 	assert.Equal(t, 10, r.To())
 }
+
+// TestASCIIFlag_Propagation tests that ASCII flags follow leaf content through edits
+func TestASCIIFlag_Propagation(t *testing.T) {
+	r := New(strings.Repeat("plain ascii text\n", 200))
+	assert.True(t, nodeIsASCII(r.root))
+
+	mixed, err := r.Insert(1000, "naïve")
+	require.NoError(t, err)
+	assert.False(t, nodeIsASCII(mixed.root))
+	assert.True(t, nodeIsASCII(r.root), "the original rope keeps its flag")
+
+	restored, err := mixed.Delete(1000, 1005)
+	require.NoError(t, err)
+	assert.True(t, nodeIsASCII(restored.root))
+	assert.Equal(t, r.String(), restored.String())
+
+	// Balancing and the pooled single-leaf paths keep the flags accurate
+	assert.False(t, nodeIsASCII(mixed.Balance().root))
+	assert.False(t, nodeIsASCII(insertIntoSingleLeaf(New("abc"), 3, "é").root))
+	assert.True(t, nodeIsASCII(insertIntoSingleLeaf(New("abc"), 0, "x").root))
+}
+
+// TestASCIIFastPaths_MatchDecoding tests ASCII fast paths against mixed text
+func TestASCIIFastPaths_MatchDecoding(t *testing.T) {
+	for _, text := range []string{
+		strings.Repeat("abcdefghij", 300),
+		strings.Repeat("abcdéfghij", 300),
+		strings.Repeat("abcdefghij", 150) + "日本" + strings.Repeat("abcdefghij", 150),
+	} {
+		r := New(text)
+		runes := []rune(text)
+		for _, pos := range []int{0, 1, 999, 1500, 1501, len(runes) - 1} {
+			ch, err := r.CharAt(pos)
+			require.NoError(t, err)
+			assert.Equal(t, runes[pos], ch, "CharAt(%d)", pos)
+
+			slice, err := r.Slice(pos, min(pos+20, len(runes)))
+			require.NoError(t, err)
+			assert.Equal(t, string(runes[pos:min(pos+20, len(runes))]), slice)
+
+			assert.Equal(t, len(string(runes[:pos])), r.CharToByte(pos))
+			assert.Equal(t, pos, r.ByteToChar(len(string(runes[:pos]))))
+		}
+
+		edited, err := r.Insert(1234, "XYZ")
+		require.NoError(t, err)
+		edited, err = edited.Delete(10, 20)
+		require.NoError(t, err)
+		want := string(runes[:10]) + string(runes[20:1234]) + "XYZ" + string(runes[1234:])
+		assert.Equal(t, want, edited.String())
+		assert.Equal(t, len([]rune(want)), edited.Length())
+	}
+}
+
+func benchmarkCharAtSlice(b *testing.B, r *Rope) {
+	n := r.Length()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pos := (i * 7919) % (n - 64)
+		r.CharAt(pos)
+		r.Slice(pos, pos+64)
+	}
+}
+
+// BenchmarkCharAtSlice_ASCII measures lookups on an all-ASCII document
+func BenchmarkCharAtSlice_ASCII(b *testing.B) {
+	benchmarkCharAtSlice(b, New(strings.Repeat("The quick brown fox jumps over the lazy dog.\n", 20000)))
+}
+
+// BenchmarkCharAtSlice_Mixed measures lookups on a document with non-ASCII text
+func BenchmarkCharAtSlice_Mixed(b *testing.B) {
+	benchmarkCharAtSlice(b, New(strings.Repeat("The quick brown fox jumps över the lazy dög.\n", 20000)))
+}
+
+// BenchmarkInsert_ASCII measures insertions into an all-ASCII document
+func BenchmarkInsert_ASCII(b *testing.B) {
+	r := New(strings.Repeat("The quick brown fox jumps over the lazy dog.\n", 20000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Insert((i*7919)%r.Length(), "x")
+	}
+}
+
+// BenchmarkInsert_Mixed measures insertions into a document with non-ASCII text
+func BenchmarkInsert_Mixed(b *testing.B) {
+	r := New(strings.Repeat("The quick brown fox jumps över the lazy dög.\n", 20000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Insert((i*7919)%r.Length(), "x")
+	}
+}
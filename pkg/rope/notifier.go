@@ -0,0 +1,170 @@
+package rope
+
+import (
+	"sync"
+)
+
+// BackpressurePolicy decides what a Notifier does when a subscriber has
+// not kept up and its buffer is full.
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock makes Commit wait until the subscriber has room
+	// (or unsubscribes). No transaction is lost, but Commit waits while
+	// holding the notifier's lock, so one slow subscriber also stalls
+	// Snapshot, Subscribe and delivery to every other subscriber until it
+	// receives or cancels its subscription.
+	BackpressureBlock BackpressurePolicy = iota
+
+	// BackpressureDropOldest discards the oldest undelivered transaction to
+	// make room, so slow subscribers never hold up editing.
+	BackpressureDropOldest
+)
+
+// DefaultSubscriberBuffer is the channel buffer used when NewNotifier is
+// given a buffer size of zero or less.
+const DefaultSubscriberBuffer = 64
+
+// Notifier holds a document and delivers every transaction committed to it
+// to its subscribers, for features such as live previews or relaying edits
+// to collaborators.
+//
+// Each subscriber receives transactions in commit order. Notifier is safe
+// for concurrent use.
+type Notifier struct {
+	mu       sync.Mutex
+	doc      *Rope
+	revision uint64
+	buffer   int
+	policy   BackpressurePolicy
+	subs     map[*subscriber]struct{}
+}
+
+// subscriber is a single subscription to a Notifier.
+type subscriber struct {
+	ch   chan *Transaction
+	done chan struct{}
+	once sync.Once
+}
+
+// NewNotifier creates a notifier for doc whose subscribers get channels
+// with the given buffer size and the given backpressure policy.
+func NewNotifier(doc *Rope, buffer int, policy BackpressurePolicy) *Notifier {
+	if doc == nil {
+		doc = Empty()
+	}
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+	return &Notifier{
+		doc:    doc,
+		buffer: buffer,
+		policy: policy,
+		subs:   make(map[*subscriber]struct{}),
+	}
+}
+
+// Snapshot returns the current document and its revision, the number of
+// transactions committed so far.
+func (n *Notifier) Snapshot() (*Rope, uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.doc, n.revision
+}
+
+// Commit applies tx to the document, delivers it to every subscriber and
+// returns the new document. With BackpressureBlock it waits for every
+// subscriber to have room for tx.
+func (n *Notifier) Commit(tx *Transaction) (*Rope, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	doc, err := tx.Apply(n.doc)
+	if err != nil {
+		return nil, err
+	}
+	n.doc = doc
+	n.revision++
+
+	for sub := range n.subs {
+		n.deliver(sub, tx)
+	}
+	return doc, nil
+}
+
+// deliver sends tx to sub according to the backpressure policy.
+// The caller holds n.mu, so deliveries never interleave.
+func (n *Notifier) deliver(sub *subscriber, tx *Transaction) {
+	if n.policy == BackpressureBlock {
+		select {
+		case sub.ch <- tx:
+		case <-sub.done:
+		}
+		return
+	}
+
+	for {
+		select {
+		case sub.ch <- tx:
+			return
+		default:
+		}
+		// Full: drop the oldest pending transaction and try again
+		select {
+		case <-sub.ch:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the transactions committed from now
+// on, all of them unless BackpressureDropOldest drops some, and a function
+// that ends the subscription and closes the channel. Use
+// SnapshotAndSubscribe to also get the document the transactions apply to.
+func (n *Notifier) Subscribe() (<-chan *Transaction, func()) {
+	_, _, ch, cancel := n.SnapshotAndSubscribe()
+	return ch, cancel
+}
+
+// SnapshotAndSubscribe atomically takes a snapshot and subscribes, so that
+// no transaction committed after the snapshot is missed and none is already
+// included in it. The first transaction received is the one making
+// revision+1.
+//
+// With BackpressureBlock, applying the received transactions in order to
+// the snapshot reproduces the document exactly. With
+// BackpressureDropOldest a slow subscriber loses transactions and can no
+// longer follow the document this way; it has to cancel and call
+// SnapshotAndSubscribe again, e.g. when a transaction fails to apply.
+//
+// Example:
+//
+//	doc, rev, changes, cancel := n.SnapshotAndSubscribe()
+//	defer cancel()
+//	for tx := range changes {
+//	    rev++
+//	    doc, _ = tx.Apply(doc)
+//	}
+func (n *Notifier) SnapshotAndSubscribe() (*Rope, uint64, <-chan *Transaction, func()) {
+	sub := &subscriber{
+		ch:   make(chan *Transaction, n.buffer),
+		done: make(chan struct{}),
+	}
+
+	n.mu.Lock()
+	n.subs[sub] = struct{}{}
+	doc, revision := n.doc, n.revision
+	n.mu.Unlock()
+
+	cancel := func() {
+		sub.once.Do(func() {
+			// Release a Commit blocked on this subscriber before locking
+			close(sub.done)
+			n.mu.Lock()
+			delete(n.subs, sub)
+			n.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+	return doc, revision, sub.ch, cancel
+}
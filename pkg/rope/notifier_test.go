@@ -0,0 +1,174 @@
+package rope
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appendTx returns a transaction appending text to a document of length n.
+func appendTx(n int, text string) *Transaction {
+	return NewTransaction(NewChangeSet(n).Retain(n).Insert(text))
+}
+
+// drain applies every transaction received on ch to doc.
+func drain(t *testing.T, doc *Rope, ch <-chan *Transaction) *Rope {
+	for tx := range ch {
+		var err error
+		doc, err = tx.Apply(doc)
+		require.NoError(t, err)
+	}
+	return doc
+}
+
+// TestNotifier_TwoSubscribers tests that every subscriber gets every edit in order
+func TestNotifier_TwoSubscribers(t *testing.T) {
+	n := NewNotifier(New("start"), 0, BackpressureBlock)
+	first, cancelFirst := n.Subscribe()
+	second, cancelSecond := n.Subscribe()
+
+	var received [2][]string
+	var wg sync.WaitGroup
+	for i, ch := range []<-chan *Transaction{first, second} {
+		wg.Add(1)
+		go func(i int, ch <-chan *Transaction) {
+			defer wg.Done()
+			for tx := range ch {
+				ops := tx.Changes().Operations()
+				received[i] = append(received[i], ops[len(ops)-1].Text)
+			}
+		}(i, ch)
+	}
+
+	var want []string
+	for i := 0; i < 200; i++ {
+		doc, _ := n.Snapshot()
+		text := fmt.Sprintf(" %d", i)
+		want = append(want, text)
+		_, err := n.Commit(appendTx(doc.Length(), text))
+		require.NoError(t, err)
+	}
+	cancelFirst()
+	cancelSecond()
+	wg.Wait()
+
+	assert.Equal(t, want, received[0])
+	assert.Equal(t, want, received[1])
+
+	doc, rev := n.Snapshot()
+	assert.Equal(t, uint64(200), rev)
+	assert.True(t, doc.EndsWith(" 198 199"))
+}
+
+// TestNotifier_SnapshotAndSubscribe tests that no edit is lost or repeated around the snapshot
+func TestNotifier_SnapshotAndSubscribe(t *testing.T) {
+	n := NewNotifier(Empty(), 0, BackpressureBlock)
+
+	halfway := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			if i == 250 {
+				close(halfway)
+			}
+			doc, _ := n.Snapshot()
+			if _, err := n.Commit(appendTx(doc.Length(), "x")); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Subscribe while the writer is still committing
+	<-halfway
+	snapshot, rev, ch, cancel := n.SnapshotAndSubscribe()
+	assert.GreaterOrEqual(t, rev, uint64(250))
+
+	// Receive concurrently, as BackpressureBlock makes the writer wait for us
+	received := make(chan *Rope)
+	go func() {
+		doc := snapshot
+		for tx := range ch {
+			doc, _ = tx.Apply(doc)
+		}
+		received <- doc
+	}()
+	<-done
+	cancel()
+
+	final, finalRev := n.Snapshot()
+	result := <-received
+	assert.Equal(t, final.String(), result.String())
+	assert.Equal(t, int(finalRev-rev), result.Length()-snapshot.Length())
+}
+
+// TestNotifier_DropOldest tests that a full subscriber keeps only the newest edits
+func TestNotifier_DropOldest(t *testing.T) {
+	n := NewNotifier(Empty(), 2, BackpressureDropOldest)
+	ch, cancel := n.Subscribe()
+
+	for i := 0; i < 5; i++ {
+		doc, _ := n.Snapshot()
+		_, err := n.Commit(appendTx(doc.Length(), fmt.Sprint(i)))
+		require.NoError(t, err)
+	}
+	cancel()
+
+	var got []string
+	for tx := range ch {
+		got = append(got, tx.Changes().Operations()[1].Text)
+	}
+	assert.Equal(t, []string{"3", "4"}, got)
+}
+
+// TestNotifier_UnsubscribeUnblocksCommit tests that cancelling releases a blocked Commit
+func TestNotifier_UnsubscribeUnblocksCommit(t *testing.T) {
+	n := NewNotifier(Empty(), 1, BackpressureBlock)
+	_, cancel := n.Subscribe()
+
+	committed := make(chan struct{})
+	go func() {
+		defer close(committed)
+		for i := 0; i < 3; i++ {
+			doc, _ := n.Snapshot()
+			_, err := n.Commit(appendTx(doc.Length(), "x"))
+			assert.NoError(t, err)
+		}
+	}()
+
+	select {
+	case <-committed:
+		t.Fatal("Commit should block while the subscriber is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-committed:
+	case <-time.After(time.Second):
+		t.Fatal("Commit still blocked after unsubscribing")
+	}
+	cancel() // Cancelling twice is harmless
+
+	doc, rev := n.Snapshot()
+	assert.Equal(t, "xxx", doc.String())
+	assert.Equal(t, uint64(3), rev)
+}
+
+// TestNotifier_CommitError tests that a failed transaction is not delivered
+func TestNotifier_CommitError(t *testing.T) {
+	n := NewNotifier(New("abc"), 0, BackpressureBlock)
+	ch, cancel := n.Subscribe()
+
+	_, err := n.Commit(NewTransaction(NewChangeSet(3).Retain(1).Delete(10)))
+	assert.Error(t, err)
+	cancel()
+
+	assert.Empty(t, drain(t, Empty(), ch).String())
+	_, rev := n.Snapshot()
+	assert.Equal(t, uint64(0), rev)
+}
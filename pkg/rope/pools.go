@@ -35,7 +35,7 @@ var globalNodePool = &NodePool{
 func AcquireLeaf() *LeafNode {
 	node := globalNodePool.leafPool.Get().(*LeafNode)
 	// Reset text to empty
-	node.text = ""
+	node.setText("")
 	return node
 }
 
@@ -54,6 +54,8 @@ func AcquireInternal() *InternalNode {
 	node.right = nil
 	node.length = 0
 	node.size = 0
+	node.lines = 0
+	node.ascii = false
 	return node
 }
 
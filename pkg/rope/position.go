@@ -3,17 +3,26 @@ package rope
 import (
 	"sort"
 	"time"
+	"unicode/utf8"
 )
 
 // Assoc represents cursor association behavior for operations.
 // This determines how the cursor position should be adjusted after edits.
+//
+// The association decides the tie when text is inserted exactly at a
+// position: AssocAfter, AssocAfterWord and AssocAfterSticky move the
+// position after the inserted text, the other associations keep it before.
+// ChangeSet.MapPosition and PositionMapper follow this rule whether the
+// positions are sorted or not.
 type Assoc int
 
 const (
-	// AssocBefore places cursor before the inserted/deleted text
+	// AssocBefore places cursor before the inserted/deleted text.
+	// Text inserted exactly at the cursor ends up after it.
 	AssocBefore Assoc = iota
 
-	// AssocAfter places cursor after the inserted/deleted text
+	// AssocAfter places cursor after the inserted/deleted text.
+	// Text inserted exactly at the cursor ends up before it.
 	AssocAfter
 
 	// AssocBeforeWord places cursor at the start of the word before the position
@@ -197,79 +206,112 @@ func (pm *PositionMapper) isSorted() bool {
 }
 
 // mapSorted maps positions in O(N+M) time using single pass.
+// Operations that lie entirely before a position are skipped once and
+// never revisited for the following (larger) positions.
 func (pm *PositionMapper) mapSorted() []int {
 	result := make([]int, len(pm.positions))
+	ops := pm.changeset.operations
 
-	// Process each position independently
+	opIdx, oldPos, newPos := 0, 0, 0
 	for i, position := range pm.positions {
-		targetPos := position.Pos
+		target := position.Pos
 
-		// Reset state for each position
-		oldPos := 0
-		newPos := 0
-
-		// Debug: print initial state for this position
-		// fmt.Printf("[Position %d] target=%d, oldPos=%d, newPos=%d\n", i, targetPos, oldPos, newPos)
-
-		// Process operations until we reach or pass targetPos
-		for _, op := range pm.changeset.operations {
-			// Stop if we've passed target in old document
-			// Exception: Inserts don't consume old document, so continue processing them
-			if oldPos > targetPos {
-				break
-			}
-			// Stop if we've reached target via Retain/Delete (but allow Inserts at target)
-			if oldPos == targetPos && op.OpType != OpInsert {
-				break
-			}
-
-			switch op.OpType {
-			case OpRetain:
-				if oldPos+op.Length >= targetPos {
-					// Target is within this retain
-					advance := targetPos - oldPos
-					oldPos += advance
-					newPos += advance
+		// Skip operations that end before the target
+		for opIdx < len(ops) {
+			op := ops[opIdx]
+			if op.OpType == OpInsert {
+				if oldPos >= target {
 					break
-				} else {
-					// Entire retain is before target
-					oldPos += op.Length
-					newPos += op.Length
 				}
-
-			case OpDelete:
-				if oldPos+op.Length > targetPos {
-					// Target is within this delete (not at the end)
-					// Delete it, but don't advance oldPos past target
-					oldPos = targetPos
+				newPos += utf8.RuneCountInString(op.Text)
+			} else {
+				// A deletion ending at the target decides where it maps
+				end := oldPos + op.Length
+				if end > target || (end == target && op.OpType == OpDelete) {
 					break
-				} else {
-					// Entire delete is before or at target
-					oldPos += op.Length
 				}
-
-			case OpInsert:
-				// Inserted content affects newPos but not oldPos
-				insertLen := len([]rune(op.Text))
-				newPos += insertLen
+				oldPos += op.Length
+				if op.OpType == OpRetain {
+					newPos += op.Length
+				}
 			}
+			opIdx++
+		}
+
+		result[i] = pm.mapFrom(position, opIdx, oldPos, newPos)
+	}
+
+	return result
+}
+
+// mapFrom maps a position through the changeset operations starting at
+// ops[opIdx], where oldPos and newPos are the old and new document offsets
+// at the start of that operation.
+//
+// Insertions exactly at the position are the tie-break: AssocAfter (and
+// AssocAfterWord, AssocAfterSticky) moves the position after the inserted
+// text, every other association keeps it before. A position inside or at
+// the end of a deleted range maps to the end of any text inserted in its
+// place. Positions at the start or in the interior of a same-size
+// replacement map linearly, whatever their association, so that mapping
+// stays monotonic across the replacement.
+func (pm *PositionMapper) mapFrom(position *Position, opIdx, oldPos, newPos int) int {
+	target := position.Pos
+	after := assocInsertsAfter(position.Assoc)
+	ops := pm.changeset.operations
+	replaced := false
+
+	for ; opIdx < len(ops); opIdx++ {
+		op := ops[opIdx]
+		if oldPos > target && op.OpType != OpInsert {
+			break
 		}
 
-		// If we ran out of operations but haven't reached targetPos,
-		// the remaining characters are retained (no more changes)
-		if oldPos < targetPos {
-			remaining := targetPos - oldPos
-			newPos += remaining
-			oldPos += remaining
-			// fmt.Printf("  Remaining: remaining=%d, oldPos=%d, newPos=%d\n", remaining, oldPos, newPos)
+		// Only the insert directly after the deletion containing the target
+		// replaces it
+		if op.OpType != OpInsert {
+			replaced = false
 		}
 
-		// Apply association behavior
-		result[i] = pm.applyAssociation(position, targetPos, newPos, oldPos)
-		// fmt.Printf("  Result[%d] = %d\n\n", i, result[i])
+		switch op.OpType {
+		case OpRetain:
+			if oldPos+op.Length > target {
+				newPos += target - oldPos
+				return pm.applyAssociation(position, target, newPos, target)
+			}
+			oldPos += op.Length
+			newPos += op.Length
+
+		case OpDelete:
+			if target >= oldPos && target < oldPos+op.Length && isSameSizeReplacement(ops, opIdx) {
+				newPos += target - oldPos
+				return pm.applyAssociation(position, target, newPos, target)
+			}
+			if target > oldPos && target <= oldPos+op.Length {
+				replaced = true
+			}
+			oldPos += op.Length
+
+		case OpInsert:
+			if oldPos >= target && !after && !replaced {
+				return pm.applyAssociation(position, target, newPos, oldPos)
+			}
+			newPos += utf8.RuneCountInString(op.Text)
+		}
 	}
 
-	return result
+	// Characters past the last operation are implicitly retained
+	if oldPos < target {
+		newPos += target - oldPos
+		oldPos = target
+	}
+	return pm.applyAssociation(position, target, newPos, oldPos)
+}
+
+// assocInsertsAfter reports whether an association places a position after
+// text inserted exactly at it.
+func assocInsertsAfter(assoc Assoc) bool {
+	return assoc == AssocAfter || assoc == AssocAfterWord || assoc == AssocAfterSticky
 }
 
 // applyAssociation applies the association behavior to determine final position.
@@ -329,43 +371,17 @@ func (pm *PositionMapper) mapUnsorted() []int {
 
 // mapSinglePosition maps a single position through the changeset.
 func (pm *PositionMapper) mapSinglePosition(position *Position) int {
-	pos := 0
-	newPos := 0
-	oldPos := position.Pos
-
-	for _, op := range pm.changeset.operations {
-		switch op.OpType {
-		case OpRetain:
-			if pos+op.Length >= oldPos {
-				// Position is within this retain
-				newPos += (oldPos - pos)
-				return pm.applyAssociation(position, oldPos, newPos, oldPos)
-			}
-			pos += op.Length
-			newPos += op.Length
-
-		case OpDelete:
-			if pos+op.Length >= oldPos {
-				// Position is within deleted range
-				// Apply association to determine where to place cursor
-				return pm.applyAssociation(position, oldPos, newPos, pos)
-			}
-			pos += op.Length
-
-		case OpInsert:
-			if pos >= oldPos {
-				// Already past the position
-				return pm.applyAssociation(position, oldPos, newPos, pos)
-			}
-			newPos += len([]rune(op.Text))
-		}
+	return pm.mapFrom(position, 0, 0, 0)
+}
 
-		if pos >= oldPos {
-			break
-		}
+// isSameSizeReplacement reports whether ops[i] is a delete immediately
+// followed by an insert of the same number of characters, such as a
+// hex-edit of a byte or a toggled flag character.
+func isSameSizeReplacement(ops []Operation, i int) bool {
+	if i+1 >= len(ops) || ops[i].OpType != OpDelete || ops[i+1].OpType != OpInsert {
+		return false
 	}
-
-	return newPos
+	return utf8.RuneCountInString(ops[i+1].Text) == ops[i].Length
 }
 
 // MapPositions is a convenience function to map positions through a changeset.
@@ -198,9 +198,9 @@ func TestSelection_MapPositions_Basic(t *testing.T) {
 
 	// Positions should be mapped
 	mappedRanges := mappedSel.Iter()
-	assert.Equal(t, 0, mappedRanges[0].From())    // Position 0 unchanged
-	assert.Greater(t, mappedRanges[1].From(), 5)  // Position 5 shifted
-	assert.Greater(t, mappedRanges[2].From(), 11) // Position 11 shifted
+	assert.Equal(t, 0, mappedRanges[0].From())  // Position 0 unchanged
+	assert.Equal(t, 5, mappedRanges[1].From())  // Position 5 is before the insert
+	assert.Equal(t, 21, mappedRanges[2].From()) // Position 11 shifted
 }
 
 func TestSelection_GetPositions(t *testing.T) {
@@ -415,3 +415,166 @@ func TestPositionMapper_MapVsMapOptimized_Consistency(t *testing.T) {
 	// For sorted input, both should produce identical results
 	assert.Equal(t, result1, result2)
 }
+
+// TestPositionMapper_Monotonic tests that mapping never reorders positions,
+// for random changesets on both the sorted and the unsorted path.
+func TestPositionMapper_Monotonic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for iter := 0; iter < 500; iter++ {
+		length := rng.Intn(12)
+		cs := NewChangeSet(length)
+		for remaining := length; remaining > 0; {
+			n := 1 + rng.Intn(remaining)
+			switch rng.Intn(3) {
+			case 0:
+				cs.Retain(n)
+			case 1:
+				cs.Delete(n)
+			default:
+				cs.Insert(strings.Repeat("x", rng.Intn(4)))
+				continue
+			}
+			remaining -= n
+		}
+		if rng.Intn(2) == 0 {
+			cs.Insert(strings.Repeat("y", 1+rng.Intn(3)))
+		}
+
+		for _, assoc := range []Assoc{AssocBefore, AssocAfter} {
+			sorted := NewPositionMapper(cs)
+			unsorted := NewPositionMapper(cs)
+			for pos := 0; pos <= length; pos++ {
+				sorted.AddPosition(pos, assoc)
+				unsorted.AddPosition(length-pos, assoc)
+			}
+			fromSorted := sorted.MapOptimized()
+			fromUnsorted := unsorted.Map()
+
+			for pos := 0; pos <= length; pos++ {
+				assert.Equal(t, fromSorted[pos], fromUnsorted[length-pos], "ops %v, pos %d", cs.operations, pos)
+				if pos > 0 {
+					assert.LessOrEqual(t, fromSorted[pos-1], fromSorted[pos], "ops %v, assoc %v, pos %d", cs.operations, assoc, pos)
+				}
+			}
+		}
+	}
+}
+
+func TestPositionMapper_ReplacementsInSequence(t *testing.T) {
+	// Position 2 ends the first replacement and starts the second, so it
+	// stays before the text inserted by the second one
+	cs := NewChangeSet(4).Delete(2).Insert("X").Delete(2).Insert("YZW")
+
+	assert.Equal(t, 1, cs.MapPosition(2, AssocBefore))
+	assert.Equal(t, 4, cs.MapPosition(3, AssocBefore))
+	assert.Equal(t, 4, cs.MapPosition(2, AssocAfter))
+
+	mapper := NewPositionMapper(cs)
+	mapper.AddPosition(3, AssocBefore).AddPosition(2, AssocBefore)
+	assert.Equal(t, []int{4, 1}, mapper.Map())
+}
+
+// ========== Same-Size Replacement Tests ==========
+
+func TestPositionMapper_SameSizeReplacement(t *testing.T) {
+	doc := New("flag=off;")
+
+	// Replace "off" with "ONN": same length, so interior positions stay put
+	cs := NewChangeSet(doc.Length()).Retain(5).Delete(3).Insert("ONN")
+
+	assert.Equal(t, 6, cs.MapPosition(6, AssocBefore), "offset 1 inside the replacement")
+	assert.Equal(t, 7, cs.MapPosition(7, AssocAfter), "offset 2 inside the replacement")
+	assert.Equal(t, 5, cs.MapPosition(5, AssocBefore), "start of the replacement")
+	assert.Equal(t, 8, cs.MapPosition(8, AssocBefore), "end of the replacement")
+	assert.Equal(t, 9, cs.MapPosition(9, AssocBefore), "after the replacement")
+
+	// The unsorted path maps the same way
+	mapper := NewPositionMapper(cs)
+	mapper.AddPosition(7, AssocBefore).AddPosition(6, AssocBefore)
+	assert.Equal(t, []int{7, 6}, mapper.Map())
+}
+
+func TestPositionMapper_SameSizeReplacement_Monotonic(t *testing.T) {
+	// Replacing the whole of "ab" with "xb" keeps every position in order,
+	// including the start of the replacement under AssocAfter
+	cs := NewChangeSet(2).Delete(2).Insert("xb")
+
+	for _, assoc := range []Assoc{AssocBefore, AssocAfter} {
+		var got []int
+		for pos := 0; pos <= 2; pos++ {
+			got = append(got, cs.MapPosition(pos, assoc))
+		}
+		assert.Equal(t, []int{0, 1, 2}, got)
+	}
+}
+
+func TestPositionMapper_DifferentSizeReplacement(t *testing.T) {
+	doc := New("flag=off;")
+
+	// A replacement that changes length still collapses interior positions
+	cs := NewChangeSet(doc.Length()).Retain(5).Delete(3).Insert("on")
+	assert.Equal(t, 7, cs.MapPosition(6, AssocBefore))
+}
+
+// ========== Insert Tie-Break Tests ==========
+
+func TestPositionMapper_InsertAtPosition(t *testing.T) {
+	// Insert "XY" at position 3 of "abcdef"
+	cs := NewChangeSet(6).Retain(3).Insert("XY")
+
+	assert.Equal(t, 3, cs.MapPosition(3, AssocBefore), "AssocBefore stays before the insert")
+	assert.Equal(t, 5, cs.MapPosition(3, AssocAfter), "AssocAfter moves after the insert")
+	assert.Equal(t, 2, cs.MapPosition(2, AssocAfter))
+	assert.Equal(t, 6, cs.MapPosition(4, AssocBefore))
+	assert.Equal(t, 8, cs.MapPosition(6, AssocBefore), "trailing text is retained")
+}
+
+func TestPositionMapper_InsertAtPosition_AllPaths(t *testing.T) {
+	cases := []struct {
+		name string
+		cs   *ChangeSet
+	}{
+		{"insert", NewChangeSet(6).Retain(3).Insert("XY")},
+		{"insert at start", NewChangeSet(6).Insert("XY")},
+		{"insert at end", NewChangeSet(6).Retain(6).Insert("XY")},
+		{"delete then insert", NewChangeSet(6).Retain(1).Delete(2).Insert("XYZ")},
+		{"two inserts", NewChangeSet(6).Retain(2).Insert("X").Retain(2).Insert("YZ")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for pos := 0; pos <= 6; pos++ {
+				for _, assoc := range []Assoc{AssocBefore, AssocAfter} {
+					want := tc.cs.MapPosition(pos, assoc)
+
+					// Sorted path, with the position among others
+					sorted := NewPositionMapper(tc.cs).
+						AddPosition(0, AssocBefore).
+						AddPosition(pos, assoc).
+						AddPosition(6, AssocAfter)
+					assert.Equal(t, want, sorted.Map()[1], "sorted pos=%d assoc=%v", pos, assoc)
+
+					// Unsorted path
+					unsorted := NewPositionMapper(tc.cs).
+						AddPosition(6, AssocAfter).
+						AddPosition(pos, assoc).
+						AddPosition(0, AssocBefore)
+					assert.Equal(t, want, unsorted.Map()[1], "unsorted pos=%d assoc=%v", pos, assoc)
+
+					// Optimized path
+					optimized := MapPositionsOptimized(tc.cs, []int{pos}, []Assoc{assoc})
+					assert.Equal(t, want, optimized[0], "optimized pos=%d assoc=%v", pos, assoc)
+				}
+			}
+		})
+	}
+
+	// The tie-break itself
+	cs := cases[1].cs
+	assert.Equal(t, 0, cs.MapPosition(0, AssocBefore))
+	assert.Equal(t, 2, cs.MapPosition(0, AssocAfter))
+	cs = cases[2].cs
+	assert.Equal(t, 6, cs.MapPosition(6, AssocBefore))
+	assert.Equal(t, 8, cs.MapPosition(6, AssocAfter))
+}
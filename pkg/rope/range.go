@@ -38,6 +38,13 @@ func Point(pos int) Range {
 	}
 }
 
+// NewRangeClamped creates a new Range with both positions clamped into [0, docLen].
+// Use it when restoring a saved selection against a document that may have
+// become shorter.
+func NewRangeClamped(anchor, head, docLen int) Range {
+	return NewRange(anchor, head).Clamp(docLen)
+}
+
 // From returns the start of the range (minimum of anchor and head).
 func (r Range) From() int {
 	if r.Anchor < r.Head {
@@ -136,6 +143,16 @@ func (r Range) Map(cs *ChangeSet, assoc Assoc) Range {
 	}
 }
 
+// Clamp returns the range with both positions limited to [0, docLen].
+// The direction of the range is kept unless an endpoint had to move past
+// the other one.
+func (r Range) Clamp(docLen int) Range {
+	clamp := func(pos int) int {
+		return min(max(pos, 0), max(docLen, 0))
+	}
+	return Range{Anchor: clamp(r.Anchor), Head: clamp(r.Head)}
+}
+
 // Merge merges this range with another, producing a range that covers both.
 func (r Range) Merge(other Range) Range {
 	from := r.From()
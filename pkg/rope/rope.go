@@ -81,6 +81,9 @@ package rope
 
 import (
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -122,6 +125,36 @@ type Rope struct {
 	// Cached values for O(1) access
 	length int // Total characters (Unicode code points)
 	size   int // Total bytes
+
+	// classifier overrides DefaultCharClass; see WithCharClassifier
+	classifier func(rune) CharClass
+
+	// lineEnding selects the line breaks of line operations; see WithLineEnding
+	lineEnding LineEndingMode
+
+	// caches holds values computed lazily from the content; see lazyCaches
+	caches atomic.Pointer[ropeCaches]
+}
+
+// ropeCaches holds values computed lazily from a rope's content, which
+// concurrent readers may fill in. Ropes with the same content, such as a
+// rope and its WithLineEnding clone, share them.
+type ropeCaches struct {
+	mu           sync.Mutex
+	byteCache    *ropeByteCache // See GetBytePosCached
+	cacheKey     uint64         // See CacheKey
+	hasCacheKey  bool
+	graphemes    int // See GraphemeLength
+	hasGraphemes bool
+}
+
+// lazyCaches returns r's caches, creating them on first use.
+func (r *Rope) lazyCaches() *ropeCaches {
+	if c := r.caches.Load(); c != nil {
+		return c
+	}
+	r.caches.CompareAndSwap(nil, &ropeCaches{})
+	return r.caches.Load()
 }
 
 // RopeNode is the interface for all rope nodes.
@@ -141,20 +174,100 @@ type RopeNode interface {
 
 // LeafNode stores actual text content.
 type LeafNode struct {
-	text string
+	text    string
+	isASCII bool // Set at construction: character and byte positions agree
+}
+
+// newLeafNode creates a leaf holding text.
+func newLeafNode(text string) *LeafNode {
+	return &LeafNode{text: text, isASCII: IsASCII(text)}
+}
+
+// asLeafNode returns node, a leaf, as a LeafNode for an edit. Leaves of
+// other types, such as MmapLeaf, are copied into a new LeafNode, so edits
+// never write to their backing storage.
+func asLeafNode(node RopeNode) *LeafNode {
+	if leaf, ok := node.(*LeafNode); ok {
+		return leaf
+	}
+	return newLeafNode(node.Slice(0, node.Length()))
+}
+
+// setText replaces the text of a leaf, e.g. one taken from the node pool.
+func (n *LeafNode) setText(text string) {
+	n.text = text
+	n.isASCII = IsASCII(text)
+}
+
+// bytePos returns the byte offset of character position charPos in the leaf.
+func (n *LeafNode) bytePos(charPos int) int {
+	if n.isASCII {
+		return charPos
+	}
+	return findBytePosInString(n.text, charPos)
 }
 
 // InternalNode is an internal node in the rope tree that maintains balance and caches subtree info.
 type InternalNode struct {
 	left   RopeNode
 	right  RopeNode
-	length int // Cached: total characters in left subtree
-	size   int // Cached: total bytes in left subtree
+	length int  // Cached: total characters in left subtree
+	size   int  // Cached: total bytes in left subtree
+	lines  int  // Cached: line breaks ('\n') in left subtree
+	ascii  bool // Cached: the whole subtree is ASCII
+}
+
+// newInternalNode creates an internal node over left and right with its
+// cached left subtree info filled in.
+func newInternalNode(left, right RopeNode) *InternalNode {
+	return &InternalNode{
+		left:   left,
+		right:  right,
+		length: left.Length(),
+		size:   left.Size(),
+		lines:  nodeLineBreaks(left),
+		ascii:  nodeIsASCII(left) && nodeIsASCII(right),
+	}
+}
+
+// nodeIsASCII reports whether node is known to hold only ASCII text.
+func nodeIsASCII(node RopeNode) bool {
+	switch n := node.(type) {
+	case *LeafNode:
+		return n.isASCII
+	case *MmapLeaf:
+		return n.isASCII
+	case *InternalNode:
+		return n.ascii
+	default:
+		return false
+	}
+}
+
+// nodeLineBreaks returns the number of '\n' characters in node. Leaves count
+// on demand like Length; internal nodes only count their right spine.
+func nodeLineBreaks(node RopeNode) int {
+	lines := 0
+	for node != nil {
+		switch n := node.(type) {
+		case *LeafNode:
+			return lines + strings.Count(n.text, "\n")
+		case *InternalNode:
+			lines += n.lines
+			node = n.right
+		default:
+			return lines + strings.Count(node.Slice(0, node.Length()), "\n")
+		}
+	}
+	return lines
 }
 
 // ========== RopeNode Implementations ==========
 
 func (n *LeafNode) Length() int {
+	if n.isASCII {
+		return len(n.text)
+	}
 	return utf8.RuneCountInString(n.text)
 }
 
@@ -163,6 +276,10 @@ func (n *LeafNode) Size() int {
 }
 
 func (n *LeafNode) Slice(start, end int) string {
+	if n.isASCII {
+		return n.text[start:end]
+	}
+
 	// Convert character positions to byte positions without []rune conversion
 	byteStart := 0
 	for i := 0; i < start; i++ {
@@ -218,10 +335,15 @@ func (n *InternalNode) IsLeaf() bool {
 
 // New creates a Rope from the given string.
 //
-// The returned rope is empty if text is "". For non-empty text,
-// this creates a single leaf node containing the entire string.
+// New assumes text is valid UTF-8 and does not check it; invalid bytes
+// throw off character counts and positions. Use NewValidated or NewLossy
+// for text from untrusted sources.
+//
+// The returned rope is empty if text is "". Text longer than
+// DefaultMaxLeafSize bytes is split into a balanced tree of leaves sharing
+// its memory, so line lookups on the result stay logarithmic.
 //
-// Performance: O(1) time, O(len(text)) space
+// Performance: O(n) time for large text, no copying
 //
 // Example:
 //
@@ -231,14 +353,59 @@ func New(text string) *Rope {
 	if text == "" {
 		return Empty()
 	}
+	if len(text) > DefaultMaxLeafSize {
+		return newChunkedRope(text)
+	}
 
 	return &Rope{
-		root:   &LeafNode{text: text},
+		root:   newLeafNode(text),
 		length: utf8.RuneCountInString(text),
 		size:   len(text),
 	}
 }
 
+// NewValidated creates a Rope from the given string like New, but returns
+// an *ErrInvalidUTF8 giving the byte offset of the first invalid sequence
+// if text is not valid UTF-8.
+//
+// Example:
+//
+//	_, err := rope.NewValidated("ok\xe2\x82")
+//	fmt.Println(err) // invalid UTF-8 at byte offset 2
+func NewValidated(text string) (*Rope, error) {
+	if !utf8.ValidString(text) {
+		return nil, &ErrInvalidUTF8{Offset: invalidUTF8Offset(text)}
+	}
+	return New(text), nil
+}
+
+// NewLossy creates a Rope from the given string like New, replacing each
+// run of invalid UTF-8 bytes with U+FFFD (the replacement character).
+//
+// Example:
+//
+//	r := rope.NewLossy("ok\xe2\x82")
+//	fmt.Println(r.String()) // "ok\uFFFD"
+func NewLossy(text string) *Rope {
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, string(utf8.RuneError))
+	}
+	return New(text)
+}
+
+// invalidUTF8Offset returns the byte offset of the first invalid UTF-8
+// sequence in text, or -1 if there is none.
+func invalidUTF8Offset(text string) int {
+	for i := 0; i < len(text); {
+		ch, size := utf8.DecodeRuneInString(text[i:])
+		if ch == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
 // Empty returns an empty Rope.
 //
 // Returns an empty rope that can be used as a starting point for
@@ -254,7 +421,7 @@ func New(text string) *Rope {
 //	fmt.Println(r.String()) // "Hello World"
 func Empty() *Rope {
 	return &Rope{
-		root:   &LeafNode{text: ""},
+		root:   newLeafNode(""),
 		length: 0,
 		size:   0,
 	}
@@ -298,6 +465,29 @@ func (r *Rope) Size() int {
 	return r.size
 }
 
+// IsEmpty returns true if the rope has no characters.
+func (r *Rope) IsEmpty() bool {
+	return r.Length() == 0
+}
+
+// IsBlank returns true if the rope is empty or contains only whitespace.
+// The scan stops at the first non-whitespace character, so a rope that
+// starts with one is answered in O(log n).
+func (r *Rope) IsBlank() bool {
+	return blankRunes(r.NewIterator(), r.Length())
+}
+
+// blankRunes reports whether the next n runes of it are all whitespace,
+// stopping at the first one that is not.
+func blankRunes(it *Iterator, n int) bool {
+	for i := 0; i < n && it.Next(); i++ {
+		if !unicode.IsSpace(it.Current()) {
+			return false
+		}
+	}
+	return true
+}
+
 // String returns the complete content as a string.
 // Uses optimized byte slice building for minimal allocations.
 func (r *Rope) String() string {
@@ -313,6 +503,7 @@ func (r *Rope) String() string {
 	for it.Next() {
 		result = append(result, it.Current()...)
 	}
+	countCopy(len(result))
 
 	return string(result)
 }
@@ -325,6 +516,9 @@ func (r *Rope) Bytes() []byte {
 // Slice returns a substring from start to end (exclusive, in character positions).
 // Returns an error if indices are out of bounds.
 func (r *Rope) Slice(start, end int) (string, error) {
+	if m := activeMetrics.Load(); m != nil {
+		m.slices.Add(1)
+	}
 	if r == nil {
 		return "", nil
 	}
@@ -334,18 +528,41 @@ func (r *Rope) Slice(start, end int) (string, error) {
 	if start == end {
 		return "", nil
 	}
-	return r.root.Slice(start, end), nil
+	text := r.root.Slice(start, end)
+	countCopy(len(text))
+	return text, nil
 }
 
 // CharAt returns the rune at the given character position.
 // Returns an error if position is out of bounds.
 func (r *Rope) CharAt(pos int) (rune, error) {
+	if m := activeMetrics.Load(); m != nil {
+		m.charAts.Add(1)
+	}
 	if r == nil || r.length == 0 {
 		return 0, errCharOutOfBounds(pos, 0)
 	}
 	if pos < 0 || pos >= r.length {
 		return 0, errCharOutOfBounds(pos, r.length)
 	}
+	// ASCII leaves are indexed directly
+	node, offset := r.root, pos
+	for {
+		internal, ok := node.(*InternalNode)
+		if !ok {
+			break
+		}
+		if offset < internal.length {
+			node = internal.left
+		} else {
+			offset -= internal.length
+			node = internal.right
+		}
+	}
+	if leaf, ok := node.(*LeafNode); ok && leaf.isASCII {
+		return rune(leaf.text[offset]), nil
+	}
+
 	// Use optimized iterator instead of []rune conversion
 	it := r.IteratorAt(pos)
 	it.Next() // Advance to the target position
@@ -372,42 +589,32 @@ func (r *Rope) ByteAt(pos int) (byte, error) {
 
 // concatNodes concatenates two nodes and returns a new node.
 func concatNodes(left, right RopeNode) RopeNode {
-	// If one side is empty, return the other
-	if left.Length() == 0 {
+	// If one side is empty (or missing after a split), return the other
+	if left == nil || left.Length() == 0 {
 		return right
 	}
-	if right.Length() == 0 {
+	if right == nil || right.Length() == 0 {
 		return left
 	}
 
-	return &InternalNode{
-		left:   left,
-		right:  right,
-		length: left.Length(),
-		size:   left.Size(),
-	}
+	return newInternalNode(left, right)
 }
 
 // splitNode splits a node at a character position, returning (left, right).
 func splitNode(node RopeNode, pos int) (RopeNode, RopeNode) {
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
-		// Find byte position without []rune conversion
-		splitByte := 0
-		for i := 0; i < pos; i++ {
-			_, size := utf8.DecodeRuneInString(leaf.text[splitByte:])
-			splitByte += size
-		}
+		leaf := asLeafNode(node)
+		splitByte := leaf.bytePos(pos)
 
 		leftText := leaf.text[:splitByte]
 		rightText := leaf.text[splitByte:]
 
 		var left, right RopeNode
 		if leftText != "" {
-			left = &LeafNode{text: leftText}
+			left = newLeafNode(leftText)
 		}
 		if rightText != "" {
-			right = &LeafNode{text: rightText}
+			right = newLeafNode(rightText)
 		}
 
 		return left, right
@@ -428,24 +635,19 @@ func splitNode(node RopeNode, pos int) (RopeNode, RopeNode) {
 // insertNode inserts text at a character position in a node.
 func insertNode(node RopeNode, pos int, text string) RopeNode {
 	if node.Length() == 0 {
-		return &LeafNode{text: text}
+		return newLeafNode(text)
 	}
 
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
-		// Find byte position without []rune conversion
-		insertByte := 0
-		for i := 0; i < pos; i++ {
-			_, size := utf8.DecodeRuneInString(leaf.text[insertByte:])
-			insertByte += size
-		}
+		leaf := asLeafNode(node)
+		insertByte := leaf.bytePos(pos)
 
 		leftPart := leaf.text[:insertByte]
 		rightPart := leaf.text[insertByte:]
 
 		return concatNodes(
-			&LeafNode{text: leftPart + text},
-			&LeafNode{text: rightPart},
+			newLeafNode(leftPart+text),
+			newLeafNode(rightPart),
 		)
 	}
 
@@ -454,21 +656,11 @@ func insertNode(node RopeNode, pos int, text string) RopeNode {
 
 	if pos <= leftLen {
 		newLeft := insertNode(internal.left, pos, text)
-		return &InternalNode{
-			left:   newLeft,
-			right:  internal.right,
-			length: newLeft.Length(),
-			size:   newLeft.Size(),
-		}
+		return newInternalNode(newLeft, internal.right)
 	}
 
 	newRight := insertNode(internal.right, pos-leftLen, text)
-	return &InternalNode{
-		left:   internal.left,
-		right:  newRight,
-		length: internal.left.Length(),
-		size:   internal.left.Size(),
-	}
+	return newInternalNode(internal.left, newRight)
 }
 
 // deleteNode deletes characters from start to end (exclusive) from a node.
@@ -478,22 +670,12 @@ func deleteNode(node RopeNode, start, end int) RopeNode {
 	}
 
 	if node.IsLeaf() {
-		leaf := node.(*LeafNode)
-		// Find byte positions without []rune conversion
-		startByte := 0
-		for i := 0; i < start; i++ {
-			_, size := utf8.DecodeRuneInString(leaf.text[startByte:])
-			startByte += size
-		}
-
-		endByte := startByte
-		for i := start; i < end; i++ {
-			_, size := utf8.DecodeRuneInString(leaf.text[endByte:])
-			endByte += size
-		}
+		leaf := asLeafNode(node)
+		startByte := leaf.bytePos(start)
+		endByte := leaf.bytePos(end)
 
 		newText := leaf.text[:startByte] + leaf.text[endByte:]
-		return &LeafNode{text: newText}
+		return newLeafNode(newText)
 	}
 
 	internal := node.(*InternalNode)
@@ -523,6 +705,9 @@ func deleteNode(node RopeNode, start, end int) RopeNode {
 // The original Rope is unchanged.
 // Returns an error if position is out of bounds.
 func (r *Rope) Insert(pos int, text string) (*Rope, error) {
+	if m := activeMetrics.Load(); m != nil {
+		m.inserts.Add(1)
+	}
 	if r == nil {
 		// Allow insert into nil rope at position 0 only
 		if pos == 0 && text != "" {
@@ -538,17 +723,21 @@ func (r *Rope) Insert(pos int, text string) (*Rope, error) {
 	}
 
 	newRoot := insertNode(r.root, pos, text)
-	return &Rope{
-		root:   newRoot,
-		length: r.length + utf8.RuneCountInString(text),
-		size:   r.size + len(text),
-	}, nil
+	inserted := utf8.RuneCountInString(text)
+	result := r.derive(newRoot, r.length+inserted, r.size+len(text))
+	if byteCache := r.editedByteCache(pos, 0, inserted, len(text)); byteCache != nil {
+		result.caches.Store(&ropeCaches{byteCache: byteCache})
+	}
+	return result, nil
 }
 
 // Delete removes characters from start to end (exclusive) and returns a new Rope.
 // The original Rope is unchanged.
 // Returns an error if range is out of bounds.
 func (r *Rope) Delete(start, end int) (*Rope, error) {
+	if m := activeMetrics.Load(); m != nil {
+		m.deletes.Add(1)
+	}
 	if r == nil {
 		if start == 0 && end == 0 {
 			return nil, nil
@@ -562,19 +751,17 @@ func (r *Rope) Delete(start, end int) (*Rope, error) {
 		return r, nil
 	}
 
-	deletedStr, err := r.Slice(start, end)
-	if err != nil {
-		return nil, err
-	}
+	deletedStr := r.root.Slice(start, end)
+	countCopy(len(deletedStr))
 	deletedLength := utf8.RuneCountInString(deletedStr)
 	deletedSize := len(deletedStr)
 
 	newRoot := deleteNode(r.root, start, end)
-	return &Rope{
-		root:   newRoot,
-		length: r.length - deletedLength,
-		size:   r.size - deletedSize,
-	}, nil
+	result := r.derive(newRoot, r.length-deletedLength, r.size-deletedSize)
+	if byteCache := r.editedByteCache(start, deletedLength, 0, -deletedSize); byteCache != nil {
+		result.caches.Store(&ropeCaches{byteCache: byteCache})
+	}
+	return result, nil
 }
 
 // Replace replaces characters from start to end (exclusive) with text and returns a new Rope.
@@ -602,47 +789,80 @@ func (r *Rope) Split(pos int) (*Rope, *Rope, error) {
 		return nil, nil, errSplitOutOfBounds(pos, r.length)
 	}
 	if pos == 0 {
-		return Empty(), r, nil
+		return r.derive(newLeafNode(""), 0, 0), r, nil
 	}
 	if pos == r.length {
-		return r, Empty(), nil
+		return r, r.derive(newLeafNode(""), 0, 0), nil
 	}
 
 	leftRoot, rightRoot := splitNode(r.root, pos)
+	left := r.derive(leftRoot, pos, leftRoot.Size())
+	right := r.derive(rightRoot, r.length-pos, rightRoot.Size())
+	return left, right, nil
+}
 
-	left := &Rope{
-		root:   leftRoot,
-		length: pos,
-		size:   0, // Will be recalculated
+// Split3 splits the rope at start and end.
+// Returns (left, mid, right) where left contains [0, start), mid contains
+// [start, end) and right contains [end, length). All three share structure
+// with r.
+// Returns an error if the range is invalid.
+func (r *Rope) Split3(start, end int) (left, mid, right *Rope, err error) {
+	if start < 0 || start > end || end > r.Length() {
+		return nil, nil, nil, &ErrInvalidRange{
+			Operation: "Split3",
+			Start:     start,
+			End:       end,
+			ValidMax:  r.Length(),
+		}
 	}
-	left.size = left.root.Size()
 
-	right := &Rope{
-		root:   rightRoot,
-		length: r.length - pos,
-		size:   0, // Will be recalculated
+	left, rest, err := r.Split(start)
+	if err != nil {
+		return nil, nil, nil, err
 	}
-	right.size = right.root.Size()
-
-	return left, right, nil
+	mid, right, err = rest.Split(end - start)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return left, mid, right, nil
 }
 
 // Concat concatenates two ropes and returns a new Rope.
-// The original Ropes are unchanged.
+// The original Ropes are unchanged. The result keeps r's character
+// classifier and line ending mode.
 func (r *Rope) Concat(other *Rope) *Rope {
-	if r == nil || r.length == 0 {
+	if r == nil {
 		return other
 	}
+	if r.length == 0 {
+		return r.keepSettings(other)
+	}
 	if other == nil || other.length == 0 {
 		return r
 	}
 
 	newRoot := concatNodes(r.root, other.root)
-	return &Rope{
-		root:   newRoot,
-		length: r.length + other.length,
-		size:   r.size + other.size,
+	return r.derive(newRoot, r.length+other.length, r.size+other.size)
+}
+
+// derive returns a rope over root that keeps r's settings, its character
+// classifier and line ending mode, for ropes built from r by an edit.
+func (r *Rope) derive(root RopeNode, length, size int) *Rope {
+	result := &Rope{root: root, length: length, size: size}
+	if r != nil {
+		result.classifier, result.lineEnding = r.classifier, r.lineEnding
+	}
+	return result
+}
+
+// keepSettings returns result, a rope built separately by an edit of r such
+// as New(text) when r is empty, with r's settings.
+func (r *Rope) keepSettings(result *Rope) *Rope {
+	if r == nil || result == nil ||
+		(r.classifier == nil && result.classifier == nil && r.lineEnding == result.lineEnding) {
+		return result
 	}
+	return r.derive(result.root, result.length, result.size)
 }
 
 // Clone returns the rope itself (ropes are immutable, no copy needed).
@@ -664,6 +884,25 @@ func (r *Rope) Runes() []rune {
 	return runes
 }
 
+// SliceRunes returns the runes between start and end (exclusive) character positions.
+// Unlike []rune(r.Slice(start, end)), the runes are decoded straight from the
+// leaves into a slice of length end-start, without an intermediate string.
+// Returns an error if the range is out of bounds.
+func (r *Rope) SliceRunes(start, end int) ([]rune, error) {
+	if r == nil {
+		return []rune{}, nil
+	}
+	if start < 0 || end > r.length || start > end {
+		return nil, errSliceOutOfBounds(start, end, r.length)
+	}
+
+	runes := make([]rune, end-start)
+	if start < end {
+		collectRunes(r.root, start, end, runes)
+	}
+	return runes, nil
+}
+
 // ForEach calls the given function for each rune in the rope.
 // This is useful for side-effect operations like printing or logging.
 //
@@ -777,7 +1016,9 @@ func (r *Rope) Count(f func(rune) bool) int {
 // ========== Utility Functions ==========
 
 // Lines splits the rope into lines, preserving line endings.
-// Each line includes its trailing newline character (except possibly the last line).
+// Each line includes its trailing line break (except the last line), and
+// line breaks follow the rope's LineEndingMode, as in Line and LineCount.
+// A trailing line break is followed by an empty last line.
 // Returns a slice of strings, one per line.
 //
 // Example:
@@ -788,7 +1029,18 @@ func (r *Rope) Count(f func(rune) bool) int {
 //	}
 func (r *Rope) Lines() []string {
 	content := r.String()
-	return strings.SplitAfter(content, "\n")
+	var lines []string
+	lineStart, bytePos, charPos := 0, 0, 0
+	r.forEachLineBreak(func(_, end int) bool {
+		for ; charPos < end; charPos++ {
+			_, size := utf8.DecodeRuneInString(content[bytePos:])
+			bytePos += size
+		}
+		lines = append(lines, content[lineStart:bytePos])
+		lineStart = bytePos
+		return true
+	})
+	return append(lines, content[lineStart:])
 }
 
 // Contains reports whether the rope contains the given substring.
@@ -803,6 +1055,171 @@ func (r *Rope) Contains(substring string) bool {
 	return strings.Contains(r.String(), substring)
 }
 
+// StartsWith returns true if the rope begins with prefix.
+// Only the leaves covering the first len(prefix) bytes are read.
+//
+// Example:
+//
+//	if r.StartsWith("#!") {
+//	    fmt.Println("script")
+//	}
+func (r *Rope) StartsWith(prefix string) bool {
+	return len(prefix) <= r.Size() && matchLeaves(newLeafWalker(r, false), stringLeafWalker(prefix, false), false)
+}
+
+// EndsWith returns true if the rope ends with suffix.
+// Only the leaves covering the last len(suffix) bytes are read.
+func (r *Rope) EndsWith(suffix string) bool {
+	return len(suffix) <= r.Size() && matchLeaves(newLeafWalker(r, true), stringLeafWalker(suffix, true), true)
+}
+
+// HasPrefixRope returns true if the rope begins with the content of prefix.
+// The ropes are compared leaf by leaf without building either string.
+func (r *Rope) HasPrefixRope(prefix *Rope) bool {
+	return prefix.Size() <= r.Size() && matchLeaves(newLeafWalker(r, false), newLeafWalker(prefix, false), false)
+}
+
+// HasSuffixRope returns true if the rope ends with the content of suffix.
+// The ropes are compared leaf by leaf without building either string.
+func (r *Rope) HasSuffixRope(suffix *Rope) bool {
+	return suffix.Size() <= r.Size() && matchLeaves(newLeafWalker(r, true), newLeafWalker(suffix, true), true)
+}
+
+// stringLeafWalker returns a leaf walker over a single string.
+func stringLeafWalker(text string, reverse bool) *leafWalker {
+	return &leafWalker{stack: []RopeNode{&LeafNode{text: text}}, reverse: reverse}
+}
+
+// matchLeaves reports whether the bytes yielded by pattern match the
+// first (or, walking in reverse, the last) bytes yielded by w.
+func matchLeaves(w, pattern *leafWalker, reverse bool) bool {
+	var text, pat string
+	for {
+		if pat == "" {
+			var ok bool
+			if pat, ok = pattern.next(); !ok {
+				return true
+			}
+		}
+		if text == "" {
+			var ok bool
+			if text, ok = w.next(); !ok {
+				return false
+			}
+		}
+
+		n := min(len(text), len(pat))
+		if reverse {
+			if text[len(text)-n:] != pat[len(pat)-n:] {
+				return false
+			}
+			text, pat = text[:len(text)-n], pat[:len(pat)-n]
+		} else {
+			if text[:n] != pat[:n] {
+				return false
+			}
+			text, pat = text[n:], pat[n:]
+		}
+	}
+}
+
+// Head returns at most the first n characters of the rope. Only the leaves
+// holding them are read, so this is cheap for previews of huge documents.
+func (r *Rope) Head(n int) string {
+	return r.edgeText(n, false, false)
+}
+
+// Tail returns at most the last n characters of the rope.
+func (r *Rope) Tail(n int) string {
+	return r.edgeText(n, false, true)
+}
+
+// HeadBytes returns at most the first n bytes of the rope, shortened if
+// needed so that it does not end in the middle of a character.
+func (r *Rope) HeadBytes(n int) string {
+	return r.edgeText(n, true, false)
+}
+
+// TailBytes returns at most the last n bytes of the rope, shortened if
+// needed so that it does not start in the middle of a character.
+func (r *Rope) TailBytes(n int) string {
+	return r.edgeText(n, true, true)
+}
+
+// edgeText collects up to n characters (or bytes) from the start of the
+// rope, or from the end when fromEnd is set, reading leaves only as far as
+// needed.
+func (r *Rope) edgeText(n int, bytes, fromEnd bool) string {
+	if n <= 0 || r.Length() == 0 {
+		return ""
+	}
+	if (bytes && n >= r.Size()) || (!bytes && n >= r.Length()) {
+		return r.String()
+	}
+
+	w := newLeafWalker(r, fromEnd)
+	var chunks []string
+	for n > 0 {
+		text, ok := w.next()
+		if !ok {
+			break
+		}
+
+		size := len(text)
+		if !bytes {
+			size = utf8.RuneCountInString(text)
+		}
+		if size > n {
+			text = cutEdge(text, n, bytes, fromEnd)
+			n = 0
+		} else {
+			n -= size
+		}
+		chunks = append(chunks, text)
+	}
+
+	if fromEnd {
+		for i, j := 0, len(chunks)-1; i < j; i, j = i+1, j-1 {
+			chunks[i], chunks[j] = chunks[j], chunks[i]
+		}
+	}
+	return strings.Join(chunks, "")
+}
+
+// cutEdge returns the first (or last, if fromEnd) n characters or bytes of
+// text, which is longer than that. Byte cuts snap to character boundaries.
+func cutEdge(text string, n int, bytes, fromEnd bool) string {
+	var cut int
+	switch {
+	case bytes && fromEnd:
+		cut = len(text) - n
+		for cut < len(text) && !utf8.RuneStart(text[cut]) {
+			cut++
+		}
+	case bytes:
+		cut = n
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	case fromEnd:
+		cut = len(text)
+		for i := 0; i < n; i++ {
+			_, size := utf8.DecodeLastRuneInString(text[:cut])
+			cut -= size
+		}
+	default:
+		for i := 0; i < n; i++ {
+			_, size := utf8.DecodeRuneInString(text[cut:])
+			cut += size
+		}
+	}
+
+	if fromEnd {
+		return text[cut:]
+	}
+	return text[:cut]
+}
+
 // Index returns the first character position of substring, or -1 if not found.
 // The position is in characters (not bytes).
 //
@@ -864,3 +1281,65 @@ func (r *Rope) Compare(other *Rope) int {
 func (r *Rope) Equals(other *Rope) bool {
 	return r.String() == other.String()
 }
+
+// EqualFold reports whether two ropes are equal under Unicode simple case
+// folding, like strings.EqualFold, without building either string. Both
+// ropes' leaves are walked in lockstep and compared character by
+// character, so differing chunk boundaries and characters whose folded
+// forms have different byte lengths, such as the Kelvin sign and 'k', are
+// handled. Folds that change the number of characters, such as 'ß' and
+// "ss", are not applied.
+//
+// Example:
+//
+//	rope.New("HELLO").EqualFold(rope.New("hello")) // true
+func (r *Rope) EqualFold(other *Rope) bool {
+	// Simple folding maps one character to one character
+	if r.Length() != other.Length() {
+		return false
+	}
+
+	left, right := newLeafWalker(r, false), newLeafWalker(other, false)
+	var a, b string
+	for {
+		if a == "" {
+			a, _ = left.next()
+		}
+		if b == "" {
+			b, _ = right.next()
+		}
+		if a == "" || b == "" {
+			return a == b
+		}
+
+		// Skip identical bytes, then compare the next character folded
+		n := commonPrefixBytes(a, b)
+		for n > 0 && n < len(a) && !utf8.RuneStart(a[n]) {
+			n--
+		}
+		a, b = a[n:], b[n:]
+		if a == "" || b == "" {
+			continue
+		}
+
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if !equalFoldRune(ra, rb) {
+			return false
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+}
+
+// equalFoldRune reports whether a and b are equal under simple case folding.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
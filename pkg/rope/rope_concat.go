@@ -11,46 +11,28 @@ import (
 // This is more efficient than converting the rope to a string and appending.
 func (r *Rope) AppendRope(other *Rope) *Rope {
 	if r == nil || r.Length() == 0 {
-		return other.Clone()
+		return r.keepSettings(other.Clone())
 	}
 	if other == nil || other.Length() == 0 {
 		return r.Clone()
 	}
 
 	// Create a new internal node that joins both ropes
-	return &Rope{
-		root: &InternalNode{
-			left:   r.root,
-			right:  other.root,
-			length: r.Length(),
-			size:   r.Size(),
-		},
-		length: r.Length() + other.Length(),
-		size:   r.Size() + other.Size(),
-	}
+	return r.derive(newInternalNode(r.root, other.root), r.Length()+other.Length(), r.Size()+other.Size())
 }
 
 // PrependRope prepends another rope to the beginning of this rope.
 // Returns a new Rope, leaving both original ropes unchanged.
 func (r *Rope) PrependRope(other *Rope) *Rope {
 	if r == nil || r.Length() == 0 {
-		return other.Clone()
+		return r.keepSettings(other.Clone())
 	}
 	if other == nil || other.Length() == 0 {
 		return r.Clone()
 	}
 
 	// Create a new internal node with other as left child
-	return &Rope{
-		root: &InternalNode{
-			left:   other.root,
-			right:  r.root,
-			length: other.Length(),
-			size:   other.Size(),
-		},
-		length: other.Length() + r.Length(),
-		size:   other.Size() + r.Size(),
-	}
+	return r.derive(newInternalNode(other.root, r.root), other.Length()+r.Length(), other.Size()+r.Size())
 }
 
 // Concat concatenates multiple ropes together.
@@ -123,6 +105,24 @@ func (r *Rope) Join(ropes []*Rope, separator string) *Rope {
 	return result
 }
 
+// Join concatenates parts with sep between each pair, like strings.Join.
+// The result is a balanced tree that shares structure with sep and parts.
+// A nil or empty sep joins the parts directly.
+func Join(sep *Rope, parts ...*Rope) *Rope {
+	if len(parts) == 0 {
+		return Empty()
+	}
+
+	ropes := make([]*Rope, 0, 2*len(parts)-1)
+	for i, part := range parts {
+		if i > 0 {
+			ropes = append(ropes, sep)
+		}
+		ropes = append(ropes, part)
+	}
+	return Concat(ropes...)
+}
+
 // ========== String Append/Prepend ==========
 
 // AppendStr appends a string to the end of the rope.
@@ -136,22 +136,13 @@ func (r *Rope) AppendStr(text string) *Rope {
 		return r
 	}
 	if r.length == 0 {
-		return New(text)
+		return r.keepSettings(New(text))
 	}
 
 	// Create rope from text and append it directly
 	textRope := New(text)
 
-	return &Rope{
-		root: &InternalNode{
-			left:   r.root,
-			right:  textRope.root,
-			length: r.Length(),
-			size:   r.Size(),
-		},
-		length: r.length + utf8.RuneCountInString(text),
-		size:   r.size + len(text),
-	}
+	return r.derive(newInternalNode(r.root, textRope.root), r.length+utf8.RuneCountInString(text), r.size+len(text))
 }
 
 // PrependStr prepends a string to the beginning of the rope.
@@ -165,23 +156,14 @@ func (r *Rope) PrependStr(text string) *Rope {
 		return r
 	}
 	if r.length == 0 {
-		return New(text)
+		return r.keepSettings(New(text))
 	}
 
 	// Optimized: Create rope from text and prepend it directly
 	// This is faster than Insert(0, text) which needs to traverse the tree
 	textRope := New(text)
 
-	return &Rope{
-		root: &InternalNode{
-			left:   textRope.root,
-			right:  r.root,
-			length: textRope.Length(),
-			size:   textRope.Size(),
-		},
-		length: r.length + utf8.RuneCountInString(text),
-		size:   r.size + len(text),
-	}
+	return r.derive(newInternalNode(textRope.root, r.root), r.length+utf8.RuneCountInString(text), r.size+len(text))
 }
 
 // Append appends a string to the end of the rope.
@@ -0,0 +1,201 @@
+package rope
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf8"
+)
+
+// CommonPrefixLen returns the number of leading characters shared by r and
+// other. Both ropes' leaves are walked in lockstep and the walk stops at the
+// first difference, so chunk boundaries may differ between the two ropes.
+//
+// Example:
+//
+//	a := rope.New("Hello World")
+//	b := rope.New("Hello There")
+//	fmt.Println(a.CommonPrefixLen(b)) // 6
+func (r *Rope) CommonPrefixLen(other *Rope) int {
+	left, right := newLeafWalker(r, false), newLeafWalker(other, false)
+	var a, b string
+	count := 0
+
+	for {
+		if a == "" {
+			a, _ = left.next()
+		}
+		if b == "" {
+			b, _ = right.next()
+		}
+		if a == "" || b == "" {
+			return count
+		}
+
+		n := commonPrefixBytes(a, b)
+		// Only count whole characters
+		for n > 0 && n < len(a) && !utf8.RuneStart(a[n]) {
+			n--
+		}
+		count += utf8.RuneCountInString(a[:n])
+		if n < len(a) && n < len(b) {
+			return count
+		}
+		a, b = a[n:], b[n:]
+	}
+}
+
+// CommonSuffixLen returns the number of trailing characters shared by r and
+// other, walking both ropes' leaves backward in lockstep.
+//
+// Example:
+//
+//	a := rope.New("foo.go")
+//	b := rope.New("bar.go")
+//	fmt.Println(a.CommonSuffixLen(b)) // 3
+func (r *Rope) CommonSuffixLen(other *Rope) int {
+	left, right := newLeafWalker(r, true), newLeafWalker(other, true)
+	var a, b string
+	count := 0
+
+	for {
+		if a == "" {
+			a, _ = left.next()
+		}
+		if b == "" {
+			b, _ = right.next()
+		}
+		if a == "" || b == "" {
+			return count
+		}
+
+		n := commonSuffixBytes(a, b)
+		// Only count whole characters
+		for n > 0 && !utf8.RuneStart(a[len(a)-n]) {
+			n--
+		}
+		count += utf8.RuneCountInString(a[len(a)-n:])
+		if n < len(a) && n < len(b) {
+			return count
+		}
+		a, b = a[:len(a)-n], b[:len(b)-n]
+	}
+}
+
+// commonPrefixBytes returns the length of the common byte prefix of a and b.
+func commonPrefixBytes(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// commonSuffixBytes returns the length of the common byte suffix of a and b.
+func commonSuffixBytes(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 1; i <= n; i++ {
+		if a[len(a)-i] != b[len(b)-i] {
+			return i - 1
+		}
+	}
+	return n
+}
+
+// Diff returns a ChangeSet that turns before into after. Unchanged leading
+// and trailing text is trimmed with CommonPrefixLen and CommonSuffixLen, and
+// the remaining middle is replaced as a whole.
+//
+// Example:
+//
+//	cs := rope.Diff(rope.New("Hello World"), rope.New("Hello There"))
+//	// Retain(6), Delete(5), Insert("There")
+func Diff(before, after *Rope) *ChangeSet {
+	if before == nil {
+		before = Empty()
+	}
+	if after == nil {
+		after = Empty()
+	}
+
+	prefix := before.CommonPrefixLen(after)
+	suffix := before.CommonSuffixLen(after)
+	// The prefix and suffix must not overlap, e.g. "aa" -> "aaa"
+	suffix = min(suffix, min(before.Length(), after.Length())-prefix)
+
+	var edits []EditOperation
+	deleteEnd := before.Length() - suffix
+	insertEnd := after.Length() - suffix
+	if deleteEnd > prefix || insertEnd > prefix {
+		text, _ := after.Slice(prefix, insertEnd)
+		edits = append(edits, EditOperation{From: prefix, To: deleteEnd, Text: text})
+	}
+	return changeSetFromEdits(before.Length(), edits)
+}
+
+// UpdateFrom returns a rope with the content of other, such as a file that
+// was rewritten by another program, together with the ChangeSet that turns
+// r into it for mapping cursors and markers. Only the middle that differs
+// between the two, as found by Diff, is edited, so the parts of r's tree
+// holding the unchanged leading and trailing text are shared with the
+// result.
+//
+// Example:
+//
+//	reloaded, _ := rope.FromReader(f)
+//	doc, cs, _ := doc.UpdateFrom(reloaded)
+//	sel = sel.MapPositions(cs)
+func (r *Rope) UpdateFrom(other *Rope) (*Rope, *ChangeSet, error) {
+	if r == nil {
+		r = Empty()
+	}
+	cs := Diff(r, other)
+	result, err := cs.Apply(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, cs, nil
+}
+
+// JournalSince returns a compact journal of the changes that turn saved, the
+// last version written to disk, into r, for autosave. The journal is the
+// CacheKey of saved as 8 big-endian bytes, followed by the Diff between the
+// two in the ChangeSet binary format, so it holds only the changed region's
+// text rather than the whole document. ApplyJournal replays it onto saved.
+//
+// Example:
+//
+//	journal, _ := doc.JournalSince(saved)
+//	os.WriteFile(path+".journal", journal, 0o600)
+func (r *Rope) JournalSince(saved *Rope) ([]byte, error) {
+	changes, err := Diff(saved, r).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	journal := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(changes)), saved.CacheKey())
+	return append(journal, changes...), nil
+}
+
+// ApplyJournal replays a journal written by JournalSince onto saved,
+// recovering the document it was written from. It returns an error if the
+// journal is corrupt or was written against a version with different
+// content, as found by comparing CacheKeys.
+func ApplyJournal(saved *Rope, journal []byte) (*Rope, error) {
+	if len(journal) < 8 {
+		return nil, errInvalidChangeSetBinary("missing journal header")
+	}
+	if key, savedKey := binary.BigEndian.Uint64(journal), saved.CacheKey(); key != savedKey {
+		return nil, &ErrInvalidInput{
+			Parameter: "saved",
+			Value:     savedKey,
+			Reason:    fmt.Sprintf("journal was written against content with cache key %#x", key),
+		}
+	}
+
+	cs := &ChangeSet{}
+	if err := cs.UnmarshalBinary(journal[8:]); err != nil {
+		return nil, err
+	}
+	return cs.ApplyChecked(saved)
+}
@@ -0,0 +1,201 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkedRope builds a rope by concatenating pieces of size characters,
+// so ropes with the same content can have different leaf boundaries.
+func chunkedRope(text string, size int) *Rope {
+	runes := []rune(text)
+	var pieces []*Rope
+	for i := 0; i < len(runes); i += size {
+		pieces = append(pieces, New(string(runes[i:min(i+size, len(runes))])))
+	}
+	return Concat(pieces...)
+}
+
+// TestCommonPrefixLen_DifferentChunking tests a long shared prefix across chunk boundaries
+func TestCommonPrefixLen_DifferentChunking(t *testing.T) {
+	shared := strings.Repeat("héllo wörld 🌍 ", 200)
+	a := chunkedRope(shared+"tail A", 7)
+	b := chunkedRope(shared+"tail B", 13)
+	require.NotEqual(t, a.LeafCount(), b.LeafCount())
+
+	expected := len([]rune(shared)) + len("tail ")
+	assert.Equal(t, expected, a.CommonPrefixLen(b))
+	assert.Equal(t, expected, b.CommonPrefixLen(a))
+}
+
+// TestCommonSuffixLen_DifferentChunking tests a long shared suffix across chunk boundaries
+func TestCommonSuffixLen_DifferentChunking(t *testing.T) {
+	shared := strings.Repeat("ünïcödé ✓ ", 150)
+	a := chunkedRope("first "+shared, 5)
+	b := chunkedRope("second "+shared, 11)
+
+	assert.Equal(t, len([]rune(shared))+1, a.CommonSuffixLen(b))
+	assert.Equal(t, len([]rune(shared))+1, b.CommonSuffixLen(a))
+}
+
+// TestCommonPrefixLen_Equal tests that equal ropes share their full length
+func TestCommonPrefixLen_Equal(t *testing.T) {
+	text := strings.Repeat("abc→", 100)
+	a := chunkedRope(text, 3)
+	b := New(text)
+
+	assert.Equal(t, a.Length(), a.CommonPrefixLen(b))
+	assert.Equal(t, a.Length(), a.CommonSuffixLen(b))
+
+	// A rope that is a prefix of the other
+	assert.Equal(t, 4, New("abc→").CommonPrefixLen(b))
+	assert.Equal(t, 0, Empty().CommonPrefixLen(b))
+	assert.Equal(t, 0, Empty().CommonSuffixLen(b))
+}
+
+// TestCommonPrefixLen_MultiByteMismatch tests differences inside multi-byte characters
+func TestCommonPrefixLen_MultiByteMismatch(t *testing.T) {
+	// "é" (C3 A9) and "è" (C3 A8) share their first byte
+	assert.Equal(t, 1, New("aé").CommonPrefixLen(New("aè")))
+	// "é" (C3 A9) and "©" (C2 A9) share their last byte
+	assert.Equal(t, 1, New("éa").CommonSuffixLen(New("©a")))
+}
+
+// TestDiff tests building a minimal changeset from two ropes
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		before, after string
+	}{
+		{"Hello World", "Hello There"},
+		{"aa", "aaa"},
+		{"aaa", "aa"},
+		{"same", "same"},
+		{"", "new"},
+		{"old", ""},
+		{"prefix-é-suffix", "prefix-è-suffix"},
+	}
+
+	for _, tt := range tests {
+		before := chunkedRope(tt.before, 2)
+		cs := Diff(before, New(tt.after))
+
+		result, err := cs.Apply(before)
+		require.NoError(t, err)
+		assert.Equal(t, tt.after, result.String(), "Diff(%q, %q)", tt.before, tt.after)
+	}
+
+	cs := Diff(New("Hello World"), New("Hello There"))
+	assert.True(t, cs.Equal(NewChangeSet(11).Retain(6).Delete(5).Insert("There")))
+}
+
+// leafSet returns the set of leaf nodes of r's tree.
+func leafSet(r *Rope) map[*LeafNode]bool {
+	leaves := make(map[*LeafNode]bool)
+	var walk func(RopeNode)
+	walk = func(n RopeNode) {
+		switch node := n.(type) {
+		case *InternalNode:
+			walk(node.left)
+			walk(node.right)
+		case *LeafNode:
+			leaves[node] = true
+		}
+	}
+	walk(r.root)
+	return leaves
+}
+
+// TestUpdateFrom_MiddleChange tests that text outside the changed middle keeps its leaves
+func TestUpdateFrom_MiddleChange(t *testing.T) {
+	head := strings.Repeat("unchanged head line\n", 200)
+	tail := strings.Repeat("unchanged tail line\n", 200)
+	r := chunkedRope(head+"old middle\n"+tail, 64)
+	external := New(head + "new middle, a bit longer\n" + tail)
+
+	updated, cs, err := r.UpdateFrom(external)
+	require.NoError(t, err)
+	assert.Equal(t, external.String(), updated.String())
+
+	// Only the middle is replaced
+	ops := cs.Operations()
+	require.Len(t, ops, 4)
+	assert.Equal(t, Operation{OpType: OpRetain, Length: len(head)}, ops[0])
+	assert.Equal(t, Operation{OpType: OpRetain, Length: len(tail) + 1}, ops[3])
+
+	// Positions after the change are shifted, those before are kept
+	assert.Equal(t, 10, cs.MapPosition(10, AssocBefore))
+	end := r.Length() - 5
+	assert.Equal(t, updated.Length()-5, cs.MapPosition(end, AssocBefore))
+
+	// Most leaves of the original tree are shared with the result
+	before, after := leafSet(r), leafSet(updated)
+	shared := 0
+	for leaf := range after {
+		if before[leaf] {
+			shared++
+		}
+	}
+	assert.Greater(t, shared, len(before)*9/10)
+}
+
+// TestUpdateFrom_NoChange tests updating from identical content
+func TestUpdateFrom_NoChange(t *testing.T) {
+	r := New("same text")
+
+	updated, cs, err := r.UpdateFrom(New("same text"))
+	require.NoError(t, err)
+	assert.Same(t, r, updated)
+	assert.Equal(t, []Operation{{OpType: OpRetain, Length: 9}}, cs.Operations())
+
+	updated, _, err = r.UpdateFrom(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", updated.String())
+}
+
+// TestJournalSince tests replaying an autosave journal onto the saved version
+func TestJournalSince(t *testing.T) {
+	text := strings.Repeat("line of saved text\n", 1000)
+	saved := New(text)
+	current, err := saved.Replace(9500, 9505, "edited — ✓")
+	require.NoError(t, err)
+
+	journal, err := current.JournalSince(saved)
+	require.NoError(t, err)
+	assert.Less(t, len(journal), 64) // Only the changed region is stored
+
+	recovered, err := ApplyJournal(saved, journal)
+	require.NoError(t, err)
+	assert.Equal(t, current.String(), recovered.String())
+
+	// No changes since the save
+	journal, err = saved.JournalSince(saved)
+	require.NoError(t, err)
+	recovered, err = ApplyJournal(saved, journal)
+	require.NoError(t, err)
+	assert.Equal(t, text, recovered.String())
+}
+
+// TestApplyJournal_Invalid tests rejecting corrupt or mismatched journals
+func TestApplyJournal_Invalid(t *testing.T) {
+	saved := New("hello world")
+	current, err := saved.Insert(5, ",")
+	require.NoError(t, err)
+	journal, err := current.JournalSince(saved)
+	require.NoError(t, err)
+
+	_, err = ApplyJournal(New("hello"), journal)
+	assert.Error(t, err)
+	_, err = ApplyJournal(saved, journal[:len(journal)-1])
+	assert.Error(t, err)
+	_, err = ApplyJournal(saved, []byte("junk"))
+	assert.Error(t, err)
+
+	// Content of the same length that differs from the saved version
+	var inputErr *ErrInvalidInput
+	_, err = ApplyJournal(New("hello there"), journal)
+	require.ErrorAs(t, err, &inputErr)
+	assert.Equal(t, "saved", inputErr.Parameter)
+}
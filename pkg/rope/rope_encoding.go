@@ -0,0 +1,146 @@
+package rope
+
+import (
+	"encoding"
+	"encoding/binary"
+	"unicode/utf8"
+)
+
+// Binary format
+//
+// A serialized rope is a 4-byte magic "TXRP", a version byte, the number of
+// chunks as a uvarint, and then every chunk as a uvarint byte length
+// followed by its UTF-8 bytes. Chunk boundaries carry no meaning: decoding
+// re-chunks the content and builds a balanced tree, so only the content
+// round-trips, not the tree shape.
+
+const (
+	binaryMagic   = "TXRP"
+	binaryVersion = 1
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Rope)(nil)
+	_ encoding.BinaryUnmarshaler = (*Rope)(nil)
+)
+
+// MarshalBinary encodes the rope's content in the versioned binary format.
+// It implements encoding.BinaryMarshaler.
+func (r *Rope) MarshalBinary() ([]byte, error) {
+	var chunks []string
+	if r != nil {
+		forEachLeaf(r.root, func(text string) bool {
+			if text != "" {
+				chunks = append(chunks, text)
+			}
+			return true
+		})
+	}
+
+	buf := make([]byte, 0, len(binaryMagic)+1+binary.MaxVarintLen64*(len(chunks)+1)+r.Size())
+	buf = append(buf, binaryMagic...)
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(chunks)))
+	for _, chunk := range chunks {
+		buf = binary.AppendUvarint(buf, uint64(len(chunk)))
+		buf = append(buf, chunk...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the rope with content decoded from data, which
+// must have been produced by MarshalBinary. It implements
+// encoding.BinaryUnmarshaler.
+func (r *Rope) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return errInvalidBinary("missing header")
+	}
+	if version := data[len(binaryMagic)]; version != binaryVersion {
+		return &ErrInvalidInput{
+			Parameter: "version",
+			Value:     version,
+			Reason:    "unsupported binary format version",
+		}
+	}
+	data = data[len(binaryMagic)+1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errInvalidBinary("bad chunk count")
+	}
+	data = data[n:]
+
+	var leaves []*LeafNode
+	length, size := 0, 0
+	for i := uint64(0); i < count; i++ {
+		chunkLen, n := binary.Uvarint(data)
+		if n <= 0 || chunkLen > uint64(len(data)-n) {
+			return errInvalidBinary("truncated chunk")
+		}
+		chunk := string(data[n : n+int(chunkLen)])
+		data = data[n+int(chunkLen):]
+
+		if !utf8.ValidString(chunk) {
+			return errInvalidBinary("chunk is not valid UTF-8")
+		}
+		length += utf8.RuneCountInString(chunk)
+		size += len(chunk)
+		leaves = appendLeaves(leaves, chunk)
+	}
+	if len(data) != 0 {
+		return errInvalidBinary("trailing data")
+	}
+
+	*r = Rope{length: length, size: size}
+	r.root = buildBalancedTree(leaves, 0, len(leaves))
+	if r.root == nil {
+		r.root = newLeafNode("")
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder using the binary format.
+func (r *Rope) GobEncode() ([]byte, error) {
+	return r.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary format.
+func (r *Rope) GobDecode(data []byte) error {
+	return r.UnmarshalBinary(data)
+}
+
+// appendLeaves splits text into leaves of at most DefaultMaxLeafSize bytes,
+// cutting only at character boundaries.
+func appendLeaves(leaves []*LeafNode, text string) []*LeafNode {
+	for len(text) > DefaultMaxLeafSize {
+		cut := DefaultMaxLeafSize
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		leaves = append(leaves, newLeafNode(text[:cut]))
+		text = text[cut:]
+	}
+	return append(leaves, newLeafNode(text))
+}
+
+// newChunkedRope builds a balanced rope over text, split into leaves of at
+// most DefaultMaxLeafSize bytes. The leaves share text's memory.
+func newChunkedRope(text string) *Rope {
+	if text == "" {
+		return Empty()
+	}
+	leaves := appendLeaves(nil, text)
+	return &Rope{
+		root:   buildBalancedTree(leaves, 0, len(leaves)),
+		length: utf8.RuneCountInString(text),
+		size:   len(text),
+	}
+}
+
+func errInvalidBinary(reason string) error {
+	return &ErrInvalidInput{
+		Parameter: "data",
+		Value:     "rope binary",
+		Reason:    reason,
+	}
+}
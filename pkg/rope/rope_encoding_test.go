@@ -0,0 +1,99 @@
+package rope
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshalBinary_RoundTripLarge tests round-tripping large Unicode content
+func TestMarshalBinary_RoundTripLarge(t *testing.T) {
+	text := strings.Repeat("Grüße, 世界! 🎉 line of text\n", 5000)
+	for _, r := range []*Rope{New(text), chunkedRope(text, 333)} {
+		data, err := r.MarshalBinary()
+		require.NoError(t, err)
+
+		decoded := &Rope{}
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, text, decoded.String())
+		assert.Equal(t, r.Length(), decoded.Length())
+		assert.Equal(t, r.Size(), decoded.Size())
+		assert.NoError(t, decoded.Validate())
+		assert.True(t, decoded.IsBalanced())
+	}
+}
+
+// TestMarshalBinary_Empty tests round-tripping an empty rope
+func TestMarshalBinary_Empty(t *testing.T) {
+	data, err := Empty().MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("TXRP\x01\x00"), data)
+
+	decoded := New("previous content")
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, "", decoded.String())
+	assert.Equal(t, 0, decoded.Length())
+
+	// Edits work on a decoded rope
+	edited, err := decoded.Insert(0, "x")
+	require.NoError(t, err)
+	assert.Equal(t, "x", edited.String())
+}
+
+// TestUnmarshalBinary_Invalid tests rejecting malformed input
+func TestUnmarshalBinary_Invalid(t *testing.T) {
+	valid, err := New("hello").MarshalBinary()
+	require.NoError(t, err)
+
+	cases := map[string][]byte{
+		"empty":         nil,
+		"bad magic":     []byte("ROPE\x01\x00"),
+		"bad version":   []byte("TXRP\x02\x00"),
+		"truncated":     valid[:len(valid)-1],
+		"trailing data": append(append([]byte{}, valid...), 'x'),
+		"invalid utf8":  []byte("TXRP\x01\x01\x02\xff\xfe"),
+	}
+	for name, data := range cases {
+		var r Rope
+		var inputErr *ErrInvalidInput
+		assert.ErrorAs(t, r.UnmarshalBinary(data), &inputErr, name)
+	}
+}
+
+// TestRope_Gob tests encoding ropes with encoding/gob
+func TestRope_Gob(t *testing.T) {
+	type document struct {
+		Name string
+		Body *Rope
+	}
+	in := document{Name: "doc", Body: chunkedRope("héllo\nwörld 🎉", 4)}
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+	var out document
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	assert.Equal(t, "doc", out.Name)
+	assert.Equal(t, in.Body.String(), out.Body.String())
+}
+
+// TestChangeSet_MarshalBinary tests round-tripping a changeset
+func TestChangeSet_MarshalBinary(t *testing.T) {
+	cs := NewChangeSet(12).Retain(3).Delete(4).Insert("Grüße 🎉").Retain(5)
+	data, err := cs.MarshalBinary()
+	require.NoError(t, err)
+
+	decoded := &ChangeSet{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.True(t, cs.Equal(decoded))
+	assert.Equal(t, cs.LenAfter(), decoded.LenAfter())
+
+	// A retain past lenBefore fails validation
+	bad, err := NewChangeSet(2).Retain(5).MarshalBinary()
+	require.NoError(t, err)
+	assert.Error(t, decoded.UnmarshalBinary(bad))
+}
@@ -3,6 +3,7 @@ package rope
 import (
 	"bufio"
 	"io"
+	"unicode/utf8"
 )
 
 // FromReader reads content from an io.Reader and creates a new Rope.
@@ -40,6 +41,89 @@ func FromReader(reader io.Reader) (*Rope, error) {
 	}
 }
 
+// InsertReader inserts the content read from src at the given character
+// position, e.g. for pasting the output of a subprocess. The content is built
+// into a balanced subtree that is spliced in structurally, rather than
+// inserted as one large string.
+//
+// If reading fails, the error is returned and the rope is unchanged.
+//
+// Example:
+//
+//	r := rope.New("ab")
+//	r2, err := r.InsertReader(1, strings.NewReader("XYZ"))
+//	fmt.Println(r2.String()) // "aXYZb"
+func (r *Rope) InsertReader(pos int, src io.Reader) (*Rope, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, errInsertOutOfBounds(pos, r.Length())
+	}
+
+	read, err := readChunked(src)
+	if err != nil {
+		return nil, err
+	}
+	if read.Length() == 0 {
+		return r, nil
+	}
+
+	left, right, err := r.Split(pos)
+	if err != nil {
+		return nil, err
+	}
+	return left.Concat(read).Concat(right), nil
+}
+
+// readChunked reads src into a balanced rope of leaves of at most
+// DefaultMaxLeafSize bytes. A character split across reads is carried over
+// to the next leaf, and the content is never joined into one string.
+func readChunked(src io.Reader) (*Rope, error) {
+	var leaves []*LeafNode
+	length, size := 0, 0
+	buf := make([]byte, DefaultMaxLeafSize)
+	filled := 0
+
+	for {
+		n, err := io.ReadFull(src, buf[filled:])
+		filled += n
+		done := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !done {
+			return nil, err
+		}
+
+		cut := filled
+		if !done {
+			// Keep an incomplete trailing character for the next leaf
+			start := cut - 1
+			for start > 0 && start > cut-utf8.UTFMax && !utf8.RuneStart(buf[start]) {
+				start--
+			}
+			if !utf8.FullRune(buf[start:cut]) {
+				cut = start
+			}
+		}
+		if cut > 0 {
+			leaf := newLeafNode(string(buf[:cut]))
+			leaves = append(leaves, leaf)
+			length += leaf.Length()
+			size += cut
+		}
+		filled = copy(buf, buf[cut:filled])
+
+		if done {
+			break
+		}
+	}
+
+	if len(leaves) == 0 {
+		return Empty(), nil
+	}
+	return &Rope{
+		root:   buildBalancedTree(leaves, 0, len(leaves)),
+		length: length,
+		size:   size,
+	}, nil
+}
+
 // WriteTo writes the rope's content to an io.Writer.
 //
 // Returns the number of bytes written and any error encountered.
@@ -169,3 +253,82 @@ func (rr *ropeReader) Read(p []byte) (int, error) {
 	}
 	return count, nil
 }
+
+// SliceReader returns an io.Reader yielding the UTF-8 bytes of the
+// characters [start, end), one leaf at a time, so a large region can be
+// piped into a writer or parser without building it as a string first.
+// If the range is invalid, the first Read returns an *ErrInvalidRange.
+//
+// Example:
+//
+//	r := rope.New("Hello World")
+//	io.Copy(os.Stdout, r.SliceReader(6, 11)) // World
+func (r *Rope) SliceReader(start, end int) io.Reader {
+	if start < 0 || end > r.Length() || start > end {
+		return &sliceReader{err: &ErrInvalidRange{
+			Operation: "SliceReader",
+			Start:     start,
+			End:       end,
+			ValidMax:  r.Length(),
+		}}
+	}
+	if start == end {
+		return &sliceReader{err: io.EOF}
+	}
+
+	walker, leafStart := leafWalkerAt(r, start)
+	return &sliceReader{walker: walker, skip: start - leafStart, remaining: end - start}
+}
+
+// sliceReader implements io.Reader for a character range of a rope.
+type sliceReader struct {
+	walker    *leafWalker
+	skip      int    // Characters to skip at the start of the next leaf
+	remaining int    // Characters not yet moved into pending
+	pending   string // Bytes of the current leaf not yet read
+	err       error  // Returned once pending is drained
+}
+
+func (sr *sliceReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if sr.pending == "" && !sr.nextLeaf() {
+			break
+		}
+		copied := copy(p[n:], sr.pending)
+		sr.pending = sr.pending[copied:]
+		n += copied
+	}
+	if n == 0 && len(p) > 0 {
+		return 0, sr.err
+	}
+	return n, nil
+}
+
+// nextLeaf moves the part of the next leaf inside the range into pending.
+// Returns false, setting err, once the range is exhausted.
+func (sr *sliceReader) nextLeaf() bool {
+	if sr.err != nil {
+		return false
+	}
+	if sr.remaining == 0 {
+		sr.err = io.EOF
+		return false
+	}
+	text, ok := sr.walker.next()
+	if !ok {
+		sr.err = io.EOF
+		return false
+	}
+
+	if sr.skip > 0 {
+		text = text[findBytePosInString(text, sr.skip):]
+		sr.skip = 0
+	}
+	if cut := findBytePosInString(text, sr.remaining); cut < len(text) {
+		text = text[:cut]
+	}
+	sr.remaining -= RuneCountInStringFast(text)
+	sr.pending = text
+	return true
+}
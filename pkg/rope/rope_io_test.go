@@ -0,0 +1,144 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInsertReader_LargeStream tests inserting a 1MB stream in the middle of a rope
+func TestInsertReader_LargeStream(t *testing.T) {
+	r := New("head|tail")
+	paste := strings.Repeat("stream data ünï 世界\n", 1<<20/len("stream data ünï 世界\n"))
+
+	result, err := r.InsertReader(5, strings.NewReader(paste))
+	require.NoError(t, err)
+
+	expected := "head|" + paste + "tail"
+	assert.Equal(t, len([]rune(expected)), result.Length())
+	assert.Equal(t, len(expected), result.Size())
+	assert.Equal(t, expected, result.String())
+	assert.NoError(t, result.Validate())
+	assert.True(t, result.ChunkCount() > 1)
+
+	// The original is unchanged
+	assert.Equal(t, "head|tail", r.String())
+}
+
+// TestInsertReader_SplitCharacters tests characters split across reads and leaves
+func TestInsertReader_SplitCharacters(t *testing.T) {
+	r := New("<>")
+	paste := strings.Repeat("é世🎉", DefaultMaxLeafSize)
+
+	result, err := r.InsertReader(1, iotest.OneByteReader(strings.NewReader(paste)))
+	require.NoError(t, err)
+	assert.Equal(t, "<"+paste+">", result.String())
+	assert.NoError(t, result.Validate())
+
+	forEachLeaf(result.root, func(text string) bool {
+		assert.True(t, utf8.ValidString(text))
+		assert.LessOrEqual(t, len(text), DefaultMaxLeafSize)
+		return true
+	})
+}
+
+// errAfterReader returns some data and then fails.
+type errAfterReader struct {
+	data string
+	err  error
+}
+
+func (e *errAfterReader) Read(p []byte) (int, error) {
+	if e.data == "" {
+		return 0, e.err
+	}
+	n := copy(p, e.data)
+	e.data = e.data[n:]
+	return n, nil
+}
+
+// TestInsertReader_ReadError tests that a failing reader leaves the rope unchanged
+func TestInsertReader_ReadError(t *testing.T) {
+	r := New("original")
+	readErr := errors.New("pipe closed")
+
+	result, err := r.InsertReader(3, &errAfterReader{data: strings.Repeat("x", 10000), err: readErr})
+	assert.ErrorIs(t, err, readErr)
+	assert.Nil(t, result)
+	assert.Equal(t, "original", r.String())
+}
+
+// TestInsertReader_EdgeCases tests empty streams, the document ends and bad positions
+func TestInsertReader_EdgeCases(t *testing.T) {
+	r := New("ab")
+
+	result, err := r.InsertReader(1, strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, "ab", result.String())
+
+	result, err = r.InsertReader(0, strings.NewReader(">"))
+	require.NoError(t, err)
+	assert.Equal(t, ">ab", result.String())
+
+	result, err = r.InsertReader(2, strings.NewReader("<"))
+	require.NoError(t, err)
+	assert.Equal(t, "ab<", result.String())
+
+	_, err = r.InsertReader(3, io.LimitReader(strings.NewReader("x"), 1))
+	var boundsErr *ErrOutOfBounds
+	assert.ErrorAs(t, err, &boundsErr)
+}
+
+// TestSliceReader_MatchesSlice tests streaming ranges that start and end inside leaves
+func TestSliceReader_MatchesSlice(t *testing.T) {
+	text := strings.Repeat("héllo wörld 日本 ", 50)
+	r := chunkedRope(text, 7)
+	n := r.Length()
+
+	for _, rng := range [][2]int{{0, n}, {3, 4}, {5, 5}, {17, 333}, {n / 2, n - 1}, {n - 2, n}} {
+		want, err := r.Slice(rng[0], rng[1])
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		_, err = io.Copy(&buf, r.SliceReader(rng[0], rng[1]))
+		require.NoError(t, err)
+		assert.Equal(t, want, buf.String(), "range %v", rng)
+	}
+}
+
+// TestSliceReader_SmallReads tests reading a range a few bytes at a time
+func TestSliceReader_SmallReads(t *testing.T) {
+	r := chunkedRope("the quick brown 🦊 jumps", 4)
+	want, _ := r.Slice(4, 18)
+
+	reader := r.SliceReader(4, 18)
+	var got []byte
+	p := make([]byte, 3)
+	for {
+		n, err := reader.Read(p)
+		got = append(got, p[:n]...)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	assert.Equal(t, want, string(got))
+}
+
+// TestSliceReader_InvalidRange tests that a bad range fails on the first read
+func TestSliceReader_InvalidRange(t *testing.T) {
+	_, err := io.ReadAll(New("abc").SliceReader(2, 4))
+	var rangeErr *ErrInvalidRange
+	assert.ErrorAs(t, err, &rangeErr)
+
+	data, err := io.ReadAll(Empty().SliceReader(0, 0))
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
@@ -0,0 +1,170 @@
+package rope
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ========== Three-Way Merge ==========
+
+// Conflict is a region where ours and theirs changed the same lines of
+// base differently. The texts are whole lines, including line endings.
+type Conflict struct {
+	Base   string
+	Ours   string
+	Theirs string
+
+	// Range is the character range of the conflict markers and both sides
+	// in the merged document, for jumping to or resolving the conflict.
+	Range Range
+}
+
+// Conflict markers written by Merge3, as used by git.
+const (
+	conflictOursMarker   = "<<<<<<< ours\n"
+	conflictSepMarker    = "=======\n"
+	conflictTheirsMarker = ">>>>>>> theirs\n"
+)
+
+// Merge3 merges the changes that ours and theirs each made to base, line by
+// line, as version control systems do. Changes to different lines merge
+// cleanly; where both sides changed the same lines differently, the merged
+// document holds both versions between git-style conflict markers and the
+// conflict is reported, in document order.
+//
+// Lines are matched with a longest common subsequence after trimming the
+// lines shared at the start and end, so the cost grows with the product of
+// the sizes of the changed regions rather than of the whole documents.
+//
+// Example:
+//
+//	merged, conflicts, _ := rope.Merge3(base, ours, theirs)
+//	for _, c := range conflicts {
+//	    fmt.Printf("conflict at %d: %q vs %q\n", c.Range.From(), c.Ours, c.Theirs)
+//	}
+func Merge3(base, ours, theirs *Rope) (*Rope, []Conflict, error) {
+	baseLines, oursLines, theirsLines := mergeLines(base), mergeLines(ours), mergeLines(theirs)
+	toOurs := matchLines(baseLines, oursLines)
+	toTheirs := matchLines(baseLines, theirsLines)
+
+	var b strings.Builder
+	var conflicts []Conflict
+	pos := 0
+	write := func(text string) {
+		b.WriteString(text)
+		pos += utf8.RuneCountInString(text)
+	}
+
+	bi, oi, ti := 0, 0, 0
+	for {
+		// Find the next base line kept by both sides
+		i := bi
+		for i < len(baseLines) && (toOurs[i] < 0 || toTheirs[i] < 0) {
+			i++
+		}
+		if i < len(baseLines) && i == bi && toOurs[i] == oi && toTheirs[i] == ti {
+			write(baseLines[i])
+			bi, oi, ti = bi+1, oi+1, ti+1
+			continue
+		}
+
+		oEnd, tEnd := len(oursLines), len(theirsLines)
+		if i < len(baseLines) {
+			oEnd, tEnd = toOurs[i], toTheirs[i]
+		}
+		o := strings.Join(oursLines[oi:oEnd], "")
+		t := strings.Join(theirsLines[ti:tEnd], "")
+		orig := strings.Join(baseLines[bi:i], "")
+
+		switch {
+		case o == t || t == orig:
+			write(o)
+		case o == orig:
+			write(t)
+		default:
+			start := pos
+			write(conflictOursMarker)
+			write(withTrailingNewline(o))
+			write(conflictSepMarker)
+			write(withTrailingNewline(t))
+			write(conflictTheirsMarker)
+			conflicts = append(conflicts, Conflict{Base: orig, Ours: o, Theirs: t, Range: NewRange(start, pos)})
+		}
+
+		if i == len(baseLines) {
+			break
+		}
+		bi, oi, ti = i, oEnd, tEnd
+	}
+
+	return New(b.String()), conflicts, nil
+}
+
+// mergeLines returns the lines of r, keeping their line endings.
+func mergeLines(r *Rope) []string {
+	if r == nil || r.Length() == 0 {
+		return nil
+	}
+	lines, _ := docLines(r.String())
+	return lines
+}
+
+// matchLines pairs up the lines of a and b along a longest common
+// subsequence, returning for each line of a the index of its partner in b,
+// or -1. The pairs are in increasing order in both slices.
+func matchLines(a, b []string) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+
+	// Lines shared at the start and end match directly
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		match[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		match[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	// LCS table over the middle: lcs[i][j] is the length for a[i:], b[j:]
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(am) && j < len(bm); {
+		switch {
+		case am[i] == bm[j]:
+			match[prefix+i] = prefix + j
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+// withTrailingNewline adds a '\n' to non-empty text that lacks one, so a
+// conflict marker after it starts on its own line.
+func withTrailingNewline(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
+	}
+	return text
+}
@@ -0,0 +1,66 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMerge3_Clean tests merging edits to different lines
+func TestMerge3_Clean(t *testing.T) {
+	base := New("one\ntwo\nthree\nfour\nfive\n")
+	ours := New("ONE\ntwo\nthree\nfour\nfive\n")
+	theirs := New("one\ntwo\nthree\nfour\nfive\nsix\n")
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "ONE\ntwo\nthree\nfour\nfive\nsix\n", merged.String())
+
+	// Insertions and deletions on both sides
+	ours = New("zero\none\ntwo\nfour\nfive\n")
+	theirs = New("one\ntwo\nthree\nfour\n4.5\nfive")
+	merged, conflicts, err = Merge3(base, ours, theirs)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "zero\none\ntwo\nfour\n4.5\nfive", merged.String())
+
+	// Both sides making the same change is not a conflict
+	merged, conflicts, err = Merge3(base, ours, ours)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, ours.String(), merged.String())
+}
+
+// TestMerge3_Conflict tests both sides changing the same line
+func TestMerge3_Conflict(t *testing.T) {
+	base := New("a\nb\nc\n")
+	ours := New("a\nB (ours)\nc\n")
+	theirs := New("a\nB (theirs)\nc\nd\n")
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	require.NoError(t, err)
+	want := "a\n<<<<<<< ours\nB (ours)\n=======\nB (theirs)\n>>>>>>> theirs\nc\nd\n"
+	assert.Equal(t, want, merged.String())
+
+	require.Len(t, conflicts, 1)
+	c := conflicts[0]
+	assert.Equal(t, "b\n", c.Base)
+	assert.Equal(t, "B (ours)\n", c.Ours)
+	assert.Equal(t, "B (theirs)\n", c.Theirs)
+	assert.Equal(t, "<<<<<<< ours\nB (ours)\n=======\nB (theirs)\n>>>>>>> theirs\n", sliceRange(merged, c.Range))
+}
+
+// TestMerge3_Empty tests merging with empty and nil documents
+func TestMerge3_Empty(t *testing.T) {
+	merged, conflicts, err := Merge3(nil, New("x"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "x", merged.String())
+
+	merged, conflicts, err = Merge3(nil, New("x"), New("y"))
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "<<<<<<< ours\nx\n=======\ny\n>>>>>>> theirs\n", merged.String())
+}
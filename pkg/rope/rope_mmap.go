@@ -0,0 +1,156 @@
+package rope
+
+import (
+	"unicode/utf8"
+)
+
+// ========== Memory-Mapped Ropes ==========
+//
+// NewFromMmap opens a file read-only through a memory mapping instead of
+// copying it into Go strings. The rope's leaves are MmapLeaf nodes that
+// reference the mapped pages, so opening a file costs no heap memory for
+// its content.
+//
+// Reads such as Slice, String and searching copy the text they return out
+// of the mapping, so strings obtained from the rope never reference it.
+// Edits are copy-on-write at the leaf: a leaf that an edit rewrites becomes
+// an ordinary LeafNode, while untouched leaves keep referencing the
+// mapping. Call Detach to copy a rope out of the mapping completely, e.g.
+// before releasing it.
+
+// NewFromMmap memory-maps the file at path and returns a rope over its
+// content, together with a function that releases the mapping.
+//
+// The file must contain valid UTF-8; otherwise the mapping is released and
+// an *ErrInvalidUTF8 is returned. The file must not be modified while
+// mapped. After the release function is called, the rope and every rope
+// derived from it by editing are unusable: any access may crash the
+// program. Call Detach first for ropes that need to outlive the mapping.
+//
+// Example:
+//
+//	r, closeMap, err := rope.NewFromMmap("huge.log")
+//	if err != nil {
+//	    return err
+//	}
+//	defer closeMap()
+//	fmt.Println(r.LineCount())
+func NewFromMmap(path string) (*Rope, func() error, error) {
+	data, release, err := mmapFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !utf8.Valid(data) {
+		err := &ErrInvalidUTF8{Offset: invalidUTF8Offset(string(data))}
+		release()
+		return nil, nil, err
+	}
+	return newMappedRope(data), release, nil
+}
+
+// newMappedRope builds a balanced rope of MmapLeaf nodes over data, which
+// must be valid UTF-8.
+func newMappedRope(data []byte) *Rope {
+	if len(data) == 0 {
+		return Empty()
+	}
+
+	var leaves []*MmapLeaf
+	length, size := 0, len(data)
+	for len(data) > 0 {
+		cut := len(data)
+		if cut > DefaultMaxLeafSize {
+			cut = DefaultMaxLeafSize
+			for cut > 0 && !utf8.RuneStart(data[cut]) {
+				cut--
+			}
+		}
+		leaf := newMmapLeaf(data[:cut:cut])
+		leaves = append(leaves, leaf)
+		length += leaf.length
+		data = data[cut:]
+	}
+
+	return &Rope{
+		root:   buildBalancedTree(leaves, 0, len(leaves)),
+		length: length,
+		size:   size,
+	}
+}
+
+// Detach returns a copy of the rope whose leaves are all LeafNodes owning
+// their memory, so it no longer references any memory mapping it was
+// created from. The tree shape is not preserved.
+func (r *Rope) Detach() *Rope {
+	if r == nil || r.Length() == 0 {
+		return Empty()
+	}
+
+	var leaves []*LeafNode
+	forEachLeaf(r.root, func(text string) bool {
+		if text != "" {
+			leaves = append(leaves, newLeafNode(text))
+		}
+		return true
+	})
+	return &Rope{
+		root:   buildBalancedTree(leaves, 0, len(leaves)),
+		length: r.length,
+		size:   r.size,
+	}
+}
+
+// ========== Mapped Leaf Node ==========
+
+// MmapLeaf is a leaf node whose text lives in a read-only memory mapping
+// created by NewFromMmap. Slice copies the text out of the mapping, and
+// edits replace the leaf with a LeafNode holding the new text, so the
+// mapping is never written to and never escapes the rope.
+type MmapLeaf struct {
+	data    []byte // Part of the mapping, split at a rune boundary
+	length  int    // Number of characters, counted once at construction
+	isASCII bool
+}
+
+// newMmapLeaf creates a leaf over data, which must be valid UTF-8.
+func newMmapLeaf(data []byte) *MmapLeaf {
+	length := utf8.RuneCount(data)
+	return &MmapLeaf{data: data, length: length, isASCII: length == len(data)}
+}
+
+// Length returns the number of characters.
+func (n *MmapLeaf) Length() int {
+	return n.length
+}
+
+// Size returns the number of bytes.
+func (n *MmapLeaf) Size() int {
+	return len(n.data)
+}
+
+// Slice returns a copy of the text between the character positions start
+// and end.
+func (n *MmapLeaf) Slice(start, end int) string {
+	if n.isASCII {
+		return string(n.data[start:end])
+	}
+
+	byteStart := 0
+	for i := 0; i < start; i++ {
+		_, size := utf8.DecodeRune(n.data[byteStart:])
+		byteStart += size
+	}
+
+	byteEnd := byteStart
+	for i := start; i < end; i++ {
+		_, size := utf8.DecodeRune(n.data[byteEnd:])
+		byteEnd += size
+	}
+
+	return string(n.data[byteStart:byteEnd])
+}
+
+// IsLeaf returns true.
+func (n *MmapLeaf) IsLeaf() bool {
+	return true
+}
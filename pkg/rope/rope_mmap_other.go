@@ -0,0 +1,17 @@
+//go:build !unix
+
+package rope
+
+import (
+	"os"
+)
+
+// mmapFile reads the whole file on platforms without mmap support.
+// The returned release function is a no-op.
+func mmapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,164 @@
+package rope
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTempFile writes content to a new file in a test temp directory.
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// aliasesMapping reports whether s points into data.
+func aliasesMapping(s string, data []byte) bool {
+	if s == "" || len(data) == 0 {
+		return false
+	}
+	p := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(data)))
+	return p >= start && p < start+uintptr(len(data))
+}
+
+// forEachNode calls fn with every leaf node of n in order.
+func forEachNode(n RopeNode, fn func(leaf RopeNode)) {
+	if internal, ok := n.(*InternalNode); ok {
+		forEachNode(internal.left, fn)
+		forEachNode(internal.right, fn)
+		return
+	}
+	fn(n)
+}
+
+// TestNewFromMmap_ReadAndSearch tests reading and searching a large mapped file
+func TestNewFromMmap_ReadAndSearch(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 20000; i++ {
+		sb.WriteString("line of text with ünïcode 世界\n")
+	}
+	sb.WriteString("needle at the end 🎯")
+	content := sb.String()
+	path := writeTempFile(t, content)
+
+	r, closeMap, err := NewFromMmap(path)
+	require.NoError(t, err)
+	defer closeMap()
+
+	assert.Equal(t, content, r.String())
+	assert.Equal(t, len(content), r.Size())
+	assert.Equal(t, 20001, r.LineCount())
+	assert.NoError(t, r.Validate())
+	assert.True(t, r.ChunkCount() > 1)
+
+	line, err := r.Line(12345)
+	require.NoError(t, err)
+	assert.Equal(t, "line of text with ünïcode 世界", line)
+
+	assert.True(t, r.Contains("needle at the end"))
+	results := r.Grep("needle", SearchOptions{})
+	require.Len(t, results, 1)
+	assert.Equal(t, 20000, results[0].LineNumber)
+}
+
+// TestNewFromMmap_EditDetachesLeaf tests that an edited leaf no longer references the mapping
+func TestNewFromMmap_EditDetachesLeaf(t *testing.T) {
+	content := strings.Repeat("abcdefghij", 1000)
+	data, release, err := mmapFile(writeTempFile(t, content))
+	require.NoError(t, err)
+	defer release()
+
+	r := newMappedRope(data)
+	mapped := 0
+	forEachNode(r.root, func(leaf RopeNode) {
+		_, ok := leaf.(*MmapLeaf)
+		assert.True(t, ok)
+		mapped++
+	})
+	assert.True(t, mapped > 1)
+
+	// Reads copy out of the mapping
+	slice, err := r.Slice(10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefghij", slice)
+	assert.False(t, aliasesMapping(slice, data))
+	forEachLeaf(r.root, func(text string) bool {
+		assert.False(t, aliasesMapping(text, data))
+		return true
+	})
+
+	edited, err := r.Insert(5000, "XYZ")
+	require.NoError(t, err)
+	assert.Equal(t, content[:5000]+"XYZ"+content[5000:], edited.String())
+	assert.NoError(t, edited.Validate())
+
+	// Only the leaf holding the inserted text was copied out of the mapping
+	stillMapped := 0
+	forEachNode(edited.root, func(leaf RopeNode) {
+		switch leaf := leaf.(type) {
+		case *MmapLeaf:
+			stillMapped++
+		case *LeafNode:
+			assert.False(t, aliasesMapping(leaf.text, data))
+		}
+	})
+	assert.Equal(t, mapped-1, stillMapped)
+
+	// Detach copies every remaining leaf
+	detached := edited.Detach()
+	assert.Equal(t, edited.String(), detached.String())
+	forEachNode(detached.root, func(leaf RopeNode) {
+		assert.IsType(t, &LeafNode{}, leaf)
+	})
+}
+
+// TestNewFromMmap_Edits tests editing operations on a mapped non-ASCII rope
+func TestNewFromMmap_Edits(t *testing.T) {
+	content := strings.Repeat("héllo wörld 世界\n", 500)
+	r, closeMap, err := NewFromMmap(writeTempFile(t, content))
+	require.NoError(t, err)
+	defer closeMap()
+
+	expected := New(content)
+	assert.Equal(t, expected.Length(), r.Length())
+	assert.Equal(t, expected.CharToByte(3000), r.CharToByte(3000))
+
+	edited, err := r.Delete(100, 5000)
+	require.NoError(t, err)
+	want, err := expected.Delete(100, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, want.String(), edited.String())
+
+	left, right, err := r.Split(1234)
+	require.NoError(t, err)
+	assert.Equal(t, content, left.Concat(right).String())
+	assert.Equal(t, content, r.Balance().String())
+	assert.Equal(t, content, r.Compact().String())
+}
+
+// TestNewFromMmap_InvalidUTF8 tests that a file with invalid UTF-8 is rejected
+func TestNewFromMmap_InvalidUTF8(t *testing.T) {
+	_, _, err := NewFromMmap(writeTempFile(t, "ok\xffno"))
+	var invalid *ErrInvalidUTF8
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, 2, invalid.Offset)
+}
+
+// TestNewFromMmap_EmptyAndMissing tests empty and missing files
+func TestNewFromMmap_EmptyAndMissing(t *testing.T) {
+	r, closeMap, err := NewFromMmap(writeTempFile(t, ""))
+	require.NoError(t, err)
+	assert.Equal(t, 0, r.Length())
+	assert.NoError(t, closeMap())
+
+	_, _, err = NewFromMmap(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
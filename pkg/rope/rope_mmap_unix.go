@@ -0,0 +1,50 @@
+//go:build unix
+
+package rope
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the file at path read-only and returns the mapped bytes
+// and a function that unmaps them.
+func mmapFile(path string) ([]byte, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		// Zero-length mappings are not allowed
+		return nil, func() error { return nil }, nil
+	}
+	if int64(int(size)) != size {
+		return nil, nil, &ErrInvalidInput{
+			Parameter: "path",
+			Value:     path,
+			Reason:    "file too large to map",
+		}
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+
+	released := false
+	release := func() error {
+		if released {
+			return nil
+		}
+		released = true
+		return syscall.Munmap(data)
+	}
+	return data, release, nil
+}
@@ -1,5 +1,10 @@
 package rope
 
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
 // SplitOff splits the rope at the given character position, returning
 // a new rope containing the text after the split point, and a new rope
 // containing the text before the split point.
@@ -30,3 +35,232 @@ func (r *Rope) SplitOff(pos int) (*Rope, *Rope, error) {
 
 	return left, right, nil
 }
+
+// Truncate returns the first n characters of the rope.
+// It splits the tree once, so it runs in O(log n).
+// Returns an error if n is outside [0, Length()].
+//
+// Example:
+//
+//	r := rope.New("Hello World")
+//	head, _ := r.Truncate(5)
+//	fmt.Println(head.String()) // "Hello"
+func (r *Rope) Truncate(n int) (*Rope, error) {
+	left, _, err := r.Split(n)
+	if err != nil {
+		return nil, truncateError("Truncate", n, r.Length())
+	}
+	return left, nil
+}
+
+// Drop returns the rope without its first n characters.
+// It splits the tree once, so it runs in O(log n).
+// Returns an error if n is outside [0, Length()].
+//
+// Example:
+//
+//	r := rope.New("Hello World")
+//	tail, _ := r.Drop(6)
+//	fmt.Println(tail.String()) // "World"
+func (r *Rope) Drop(n int) (*Rope, error) {
+	_, right, err := r.Split(n)
+	if err != nil {
+		return nil, truncateError("Drop", n, r.Length())
+	}
+	return right, nil
+}
+
+// Fields splits the rope around runs of whitespace, as unicode.IsSpace
+// defines it, like strings.Fields. Returns the pieces in order as sub-ropes
+// sharing structure with r; the document is walked once and never copied
+// into a single string. Returns nil if the rope holds only whitespace.
+//
+// Example:
+//
+//	r := rope.New("  foo bar\t\tbaz\n")
+//	for _, f := range r.Fields() {
+//	    fmt.Println(f.String()) // "foo", "bar", "baz"
+//	}
+func (r *Rope) Fields() []*Rope {
+	if r == nil || r.Length() == 0 {
+		return nil
+	}
+
+	var fields []*Rope
+	start, pos := -1, 0
+	forEachLeaf(r.root, func(text string) bool {
+		for _, ch := range text {
+			space := unicode.IsSpace(ch)
+			if space && start >= 0 {
+				fields = append(fields, r.subRope(start, pos))
+				start = -1
+			} else if !space && start < 0 {
+				start = pos
+			}
+			pos++
+		}
+		return true
+	})
+	if start >= 0 {
+		fields = append(fields, r.subRope(start, pos))
+	}
+	return fields
+}
+
+// SplitString splits the rope around every occurrence of sep, like
+// strings.Split: consecutive separators, or a separator at either end,
+// yield empty pieces, and a rope without sep yields itself as the only
+// piece. An empty sep splits after each character. The pieces are
+// sub-ropes sharing structure with r, and separators spanning leaf
+// boundaries are found.
+//
+// Example:
+//
+//	r := rope.New("a,,b,")
+//	for _, piece := range r.SplitString(",") {
+//	    fmt.Printf("%q ", piece.String()) // "a" "" "b" ""
+//	}
+func (r *Rope) SplitString(sep string) []*Rope {
+	if r == nil {
+		r = Empty()
+	}
+	if sep == "" {
+		pieces := make([]*Rope, r.Length())
+		for i := range pieces {
+			pieces[i] = r.subRope(i, i+1)
+		}
+		return pieces
+	}
+
+	var pieces []*Rope
+	start := 0
+	it := r.FindIter(sep, SearchOptions{})
+	for it.Next() {
+		m := it.Match()
+		pieces = append(pieces, r.subRope(start, m.From()))
+		start = m.To()
+	}
+	return append(pieces, r.subRope(start, r.Length()))
+}
+
+// subRope returns the characters [start, end) as a rope sharing structure
+// with r. The caller checks that the range is valid.
+func (r *Rope) subRope(start, end int) *Rope {
+	_, mid, _, err := r.Split3(start, end)
+	if err != nil {
+		return Empty()
+	}
+	return mid
+}
+
+// TrimPrefix returns the rope without prefix, like strings.TrimPrefix.
+// If the rope does not start with prefix, r itself is returned. The result
+// shares structure with r.
+//
+// Example:
+//
+//	r := rope.New("// comment")
+//	fmt.Println(r.TrimPrefix("// ").String()) // "comment"
+func (r *Rope) TrimPrefix(prefix string) *Rope {
+	if prefix == "" || !r.StartsWith(prefix) {
+		return r
+	}
+	tail, err := r.Drop(utf8.RuneCountInString(prefix))
+	if err != nil {
+		return r
+	}
+	return tail
+}
+
+// TrimSuffix returns the rope without suffix, like strings.TrimSuffix.
+// If the rope does not end with suffix, r itself is returned. The result
+// shares structure with r.
+//
+// Example:
+//
+//	r := rope.New("main.go")
+//	fmt.Println(r.TrimSuffix(".go").String()) // "main"
+func (r *Rope) TrimSuffix(suffix string) *Rope {
+	if suffix == "" || !r.EndsWith(suffix) {
+		return r
+	}
+	head, err := r.Truncate(r.Length() - utf8.RuneCountInString(suffix))
+	if err != nil {
+		return r
+	}
+	return head
+}
+
+// TruncateBytes returns the first n bytes of the rope.
+// Returns an error if n is outside [0, Size()] or does not fall on a
+// character boundary.
+func (r *Rope) TruncateBytes(n int) (*Rope, error) {
+	pos, err := r.charAtByteBoundary("TruncateBytes", n)
+	if err != nil {
+		return nil, err
+	}
+	return r.Truncate(pos)
+}
+
+// DropBytes returns the rope without its first n bytes.
+// Returns an error if n is outside [0, Size()] or does not fall on a
+// character boundary.
+func (r *Rope) DropBytes(n int) (*Rope, error) {
+	pos, err := r.charAtByteBoundary("DropBytes", n)
+	if err != nil {
+		return nil, err
+	}
+	return r.Drop(pos)
+}
+
+// truncateError reports an out-of-range count for Truncate-style operations.
+func truncateError(operation string, n, max int) error {
+	return &ErrOutOfBounds{
+		Operation: operation,
+		Position:  n,
+		Min:       0,
+		Max:       max + 1,
+	}
+}
+
+// charAtByteBoundary converts a byte offset to a character position by
+// descending the tree, failing if the offset splits a character.
+func (r *Rope) charAtByteBoundary(operation string, byteIdx int) (int, error) {
+	if byteIdx < 0 || byteIdx > r.Size() {
+		return 0, truncateError(operation, byteIdx, r.Size())
+	}
+	if byteIdx == 0 {
+		return 0, nil
+	}
+
+	offset, charIdx := byteIdx, 0
+	node := r.root
+	for {
+		internal, ok := node.(*InternalNode)
+		if !ok {
+			break
+		}
+		if offset < internal.size {
+			node = internal.left
+		} else {
+			offset -= internal.size
+			charIdx += internal.length
+			node = internal.right
+		}
+	}
+
+	var text string
+	if leaf, ok := node.(*LeafNode); ok {
+		text = leaf.text
+	} else {
+		text = node.Slice(0, node.Length())
+	}
+	if offset < len(text) && !utf8.RuneStart(text[offset]) {
+		return 0, &ErrInvalidInput{
+			Parameter: "n",
+			Value:     byteIdx,
+			Reason:    "not on a character boundary",
+		}
+	}
+	return charIdx + utf8.RuneCountInString(text[:offset]), nil
+}
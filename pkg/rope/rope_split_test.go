@@ -95,6 +95,44 @@ func TestRope_SplitAndAppend(t *testing.T) {
 	assert.Equal(t, "Hello World", r4.String())
 }
 
+// ============================================================================
+// Split3 Tests
+// ============================================================================
+
+func TestRope_Split3(t *testing.T) {
+	text := "Hello, 世界! The quick brown fox 🦊 jumps."
+	r := chunkedRope(text, 4)
+	n := r.Length()
+
+	for start := 0; start <= n; start++ {
+		for end := start; end <= n; end++ {
+			left, mid, right, err := r.Split3(start, end)
+			assert.NoError(t, err)
+
+			slice, err := r.Slice(start, end)
+			assert.NoError(t, err)
+			assert.Equal(t, slice, mid.String())
+			assert.Equal(t, start, left.Length())
+			assert.Equal(t, n-end, right.Length())
+			assert.Equal(t, text, left.Concat(mid).Concat(right).String())
+		}
+	}
+}
+
+func TestRope_Split3_InvalidRange(t *testing.T) {
+	r := New("Hello")
+
+	for _, rng := range [][2]int{{-1, 2}, {3, 2}, {0, 6}} {
+		_, _, _, err := r.Split3(rng[0], rng[1])
+		var rangeErr *ErrInvalidRange
+		assert.ErrorAs(t, err, &rangeErr)
+	}
+
+	left, mid, right, err := New("").Split3(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "", left.String()+mid.String()+right.String())
+}
+
 // ============================================================================
 // Stream I/O Tests
 // ============================================================================
@@ -373,3 +411,169 @@ func BenchmarkRope_Reader(b *testing.B) {
 		io.ReadAll(reader)
 	}
 }
+
+// ============================================================================
+// Truncate / Drop Tests
+// ============================================================================
+
+func TestTruncateAndDrop(t *testing.T) {
+	r := chunkedRope("Hello, 世界!", 3)
+
+	head, err := r.Truncate(7)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello, ", head.String())
+
+	tail, err := r.Drop(7)
+	assert.NoError(t, err)
+	assert.Equal(t, "世界!", tail.String())
+
+	// n == 0
+	head, err = r.Truncate(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "", head.String())
+	tail, err = r.Drop(0)
+	assert.NoError(t, err)
+	assert.Equal(t, r.String(), tail.String())
+
+	// n == Length()
+	head, err = r.Truncate(r.Length())
+	assert.NoError(t, err)
+	assert.Equal(t, r.String(), head.String())
+	tail, err = r.Drop(r.Length())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tail.Length())
+
+	// Out of range
+	var boundsErr *ErrOutOfBounds
+	_, err = r.Truncate(r.Length() + 1)
+	assert.ErrorAs(t, err, &boundsErr)
+	assert.Equal(t, "Truncate", boundsErr.Operation)
+	_, err = r.Drop(-1)
+	assert.ErrorAs(t, err, &boundsErr)
+	assert.Equal(t, "Drop", boundsErr.Operation)
+}
+
+func TestTruncateBytesAndDropBytes(t *testing.T) {
+	r := chunkedRope("ab世界cd", 2)
+
+	// "ab世" is 5 bytes
+	head, err := r.TruncateBytes(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab世", head.String())
+
+	tail, err := r.DropBytes(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "界cd", tail.String())
+
+	// n == 0 and n == Size()
+	head, err = r.TruncateBytes(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "", head.String())
+	tail, err = r.DropBytes(r.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, "", tail.String())
+	head, err = r.TruncateBytes(r.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, r.String(), head.String())
+
+	// Inside a multi-byte character
+	var inputErr *ErrInvalidInput
+	_, err = r.TruncateBytes(3)
+	assert.ErrorAs(t, err, &inputErr)
+	_, err = r.DropBytes(6)
+	assert.ErrorAs(t, err, &inputErr)
+
+	// Out of range
+	var boundsErr *ErrOutOfBounds
+	_, err = r.DropBytes(r.Size() + 1)
+	assert.ErrorAs(t, err, &boundsErr)
+	_, err = r.TruncateBytes(-1)
+	assert.ErrorAs(t, err, &boundsErr)
+}
+
+// ============================================================================
+// Fields Tests
+// ============================================================================
+
+// ropeStrings returns the text of each rope.
+func ropeStrings(ropes []*Rope) []string {
+	texts := make([]string, len(ropes))
+	for i, r := range ropes {
+		texts[i] = r.String()
+	}
+	return texts
+}
+
+func TestRope_Fields(t *testing.T) {
+	text := "  foo \t\tbar\n\nbaz\r\n qux  "
+	assert.Equal(t, []string{"foo", "bar", "baz", "qux"}, ropeStrings(New(text).Fields()))
+	assert.Equal(t, strings.Fields(text), ropeStrings(chunkedRope(text, 3).Fields()))
+}
+
+func TestRope_Fields_UnicodeSpaces(t *testing.T) {
+	// No-break space, em space and ideographic space separate fields too
+	text := "\u00a0a\u2003b\u3000世界\u3000"
+	fields := chunkedRope(text, 4).Fields()
+	assert.Equal(t, []string{"a", "b", "世界"}, ropeStrings(fields))
+	assert.Equal(t, strings.Fields(text), ropeStrings(fields))
+}
+
+func TestRope_Fields_Empty(t *testing.T) {
+	assert.Nil(t, Empty().Fields())
+	assert.Nil(t, New(" \t\n ").Fields())
+	assert.Equal(t, []string{"word"}, ropeStrings(New("word").Fields()))
+}
+
+// ============================================================================
+// SplitString Tests
+// ============================================================================
+
+func TestRope_SplitString_CSV(t *testing.T) {
+	for _, text := range []string{"name,age,,city,", ",leading,field", "a,b", "no separator", ""} {
+		want := strings.Split(text, ",")
+		assert.Equal(t, want, ropeStrings(New(text).SplitString(",")), text)
+		assert.Equal(t, want, ropeStrings(chunkedRope(text, 2).SplitString(",")), text)
+	}
+}
+
+func TestRope_SplitString_AcrossLeaves(t *testing.T) {
+	// The multi-character separator straddles leaf boundaries
+	text := "one<=>two<=><=>three<=>"
+	for size := 1; size <= 5; size++ {
+		pieces := chunkedRope(text, size).SplitString("<=>")
+		assert.Equal(t, []string{"one", "two", "", "three", ""}, ropeStrings(pieces))
+	}
+
+	assert.Equal(t, strings.Split("日本語", ""), ropeStrings(New("日本語").SplitString("")))
+}
+
+// ============================================================================
+// TrimPrefix / TrimSuffix Tests
+// ============================================================================
+
+func TestRope_TrimPrefix(t *testing.T) {
+	// The prefix covers several leaves
+	r := chunkedRope("#!/usr/bin/env bash\necho hi\n", 3)
+
+	trimmed := r.TrimPrefix("#!/usr/bin/env bash\n")
+	assert.Equal(t, "echo hi\n", trimmed.String())
+	assert.Equal(t, "echo hi\n", trimmed.TrimPrefix("").String())
+
+	// No match returns the very same rope
+	assert.Same(t, r, r.TrimPrefix("#!/bin/sh"))
+	assert.Same(t, r, r.TrimPrefix(r.String()+"x"))
+	assert.Equal(t, "", r.TrimPrefix(r.String()).String())
+	assert.Equal(t, "本語", New("日本語").TrimPrefix("日").String())
+}
+
+func TestRope_TrimSuffix(t *testing.T) {
+	r := chunkedRope("package main // END 🎯\n", 2)
+
+	trimmed := r.TrimSuffix(" // END 🎯\n")
+	assert.Equal(t, "package main", trimmed.String())
+
+	assert.Same(t, r, r.TrimSuffix("// START\n"))
+	assert.Same(t, r, r.TrimSuffix(""))
+	assert.Same(t, r, r.TrimSuffix("x"+r.String()))
+	assert.Equal(t, "", r.TrimSuffix(r.String()).String())
+}
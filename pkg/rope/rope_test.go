@@ -36,6 +36,30 @@ func TestNew_UTF8(t *testing.T) {
 	assert.Equal(t, "Hello 世界", r.String())
 }
 
+func TestNewValidated(t *testing.T) {
+	// "\xe4\xb8" is "\u4e16" with its last byte cut off
+	_, err := NewValidated("Hello \xe4\xb8 World")
+	var utf8Err *ErrInvalidUTF8
+	if assert.ErrorAs(t, err, &utf8Err) {
+		assert.Equal(t, 6, utf8Err.Offset)
+	}
+
+	r, err := NewValidated("Hello \u4e16")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, r.Length())
+}
+
+func TestNewLossy(t *testing.T) {
+	r := NewLossy("Hello \xe4\xb8 World")
+	assert.Equal(t, "Hello \uFFFD World", r.String())
+	assert.Equal(t, 13, r.Length())
+	assert.True(t, utf8.ValidString(r.String()))
+
+	// Valid text is kept as is, even across leaves
+	text := strings.Repeat("\u4e16\u754c", DefaultMaxLeafSize)
+	assert.Equal(t, text, NewLossy(text).String())
+}
+
 func TestEmpty(t *testing.T) {
 	r := Empty()
 	assert.Equal(t, 0, r.Length())
@@ -78,6 +102,43 @@ func TestSlice_OutOfBounds(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestSliceRunes_MatchesSlice(t *testing.T) {
+	text := "héllo 世界 🎉 wörld\nnext 🚀 line"
+	ropes := []*Rope{New(text), chunkedRope(text, 3), chunkedRope(text, 1)}
+	length := utf8.RuneCountInString(text)
+
+	for _, r := range ropes {
+		for start := 0; start <= length; start++ {
+			for end := start; end <= length; end++ {
+				s, err := r.Slice(start, end)
+				assert.NoError(t, err)
+				runes, err := r.SliceRunes(start, end)
+				assert.NoError(t, err)
+				assert.Equal(t, []rune(s), runes, "range %d..%d", start, end)
+			}
+		}
+	}
+}
+
+func TestSliceRunes_EmptyAndOutOfBounds(t *testing.T) {
+	r := New("日本語")
+
+	runes, err := r.SliceRunes(2, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, runes)
+
+	runes, err = Empty().SliceRunes(0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, runes)
+
+	_, err = r.SliceRunes(-1, 2)
+	assert.Error(t, err)
+	_, err = r.SliceRunes(0, 4)
+	assert.Error(t, err)
+	_, err = r.SliceRunes(2, 1)
+	assert.Error(t, err)
+}
+
 func TestCharAt(t *testing.T) {
 	r := New("Hello")
 
@@ -279,6 +340,35 @@ func TestConcat_Multiple(t *testing.T) {
 	assert.Equal(t, "Hello World", result.String())
 }
 
+func TestJoin_Lines(t *testing.T) {
+	lines := []string{"first line", "", "third 世界", "fourth", "🎯"}
+	parts := make([]*Rope, len(lines))
+	for i, line := range lines {
+		parts[i] = New(line)
+	}
+
+	joined := Join(New("\n"), parts...)
+	assert.Equal(t, strings.Join(lines, "\n"), joined.String())
+	assert.Equal(t, len(lines), joined.LineCount())
+	assert.NoError(t, joined.Validate())
+
+	// Many parts still give a shallow tree
+	many := make([]*Rope, 1000)
+	for i := range many {
+		many[i] = New(fmt.Sprintf("line %d", i))
+	}
+	joined = Join(New("\n"), many...)
+	assert.Equal(t, 1000, joined.LineCount())
+	assert.LessOrEqual(t, joined.Depth(), 12)
+}
+
+func TestJoin_EmptyAndSingle(t *testing.T) {
+	assert.Equal(t, "", Join(New(",")).String())
+	assert.Equal(t, "only", Join(New(","), New("only")).String())
+	assert.Equal(t, ",", Join(New(","), Empty(), Empty()).String())
+	assert.Equal(t, "ab", Join(nil, New("a"), New("b")).String())
+}
+
 // ========== Clone Tests ==========
 
 func TestClone(t *testing.T) {
@@ -300,6 +390,76 @@ func TestContains(t *testing.T) {
 	assert.False(t, r.Contains("xyz"))
 }
 
+func TestStartsWith_AcrossLeaves(t *testing.T) {
+	r := chunkedRope("#!/usr/bin/env bash\necho 世界\n", 3)
+
+	assert.True(t, r.StartsWith(""))
+	assert.True(t, r.StartsWith("#!"))
+	assert.True(t, r.StartsWith("#!/usr/bin/env bash\n"))
+	assert.False(t, r.StartsWith("#!/usr/bin/env zsh"))
+	assert.False(t, r.StartsWith(r.String() + "x"))
+	assert.True(t, r.StartsWith(r.String()))
+
+	assert.True(t, r.HasPrefixRope(chunkedRope("#!/usr/bin", 2)))
+	assert.False(t, r.HasPrefixRope(New("#?")))
+	assert.True(t, r.HasPrefixRope(Empty()))
+}
+
+func TestEndsWith_LongerThanLastLeaf(t *testing.T) {
+	r := chunkedRope("package main\n// END 🎯\n", 4)
+
+	assert.True(t, r.EndsWith(""))
+	assert.True(t, r.EndsWith("\n"))
+	assert.True(t, r.EndsWith("main\n// END 🎯\n"))
+	assert.False(t, r.EndsWith("// START 🎯\n"))
+	assert.False(t, r.EndsWith("x" + r.String()))
+
+	assert.True(t, r.HasSuffixRope(chunkedRope("// END 🎯\n", 3)))
+	assert.False(t, r.HasSuffixRope(New("END\n")))
+	assert.False(t, Empty().HasSuffixRope(New("a")))
+}
+
+func TestHeadTail(t *testing.T) {
+	text := "héllo 世界 wörld 🎯!"
+	r := chunkedRope(text, 4)
+	runes := []rune(text)
+
+	for n := 0; n <= len(runes)+2; n++ {
+		assert.Equal(t, string(runes[:min(n, len(runes))]), r.Head(n), "Head(%d)", n)
+		assert.Equal(t, string(runes[max(len(runes)-n, 0):]), r.Tail(n), "Tail(%d)", n)
+	}
+
+	// Exactly at a chunk boundary
+	assert.Equal(t, "héll", r.Head(4))
+	assert.Equal(t, "héllo 世界", r.Head(8))
+	assert.Equal(t, "", r.Head(-1))
+	assert.Equal(t, "", Empty().Tail(3))
+}
+
+func TestHeadTailBytes(t *testing.T) {
+	text := "héllo 世界 wörld 🎯!"
+	r := chunkedRope(text, 4)
+
+	for n := 0; n <= len(text)+2; n++ {
+		head := r.HeadBytes(n)
+		assert.True(t, utf8.ValidString(head))
+		assert.True(t, strings.HasPrefix(text, head))
+		assert.LessOrEqual(t, len(head), n)
+		assert.Greater(t, len(head)+utf8.UTFMax, min(n, len(text)))
+
+		tail := r.TailBytes(n)
+		assert.True(t, utf8.ValidString(tail))
+		assert.True(t, strings.HasSuffix(text, tail))
+		assert.LessOrEqual(t, len(tail), n)
+		assert.Greater(t, len(tail)+utf8.UTFMax, min(n, len(text)))
+	}
+
+	assert.Equal(t, text, r.HeadBytes(1000))
+	assert.Equal(t, "h", r.HeadBytes(2), "cut inside é snaps back")
+	assert.Equal(t, "!", r.TailBytes(4), "cut inside 🎯 snaps forward")
+	assert.Equal(t, "🎯!", r.TailBytes(5))
+}
+
 func TestIndex(t *testing.T) {
 	r := New("Hello World")
 
@@ -334,6 +494,29 @@ func TestEquals(t *testing.T) {
 	assert.False(t, r1.Equals(r3))
 }
 
+func TestEqualFold(t *testing.T) {
+	assert.True(t, New("HELLO").EqualFold(New("hello")))
+	assert.True(t, New("").EqualFold(New("")))
+	assert.False(t, New("hello").EqualFold(New("help!")))
+	assert.False(t, New("hello").EqualFold(New("hello!")))
+
+	// Folded forms with different byte lengths
+	assert.True(t, New("\u212Aelvin").EqualFold(New("kELVIN")))
+	assert.True(t, New("\u03a3\u0391\u03a3").EqualFold(New("\u03c3\u03b1\u03c2")))
+	// Simple folding maps single characters only: "\u0130" is one character,
+	// "i\u0307" two, and "\u00df" does not fold to "ss"
+	assert.False(t, New("\u0130").EqualFold(New("i\u0307")))
+	assert.False(t, New("stra\u00dfe").EqualFold(New("STRASSE")))
+
+	// Differing chunk boundaries
+	text := strings.Repeat("Gr\u00fc\u00dfe, \u4e16\u754c! \u212A ", 200)
+	upper := strings.ReplaceAll(strings.ToUpper(text), "\u212A", "k")
+	left, right := chunkedRope(text, 7), chunkedRope(upper, 13)
+	assert.True(t, left.EqualFold(right))
+	changed, _ := right.Replace(1500, 1501, "?")
+	assert.False(t, left.EqualFold(changed))
+}
+
 // ========== UTF-8 Tests ==========
 
 func TestUTF8_Chinese(t *testing.T) {
@@ -1201,3 +1384,38 @@ func randomString(length int) string {
 	}
 	return string(b)
 }
+
+// ========== Blank Tests ==========
+
+func TestIsEmpty(t *testing.T) {
+	assert.True(t, Empty().IsEmpty())
+	assert.True(t, (*Rope)(nil).IsEmpty())
+	assert.False(t, New(" ").IsEmpty())
+}
+
+func TestIsBlank(t *testing.T) {
+	assert.True(t, Empty().IsBlank())
+	assert.True(t, New("  \t\n\r\n ").IsBlank())
+	assert.False(t, New("   x   ").IsBlank())
+	assert.True(t, chunkedRope(strings.Repeat(" \n", 500), 7).IsBlank())
+}
+
+func TestIsBlank_EarlyExit(t *testing.T) {
+	r := chunkedRope("x"+strings.Repeat(" ", 10000), 16)
+
+	it := r.NewIterator()
+	assert.False(t, blankRunes(it, r.Length()))
+	assert.Equal(t, 1, it.Position(), "scan stops at the first non-space rune")
+}
+
+func TestLineIsBlank(t *testing.T) {
+	r := New("code\n   \n\t \r\n\nend")
+
+	assert.False(t, r.LineIsBlank(0))
+	assert.True(t, r.LineIsBlank(1))
+	assert.True(t, r.LineIsBlank(2))
+	assert.True(t, r.LineIsBlank(3))
+	assert.False(t, r.LineIsBlank(4))
+	assert.False(t, r.LineIsBlank(5))
+	assert.False(t, r.LineIsBlank(-1))
+}
@@ -0,0 +1,54 @@
+package rope
+
+// RopeView is a read-only handle to a rope, for passing a document to
+// consumers such as plugins that may read it but must not edit it. It
+// exposes only the ReadOnly methods, so unlike a *Rope it cannot be passed
+// where a MutableDocument is expected, and the underlying rope cannot be
+// recovered from it.
+//
+// Example:
+//
+//	plugin.Inspect(doc.View())
+type RopeView struct {
+	r *Rope
+}
+
+// View returns a read-only view of the rope.
+func (r *Rope) View() *RopeView {
+	return &RopeView{r: r}
+}
+
+// Length returns the number of characters in the viewed rope.
+func (v *RopeView) Length() int { return v.r.Length() }
+
+// LengthBytes returns the number of bytes in the viewed rope.
+func (v *RopeView) LengthBytes() int { return v.r.LengthBytes() }
+
+// LengthChars returns the number of characters in the viewed rope.
+func (v *RopeView) LengthChars() int { return v.r.LengthChars() }
+
+// String returns the viewed rope's content.
+func (v *RopeView) String() string { return v.r.String() }
+
+// Bytes returns a copy of the viewed rope's content.
+func (v *RopeView) Bytes() []byte { return v.r.Bytes() }
+
+// Slice returns the text between character positions start and end.
+func (v *RopeView) Slice(start, end int) (string, error) { return v.r.Slice(start, end) }
+
+// CharAt returns the character at position pos.
+func (v *RopeView) CharAt(pos int) (rune, error) { return v.r.CharAt(pos) }
+
+// ByteAt returns the byte at byte offset pos.
+func (v *RopeView) ByteAt(pos int) (byte, error) { return v.r.ByteAt(pos) }
+
+// Contains reports whether substring occurs in the viewed rope.
+func (v *RopeView) Contains(substring string) bool { return v.r.Contains(substring) }
+
+// Index returns the character position of the first occurrence of
+// substring, or -1 if it is not present.
+func (v *RopeView) Index(substring string) int { return v.r.Index(substring) }
+
+// LastIndex returns the character position of the last occurrence of
+// substring, or -1 if it is not present.
+func (v *RopeView) LastIndex(substring string) int { return v.r.LastIndex(substring) }
@@ -0,0 +1,41 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRopeView tests that a view reads the rope but cannot edit it
+func TestRopeView(t *testing.T) {
+	r := New("Hello, 世界")
+	v := r.View()
+
+	var doc any = v
+	_, ok := doc.(ReadOnly)
+	assert.True(t, ok)
+	_, ok = doc.(MutableDocument)
+	assert.False(t, ok)
+	_, ok = doc.(*Rope)
+	assert.False(t, ok)
+
+	assert.Equal(t, r.Length(), v.Length())
+	assert.Equal(t, r.LengthBytes(), v.LengthBytes())
+	assert.Equal(t, "Hello, 世界", v.String())
+	assert.Equal(t, []byte("Hello, 世界"), v.Bytes())
+
+	text, err := v.Slice(7, 9)
+	require.NoError(t, err)
+	assert.Equal(t, "世界", text)
+	ch, err := v.CharAt(8)
+	require.NoError(t, err)
+	assert.Equal(t, '界', ch)
+	b, err := v.ByteAt(0)
+	require.NoError(t, err)
+	assert.Equal(t, byte('H'), b)
+
+	assert.True(t, v.Contains("世"))
+	assert.Equal(t, 7, v.Index("世"))
+	assert.Equal(t, 4, v.LastIndex("o"))
+}
@@ -13,6 +13,7 @@ import "unicode/utf8"
 type Iterator struct {
 	rope         *Rope
 	chunksIter   *ChunksIterator
+	leaves       *leafWalker // Used instead of chunksIter when started mid-rope
 	currentChunk string
 	chunkPos     int  // Position within current chunk (in bytes)
 	charPos      int  // Position of last returned rune (-1 if none)
@@ -38,6 +39,7 @@ func (r *Rope) NewIterator() *Iterator {
 
 // IteratorAt creates a new iterator starting at the specified character position.
 // The iterator is positioned so that the first Next() call will return the rune at position pos.
+// Positions outside the rope are clamped to the first or last rune.
 func (r *Rope) IteratorAt(pos int) *Iterator {
 	if r == nil || r.Length() == 0 {
 		return &Iterator{rope: r, exhausted: true}
@@ -50,46 +52,64 @@ func (r *Rope) IteratorAt(pos int) *Iterator {
 		pos = r.Length() - 1
 	}
 
-	it := &Iterator{
-		rope:        r,
-		chunksIter:  r.Chunks(),
-		charPos:     pos - 1, // Will become pos after first Next()
-		currentRune: 0,
-		exhausted:   false,
-	}
-
-	// Find and load the chunk containing the target position
-	targetCharIdx := pos
-	currentCharIdx := 0
-	found := false
-
-	for it.chunksIter.Next() {
-		chunk := it.chunksIter.Current()
-		chunkLen := utf8.RuneCountInString(chunk)
-
-		if currentCharIdx+chunkLen > targetCharIdx {
-			// This chunk contains the target position
-			it.currentChunk = chunk
-			// Calculate byte position within this chunk
-			charsIntoChunk := targetCharIdx - currentCharIdx
-			bytePos := 0
-			for i := 0; i < charsIntoChunk; i++ {
-				_, size := utf8.DecodeRuneInString(it.currentChunk[bytePos:])
-				bytePos += size
-			}
-			it.chunkPos = bytePos
-			found = true
+	// Descend to the leaf containing pos, remembering the subtrees to its right
+	leaves := &leafWalker{}
+	node := r.root
+	offset := pos
+	for {
+		internal, ok := node.(*InternalNode)
+		if !ok {
 			break
 		}
+		if offset < internal.length {
+			leaves.stack = append(leaves.stack, internal.right)
+			node = internal.left
+		} else {
+			offset -= internal.length
+			node = internal.right
+		}
+	}
 
-		currentCharIdx += chunkLen
+	chunk := node.Slice(0, node.Length())
+	bytePos := 0
+	for i := 0; i < offset; i++ {
+		_, size := utf8.DecodeRuneInString(chunk[bytePos:])
+		bytePos += size
 	}
 
-	if !found {
-		it.exhausted = true
+	return &Iterator{
+		rope:         r,
+		leaves:       leaves,
+		currentChunk: chunk,
+		chunkPos:     bytePos,
+		charPos:      pos - 1, // Will become pos after first Next()
 	}
+}
 
-	return it
+// NewIteratorAt creates an iterator whose first Next() returns the rune at
+// pos. The iterator is positioned by descending the tree in O(log n) time,
+// which suits lexers resuming in the middle of a document.
+// pos may equal Length(), giving an iterator that is already exhausted.
+//
+// Example:
+//
+//	it, _ := r.NewIteratorAt(1000)
+//	for it.Next() {
+//	    fmt.Printf("%c", it.Current())
+//	}
+func (r *Rope) NewIteratorAt(pos int) (*Iterator, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, &ErrOutOfBounds{
+			Operation: "NewIteratorAt",
+			Position:  pos,
+			Min:       0,
+			Max:       r.Length() + 1,
+		}
+	}
+	if pos == r.Length() {
+		return &Iterator{rope: r, charPos: pos - 1, exhausted: true}, nil
+	}
+	return r.IteratorAt(pos), nil
 }
 
 // Next advances to the next rune and returns true if there are more runes.
@@ -100,11 +120,10 @@ func (it *Iterator) Next() bool {
 
 	// If we don't have a current chunk, get the first one
 	if it.currentChunk == "" {
-		if !it.chunksIter.Next() {
+		if !it.nextChunk() {
 			it.exhausted = true
 			return false
 		}
-		it.currentChunk = it.chunksIter.Current()
 		it.chunkPos = 0
 	}
 
@@ -125,6 +144,20 @@ func (it *Iterator) Next() bool {
 	return true
 }
 
+// nextChunk loads the next chunk into currentChunk.
+func (it *Iterator) nextChunk() bool {
+	if it.leaves != nil {
+		chunk, ok := it.leaves.next()
+		it.currentChunk = chunk
+		return ok
+	}
+	if !it.chunksIter.Next() {
+		return false
+	}
+	it.currentChunk = it.chunksIter.Current()
+	return true
+}
+
 // Current returns the current rune.
 // Panics if Next() hasn't been called yet or the iterator is exhausted.
 func (it *Iterator) Current() rune {
@@ -148,6 +181,7 @@ func (it *Iterator) Reset() {
 	}
 
 	it.chunksIter = it.rope.Chunks()
+	it.leaves = nil
 	it.currentChunk = ""
 	it.chunkPos = 0
 	it.charPos = -1
@@ -182,6 +216,9 @@ func (it *Iterator) HasNext() bool {
 	}
 
 	// Check if there are more chunks
+	if it.leaves != nil {
+		return it.charPos+1 < it.rope.Length()
+	}
 	return it.chunksIter.Position()+1 < it.chunksIter.Count()
 }
 
@@ -242,6 +279,10 @@ func (it *Iterator) Peek() (rune, bool) {
 	oldCharPos := it.charPos
 	oldExhausted := it.exhausted
 	oldCurrentRune := it.currentRune
+	var oldStack []RopeNode
+	if it.leaves != nil {
+		oldStack = append(oldStack, it.leaves.stack...)
+	}
 
 	// Advance to next
 	hasNext := it.Next()
@@ -258,6 +299,9 @@ func (it *Iterator) Peek() (rune, bool) {
 	it.charPos = oldCharPos
 	it.exhausted = oldExhausted
 	it.currentRune = oldCurrentRune
+	if it.leaves != nil {
+		it.leaves.stack = oldStack
+	}
 
 	return r, hasNext
 }
@@ -2,12 +2,17 @@ package rope
 
 // Selection represents a collection of selection ranges.
 // It always contains at least one range.
+//
+// A single cursor or selection is a Selection with one Range, whose Anchor
+// and Head are the fixed and moving ends; multiple cursors add more ranges,
+// one of which is primary.
 type Selection struct {
 	ranges       []Range
 	primaryIndex int
 }
 
-// NewSelection creates a new Selection with a single Range.
+// NewSelection creates a new Selection from the given ranges, with the
+// first one as primary. Without ranges it holds a single cursor at 0.
 func NewSelection(ranges ...Range) *Selection {
 	if len(ranges) == 0 {
 		// A selection must have at least one range
@@ -71,6 +76,30 @@ func (s *Selection) SetPrimary(index int) {
 	}
 }
 
+// NextPrimary returns a copy of the selection with the next range as the
+// primary one, wrapping around after the last range.
+func (s *Selection) NextPrimary() *Selection {
+	return s.withPrimaryOffset(1)
+}
+
+// PrevPrimary returns a copy of the selection with the previous range as the
+// primary one, wrapping around before the first range.
+func (s *Selection) PrevPrimary() *Selection {
+	return s.withPrimaryOffset(-1)
+}
+
+// withPrimaryOffset returns a copy of the selection with the primary index
+// moved by offset, modulo the number of ranges.
+func (s *Selection) withPrimaryOffset(offset int) *Selection {
+	ranges := make([]Range, len(s.ranges))
+	copy(ranges, s.ranges)
+	n := len(ranges)
+	if n == 0 {
+		return NewSelection()
+	}
+	return NewSelectionWithPrimary(ranges, ((s.primaryIndex+offset)%n+n)%n)
+}
+
 // ========== Position Mapping Integration ==========
 
 // MapPositions maps all cursor positions in the selection through a changeset.
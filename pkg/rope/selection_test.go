@@ -70,6 +70,44 @@ func TestRange_Contains(t *testing.T) {
 	}
 }
 
+// TestRange_Clamp tests clamping ranges into the document
+func TestRange_Clamp(t *testing.T) {
+	testCases := []struct {
+		name   string
+		rng    Range
+		docLen int
+		expect Range
+	}{
+		{"inside", NewRange(2, 4), 10, NewRange(2, 4)},
+		{"beyond EOF", NewRange(3, 25), 10, NewRange(3, 10)},
+		{"backward beyond EOF", NewRange(30, 5), 10, NewRange(10, 5)},
+		{"negative anchor", NewRange(-4, 6), 10, NewRange(0, 6)},
+		{"cursor past end", Point(12), 10, Point(10)},
+		{"empty document", NewRange(-1, 3), 0, Point(0)},
+	}
+
+	for _, tc := range testCases {
+		result := tc.rng.Clamp(tc.docLen)
+		if result != tc.expect {
+			t.Errorf("%s: expected %+v, got %+v", tc.name, tc.expect, result)
+		}
+	}
+}
+
+// TestRange_NewRangeClamped tests restoring a saved selection against a shorter document
+func TestRange_NewRangeClamped(t *testing.T) {
+	r := New("short")
+	saved := NewRange(-2, 40)
+
+	rng := NewRangeClamped(saved.Anchor, saved.Head, r.Length())
+	if rng.Anchor != 0 || rng.Head != 5 {
+		t.Errorf("Expected 0-5, got anchor=%d, head=%d", rng.Anchor, rng.Head)
+	}
+	if _, err := r.Slice(rng.From(), rng.To()); err != nil {
+		t.Errorf("clamped range should be sliceable: %v", err)
+	}
+}
+
 // TestSelection_NewSelection tests creating a new selection
 func TestSelection_NewSelection(t *testing.T) {
 	// Single cursor
@@ -121,6 +159,53 @@ func TestSelection_NewSelectionWithPrimary(t *testing.T) {
 	}
 }
 
+// TestSelection_SingleCursor tests a selection holding one anchor/cursor pair
+func TestSelection_SingleCursor(t *testing.T) {
+	doc := New("hello world")
+	sel := NewSelection(NewRange(6, 2))
+
+	primary := sel.Primary()
+	if sel.Len() != 1 || primary.Anchor != 6 || primary.Head != 2 {
+		t.Fatalf("Expected single range 6->2, got %d ranges, primary %v", sel.Len(), primary)
+	}
+	if primary.Cursor() != 2 || !primary.IsBackward() {
+		t.Errorf("Expected backward selection with cursor at 2, got cursor %d", primary.Cursor())
+	}
+
+	tx := NewTransaction(NewChangeSet(doc.Length()).Insert(">> ")).WithSelection(sel)
+	if got := tx.Selection().Primary(); got != primary {
+		t.Errorf("Expected transaction to keep selection %v, got %v", primary, got)
+	}
+
+	mapped := primary.Map(tx.Changes(), AssocAfter)
+	if mapped.Anchor != 9 || mapped.Head != 5 {
+		t.Errorf("Expected mapped range 9->5, got %v", mapped)
+	}
+}
+
+// TestSelection_MultiRange tests a selection holding several ranges
+func TestSelection_MultiRange(t *testing.T) {
+	doc := New("one two three")
+	sel := NewSelectionWithPrimary([]Range{Point(0), NewRange(4, 7), Point(13)}, 2)
+
+	tx := NewTransaction(NewChangeSet(doc.Length()).Retain(4).Insert("2 ")).WithSelection(sel)
+	if tx.Selection().Len() != 3 || tx.Selection().PrimaryIndex() != 2 {
+		t.Fatalf("Expected 3 ranges with primary 2, got %d ranges with primary %d",
+			tx.Selection().Len(), tx.Selection().PrimaryIndex())
+	}
+
+	mapped := sel.MapPositions(tx.Changes())
+	want := []int{0, 9, 15}
+	for i, r := range mapped.Iter() {
+		if r.Cursor() != want[i] {
+			t.Errorf("Range %d: expected cursor %d, got %d", i, want[i], r.Cursor())
+		}
+	}
+	if mapped.PrimaryIndex() != 2 {
+		t.Errorf("Expected primary index 2, got %d", mapped.PrimaryIndex())
+	}
+}
+
 // TestChangeIterator_Basic tests the ChangeIterator
 func TestChangeIterator_Basic(t *testing.T) {
 	doc := New("hello world")
@@ -248,3 +333,64 @@ func TestRange_Map(t *testing.T) {
 		t.Errorf("Expected to 13, got %d", mapped.To())
 	}
 }
+
+// TestSelection_CyclePrimary tests cycling the primary range with wrap-around
+func TestSelection_CyclePrimary(t *testing.T) {
+	sel := NewSelection(Point(1), Point(5), Point(9))
+
+	next := sel.NextPrimary()
+	if next.PrimaryIndex() != 1 || next.Primary() != Point(5) {
+		t.Errorf("Expected primary 1, got %d", next.PrimaryIndex())
+	}
+	if sel.PrimaryIndex() != 0 {
+		t.Error("NextPrimary should not modify the receiver")
+	}
+
+	wrapped := next.NextPrimary().NextPrimary()
+	if wrapped.PrimaryIndex() != 0 {
+		t.Errorf("Expected wrap-around to 0, got %d", wrapped.PrimaryIndex())
+	}
+
+	prev := sel.PrevPrimary()
+	if prev.PrimaryIndex() != 2 || prev.Primary() != Point(9) {
+		t.Errorf("Expected wrap-around to 2, got %d", prev.PrimaryIndex())
+	}
+	if prev.Len() != 3 {
+		t.Errorf("Expected 3 ranges, got %d", prev.Len())
+	}
+}
+
+// TestRope_RotateSelections tests rotating the text of three selected words
+func TestRope_RotateSelections(t *testing.T) {
+	r := New("one, two, three")
+	sel := NewSelection(NewRange(10, 15), NewRange(0, 3), NewRange(5, 8))
+
+	forward, cs, err := r.RotateSelections(sel, 1)
+	if err != nil {
+		t.Fatalf("RotateSelections failed: %v", err)
+	}
+	if forward.String() != "three, one, two" {
+		t.Errorf("Expected %q, got %q", "three, one, two", forward.String())
+	}
+	if applied, _ := cs.Apply(r); applied.String() != forward.String() {
+		t.Errorf("changeset applies to %q", applied.String())
+	}
+
+	backward, _, err := r.RotateSelections(sel, -1)
+	if err != nil {
+		t.Fatalf("RotateSelections failed: %v", err)
+	}
+	if backward.String() != "two, three, one" {
+		t.Errorf("Expected %q, got %q", "two, three, one", backward.String())
+	}
+
+	same, _, _ := r.RotateSelections(sel, 0)
+	if same.String() != r.String() {
+		t.Errorf("dir 0 should not change the text, got %q", same.String())
+	}
+
+	_, _, err = r.RotateSelections(NewSelection(NewRange(0, 4), NewRange(2, 6)), 1)
+	if err == nil {
+		t.Error("Expected error for overlapping ranges")
+	}
+}
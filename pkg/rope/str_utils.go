@@ -22,6 +22,9 @@ func (r *Rope) CharToByte(charIdx int) int {
 	if charIdx >= r.Length() {
 		return r.Size()
 	}
+	if nodeIsASCII(r.root) {
+		return charIdx
+	}
 
 	// Count bytes up to character index
 	byteIdx := 0
@@ -41,6 +44,9 @@ func (r *Rope) ByteToChar(byteIdx int) int {
 	if byteIdx >= r.Size() {
 		return r.Length()
 	}
+	if nodeIsASCII(r.root) {
+		return byteIdx
+	}
 
 	// Count characters up to byte index
 	charIdx := 0
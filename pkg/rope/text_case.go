@@ -0,0 +1,109 @@
+package rope
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ========== Title Case ==========
+
+// DefaultSmallWords are the articles, conjunctions and short prepositions
+// that English title case usually leaves lowercase.
+var DefaultSmallWords = []string{
+	"a", "an", "the",
+	"and", "but", "or", "nor", "for", "so", "yet",
+	"as", "at", "by", "in", "of", "off", "on", "per", "to", "up", "via",
+}
+
+// TitleOptions configures TitleCaseSmart.
+type TitleOptions struct {
+	// SmallWords are kept lowercase unless they start the text or a
+	// sentence (after '.', '!', '?' or ':'). Matching ignores case. Use
+	// DefaultSmallWords for English; nil capitalizes every word.
+	SmallWords []string
+
+	// PreserveAcronyms leaves words with a capital letter after their first
+	// character, such as "NASA" or "iPhone", exactly as written instead of
+	// lowercasing them.
+	PreserveAcronyms bool
+}
+
+// TitleCaseSmart converts the text to title case, capitalizing the first
+// letter of each word and lowercasing the rest, as one undoable edit.
+// Words are runs of letters, digits and apostrophes. Options exclude small
+// words and preserve acronyms. Returns the new rope and the ChangeSet of
+// the edit.
+//
+// Example:
+//
+//	r := rope.New("the quick NASA launch of the rocket")
+//	r2, _, _ := r.TitleCaseSmart(rope.TitleOptions{
+//	    SmallWords:       rope.DefaultSmallWords,
+//	    PreserveAcronyms: true,
+//	})
+//	fmt.Println(r2.String()) // "The Quick NASA Launch of the Rocket"
+func (r *Rope) TitleCaseSmart(opts TitleOptions) (*Rope, *ChangeSet, error) {
+	small := make(map[string]bool, len(opts.SmallWords))
+	for _, w := range opts.SmallWords {
+		small[strings.ToLower(w)] = true
+	}
+
+	var edits []EditOperation
+	var word []rune
+	wordStart, pos := 0, 0
+	sentenceStart := true
+
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		original := string(word)
+		if cased := titleCaseWord(word, small, opts.PreserveAcronyms, sentenceStart); cased != original {
+			edits = append(edits, EditOperation{From: wordStart, To: pos, Text: cased})
+		}
+		word = word[:0]
+		sentenceStart = false
+	}
+
+	it := r.NewIterator()
+	for it.Next() {
+		ch := it.Current()
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) || (ch == '\'' && len(word) > 0) {
+			if len(word) == 0 {
+				wordStart = pos
+			}
+			word = append(word, ch)
+		} else {
+			flush()
+			if strings.ContainsRune(".!?:", ch) {
+				sentenceStart = true
+			}
+		}
+		pos++
+	}
+	flush()
+
+	return r.applyEdits(edits)
+}
+
+// titleCaseWord returns word in title case. Small words are lowercased
+// unless first is set, and with preserveAcronyms a word with a capital
+// after its first character is returned unchanged.
+func titleCaseWord(word []rune, small map[string]bool, preserveAcronyms, first bool) string {
+	if preserveAcronyms {
+		for _, ch := range word[1:] {
+			if unicode.IsUpper(ch) {
+				return string(word)
+			}
+		}
+	}
+
+	cased := make([]rune, len(word))
+	for i, ch := range word {
+		cased[i] = unicode.ToLower(ch)
+	}
+	if first || !small[string(cased)] {
+		cased[0] = unicode.ToTitle(cased[0])
+	}
+	return string(cased)
+}
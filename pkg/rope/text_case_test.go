@@ -0,0 +1,40 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTitleCaseSmart tests small-word and acronym handling
+func TestTitleCaseSmart(t *testing.T) {
+	opts := TitleOptions{SmallWords: DefaultSmallWords, PreserveAcronyms: true}
+
+	tests := []struct {
+		name string
+		text string
+		opts TitleOptions
+		want string
+	}{
+		{"acronym and small words", "the quick NASA launch of the rocket", opts, "The Quick NASA Launch of the Rocket"},
+		{"sentence start", "war and peace: the novel", opts, "War and Peace: The Novel"},
+		{"mixed case kept", "my iPhone's case", opts, "My iPhone's Case"},
+		{"no options", "the quick NASA launch", TitleOptions{}, "The Quick Nasa Launch"},
+		{"punctuation and digits", "  hello-world, 2nd TRY\n", TitleOptions{}, "  Hello-World, 2nd Try\n"},
+		{"empty", "", opts, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.text)
+			result, cs, err := r.TitleCaseSmart(tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.String())
+			assert.Equal(t, tt.text, r.String())
+
+			applied, err := cs.Apply(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, applied.String())
+		})
+	}
+}
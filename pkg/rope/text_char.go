@@ -1,5 +1,10 @@
 package rope
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 // ========== Single Character Operations ==========
 
 // InsertChar inserts a single rune at the specified character position.
@@ -185,6 +190,55 @@ func (r *Rope) LastIndexOfCharBefore(pos int, ch rune) (int, error) {
 	return -1, nil
 }
 
+// IndexFunc returns the position of the first character satisfying fn,
+// or -1 if there is none. It mirrors strings.IndexFunc with character
+// positions instead of byte offsets.
+//
+// Example:
+//
+//	pos := r.IndexFunc(func(ch rune) bool { return ch > unicode.MaxASCII })
+func (r *Rope) IndexFunc(fn func(rune) bool) int {
+	if r == nil {
+		return -1
+	}
+	pos := 0
+	it := r.NewIterator()
+	for it.Next() {
+		if fn(it.Current()) {
+			return pos
+		}
+		pos++
+	}
+	return -1
+}
+
+// LastIndexFunc returns the position of the last character satisfying fn,
+// or -1 if there is none. The rope is scanned backward from the end, so a
+// match near the end is found without visiting the rest of the document.
+func (r *Rope) LastIndexFunc(fn func(rune) bool) int {
+	if r == nil {
+		return -1
+	}
+	pos := r.Length()
+	w := newLeafWalker(r, true)
+	for text, ok := w.next(); ok; text, ok = w.next() {
+		for text != "" {
+			ch, size := utf8.DecodeLastRuneInString(text)
+			text = text[:len(text)-size]
+			pos--
+			if fn(ch) {
+				return pos
+			}
+		}
+	}
+	return -1
+}
+
+// ContainsFunc reports whether any character in the rope satisfies fn.
+func (r *Rope) ContainsFunc(fn func(rune) bool) bool {
+	return r.IndexFunc(fn) >= 0
+}
+
 // CountChar counts the occurrences of a character in the rope.
 func (r *Rope) CountChar(ch rune) int {
 	if r == nil {
@@ -201,6 +255,54 @@ func (r *Rope) CountChar(ch rune) int {
 	return count
 }
 
+// CountSubstring counts the occurrences of sub in the rope, including ones
+// that span leaves. With overlapping set, matches may share characters, so
+// "aa" occurs twice in "aaa"; otherwise matches are counted left to right
+// without overlap, like strings.Count. An empty sub occurs Length()+1 times.
+//
+// Example:
+//
+//	r := rope.New("aaa")
+//	fmt.Println(r.CountSubstring("aa", true))  // 2
+//	fmt.Println(r.CountSubstring("aa", false)) // 1
+func (r *Rope) CountSubstring(sub string, overlapping bool) int {
+	if r == nil {
+		return 0
+	}
+	if sub == "" {
+		return r.Length() + 1
+	}
+
+	// window holds the unsearched tail of the previous leaves (too short to
+	// contain sub on its own) followed by the current leaf
+	count := 0
+	window := ""
+	base := 0 // Byte offset of window in the rope
+	next := 0 // Byte offset where the next match may start
+	forEachLeaf(r.root, func(text string) bool {
+		window += text
+		for {
+			i := strings.Index(window[next-base:], sub)
+			if i < 0 {
+				break
+			}
+			count++
+			if overlapping {
+				next += i + 1
+			} else {
+				next += i + len(sub)
+			}
+		}
+
+		// Earlier starts cannot fit sub in the bytes seen so far
+		keep := max(base+len(window)-len(sub)+1, next)
+		window = window[keep-base:]
+		base, next = keep, keep
+		return true
+	})
+	return count
+}
+
 // ========== Character Collection ==========
 
 // CollectChars collects all characters into a rune slice.
@@ -217,6 +319,34 @@ func (r *Rope) CollectChars() []rune {
 	return runes
 }
 
+// RunesRange returns the count runes starting at character position start.
+// Only the requested window is collected, using an iterator positioned at
+// start, so it is cheap on large documents.
+// Returns an error if the window does not lie within the rope.
+func (r *Rope) RunesRange(start, count int) ([]rune, error) {
+	if start < 0 || count < 0 || start+count > r.Length() {
+		return nil, &ErrInvalidRange{
+			Operation: "RunesRange",
+			Start:     start,
+			End:       start + count,
+			ValidMax:  r.Length(),
+		}
+	}
+	if count == 0 {
+		return []rune{}, nil
+	}
+
+	it, err := r.NewIteratorAt(start)
+	if err != nil {
+		return nil, err
+	}
+	runes := make([]rune, 0, count)
+	for len(runes) < count && it.Next() {
+		runes = append(runes, it.Current())
+	}
+	return runes, nil
+}
+
 // ToRunes returns all runes in the rope as a slice.
 // Deprecated: Use Runes() instead. This method is kept for backward compatibility.
 // The behavior is identical to Runes(), but Runes() is the preferred name.
@@ -338,6 +468,30 @@ func (r *Rope) ReverseChars() (*Rope, error) {
 	return b.Build()
 }
 
+// ReverseGraphemes reverses the order of the grapheme clusters in the rope,
+// keeping each cluster intact. Unlike ReverseChars, a combining mark stays
+// after its base character, emoji sequences and flags survive, and "\r\n"
+// is not turned into "\n\r".
+// Returns a new Rope, leaving the original unchanged.
+//
+// Example:
+//
+//	r := rope.New("ae\u0301")
+//	r2, _ := r.ReverseGraphemes()
+//	fmt.Println(r2.String()) // "e\u0301a"
+func (r *Rope) ReverseGraphemes() (*Rope, error) {
+	if r == nil || r.Length() <= 1 {
+		return r, nil
+	}
+
+	graphemes := r.Graphemes().Collect()
+	b := NewBuilder()
+	for i := len(graphemes) - 1; i >= 0; i-- {
+		b.Append(graphemes[i].Text)
+	}
+	return b.Build()
+}
+
 // ========== Character Categories ==========
 
 // IsWhitespace checks if a rune is whitespace.
@@ -489,3 +643,60 @@ func (r *Rope) TrimRightWhitespace() (*Rope, error) {
 func (r *Rope) TrimWhitespace() (*Rope, error) {
 	return r.TrimChar(IsWhitespace)
 }
+
+// CollapseWhitespace replaces every run of whitespace (as IsWhitespace
+// defines it) with a single space, e.g. to normalize pasted text, as one
+// undoable edit. With preserveLines set, line breaks are kept: a run
+// containing line breaks is replaced by just its line breaks, so lines
+// lose their indentation and trailing spaces but not their structure.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("a \t b  \n\n  c")
+//	r2, _, _ := r.CollapseWhitespace(false)
+//	fmt.Println(r2.String()) // "a b c"
+//	r3, _, _ := r.CollapseWhitespace(true)
+//	fmt.Println(r3.String()) // "a b\n\nc"
+func (r *Rope) CollapseWhitespace(preserveLines bool) (*Rope, *ChangeSet, error) {
+	var edits []EditOperation
+	var run, breaks strings.Builder
+	runStart, pos := -1, 0
+
+	flush := func() {
+		if runStart < 0 {
+			return
+		}
+		replacement := " "
+		if preserveLines && breaks.Len() > 0 {
+			replacement = breaks.String()
+		}
+		if run.String() != replacement {
+			edits = append(edits, EditOperation{From: runStart, To: pos, Text: replacement})
+		}
+		runStart = -1
+		run.Reset()
+		breaks.Reset()
+	}
+
+	it := r.NewIterator()
+	for it.Next() {
+		ch := it.Current()
+		if !IsWhitespace(ch) {
+			flush()
+			pos++
+			continue
+		}
+		if runStart < 0 {
+			runStart = pos
+		}
+		run.WriteRune(ch)
+		if ch == '\n' || ch == '\r' {
+			breaks.WriteRune(ch)
+		}
+		pos++
+	}
+	flush()
+
+	return r.applyEdits(edits)
+}
@@ -0,0 +1,106 @@
+package rope
+
+import (
+	"unicode"
+)
+
+// CharClass is the category of a character for word movement and
+// selection purposes.
+type CharClass int
+
+const (
+	// CharClassWhitespace is horizontal whitespace such as spaces and tabs.
+	CharClassWhitespace CharClass = iota
+
+	// CharClassWord is a character that forms words.
+	CharClassWord
+
+	// CharClassPunctuation is any other visible character.
+	CharClassPunctuation
+
+	// CharClassNewline is a line break character.
+	CharClassNewline
+)
+
+// String returns the string representation of CharClass
+func (c CharClass) String() string {
+	switch c {
+	case CharClassWhitespace:
+		return "Whitespace"
+	case CharClassWord:
+		return "Word"
+	case CharClassPunctuation:
+		return "Punctuation"
+	case CharClassNewline:
+		return "Newline"
+	default:
+		return "Unknown"
+	}
+}
+
+// DefaultCharClass classifies a character the way ropes do unless a custom
+// classifier is set: letters, digits and underscore are word characters
+// (as \w in regex), line breaks are newlines, other Unicode whitespace is
+// whitespace, and everything else is punctuation.
+func DefaultCharClass(ch rune) CharClass {
+	switch {
+	case ch == '\n' || ch == '\r' || ch == '\u0085' || ch == '\u2028' || ch == '\u2029':
+		return CharClassNewline
+	case unicode.IsSpace(ch):
+		return CharClassWhitespace
+	case unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_':
+		return CharClassWord
+	default:
+		return CharClassPunctuation
+	}
+}
+
+// WithCharClassifier returns a rope with the same content that classifies
+// characters with fn instead of DefaultCharClass. The classifier is used by
+// CharClassAt and by WordBoundary, so word movement can follow the rules of
+// a language, e.g. treating '-' as part of CSS identifiers.
+//
+// The classifier is kept by the ropes produced by editing the returned
+// rope: Insert, Delete, Replace, Split, Concat, ChangeSet.Apply and the
+// editing commands built on them. Functions that build a rope from new
+// text, such as New or Map, use the default classification. Passing nil
+// restores the default.
+//
+// Example:
+//
+//	css := r.WithCharClassifier(func(ch rune) rope.CharClass {
+//	    if ch == '-' {
+//	        return rope.CharClassWord
+//	    }
+//	    return rope.DefaultCharClass(ch)
+//	})
+func (r *Rope) WithCharClassifier(fn func(rune) CharClass) *Rope {
+	if r == nil {
+		r = Empty()
+	}
+	clone := r.derive(r.root, r.length, r.size)
+	clone.classifier = fn
+	clone.caches.Store(r.caches.Load())
+	return clone
+}
+
+// CharClassAt returns the class of the character at pos.
+// Positions outside the rope are reported as whitespace.
+func (r *Rope) CharClassAt(pos int) CharClass {
+	if r == nil || pos < 0 || pos >= r.Length() {
+		return CharClassWhitespace
+	}
+	ch, err := r.CharAt(pos)
+	if err != nil {
+		return CharClassWhitespace
+	}
+	return r.charClass(ch)
+}
+
+// charClass classifies ch with the rope's classifier, or the default one.
+func (r *Rope) charClass(ch rune) CharClass {
+	if r != nil && r.classifier != nil {
+		return r.classifier(ch)
+	}
+	return DefaultCharClass(ch)
+}
@@ -0,0 +1,117 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cssClassifier treats '-' as part of identifiers, as in CSS.
+func cssClassifier(ch rune) CharClass {
+	if ch == '-' {
+		return CharClassWord
+	}
+	return DefaultCharClass(ch)
+}
+
+// TestCharClassAt tests default classification
+func TestCharClassAt(t *testing.T) {
+	r := New("a_1 -\r\n\tx")
+
+	assert.Equal(t, CharClassWord, r.CharClassAt(0))
+	assert.Equal(t, CharClassWord, r.CharClassAt(1))
+	assert.Equal(t, CharClassWord, r.CharClassAt(2))
+	assert.Equal(t, CharClassWhitespace, r.CharClassAt(3))
+	assert.Equal(t, CharClassPunctuation, r.CharClassAt(4))
+	assert.Equal(t, CharClassNewline, r.CharClassAt(5))
+	assert.Equal(t, CharClassNewline, r.CharClassAt(6))
+	assert.Equal(t, CharClassWhitespace, r.CharClassAt(7))
+
+	// Out of range positions are whitespace
+	assert.Equal(t, CharClassWhitespace, r.CharClassAt(-1))
+	assert.Equal(t, CharClassWhitespace, r.CharClassAt(r.Length()))
+}
+
+// TestWithCharClassifier tests overriding the classifier
+func TestWithCharClassifier(t *testing.T) {
+	r := New("a-b")
+	css := r.WithCharClassifier(cssClassifier)
+
+	assert.Equal(t, CharClassPunctuation, r.CharClassAt(1))
+	assert.Equal(t, CharClassWord, css.CharClassAt(1))
+	assert.Equal(t, r.String(), css.String())
+
+	// nil restores the default
+	assert.Equal(t, CharClassPunctuation, css.WithCharClassifier(nil).CharClassAt(1))
+}
+
+// TestWithCharClassifier_Edits tests that edits keep the classifier
+func TestWithCharClassifier_Edits(t *testing.T) {
+	css := New("a-b").WithCharClassifier(cssClassifier)
+
+	inserted, err := css.Insert(3, " c-d")
+	require.NoError(t, err)
+	assert.Equal(t, CharClassWord, inserted.CharClassAt(5))
+
+	deleted, err := inserted.Delete(0, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "c-d", deleted.String())
+	assert.Equal(t, CharClassWord, deleted.CharClassAt(1))
+
+	// Commands built on ChangeSets keep it too
+	result, _, err := inserted.DeleteForward(0)
+	require.NoError(t, err)
+	assert.Equal(t, "-b c-d", result.String())
+	assert.Equal(t, CharClassWord, result.CharClassAt(4))
+
+	// Deleting everything and typing again keeps it
+	empty, err := css.Delete(0, css.Length())
+	require.NoError(t, err)
+	retyped, err := empty.Insert(0, "x-y")
+	require.NoError(t, err)
+	assert.Equal(t, CharClassWord, retyped.CharClassAt(1))
+}
+
+// TestWordBoundary_NextWordBoundary tests word movement with the default classifier
+func TestWordBoundary_NextWordBoundary(t *testing.T) {
+	wb := NewWordBoundary(New("foo.bar  baz\n  qux"))
+
+	assert.Equal(t, 3, wb.NextWordBoundary(0))
+	assert.Equal(t, 4, wb.NextWordBoundary(3))
+	assert.Equal(t, 9, wb.NextWordBoundary(4))
+	assert.Equal(t, 15, wb.NextWordBoundary(9))
+	assert.Equal(t, 18, wb.NextWordBoundary(15))
+
+	assert.Equal(t, 9, wb.PrevWordBoundary(15))
+	assert.Equal(t, 4, wb.PrevWordBoundary(9))
+	assert.Equal(t, 3, wb.PrevWordBoundary(4))
+	assert.Equal(t, 0, wb.PrevWordBoundary(3))
+}
+
+// TestWordBoundary_CustomClassifier tests that word boundaries follow the classifier
+func TestWordBoundary_CustomClassifier(t *testing.T) {
+	r := New("a { background-color: red; }")
+
+	// By default '-' splits the identifier
+	wb := NewWordBoundary(r)
+	assert.Equal(t, 14, wb.NextWordBoundary(4))
+	assert.False(t, wb.IsWordChar('-'))
+
+	// With the CSS classifier the whole identifier is one word
+	wb = NewWordBoundary(r.WithCharClassifier(cssClassifier))
+	assert.Equal(t, 20, wb.NextWordBoundary(4))
+	assert.Equal(t, 4, wb.PrevWordBoundary(20))
+	assert.True(t, wb.IsWordChar('-'))
+
+	// Text objects built on word boundaries follow it as well
+	css := r.WithCharClassifier(cssClassifier)
+	sel := css.ExpandSelection(Point(6), TextObjectWord)
+	assert.Equal(t, "background-color", sliceRange(css, sel))
+}
+
+// TestCharClass_String tests the string representation
+func TestCharClass_String(t *testing.T) {
+	assert.Equal(t, "Word", CharClassWord.String())
+	assert.Equal(t, "Newline", CharClassNewline.String())
+}
@@ -1,9 +1,12 @@
 package rope
 
 import (
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestCharOps_InsertChar tests single character insertion
@@ -445,6 +448,48 @@ func TestCharOps_CountChar(t *testing.T) {
 	}
 }
 
+// TestCharOps_CountSubstring tests overlapping and non-overlapping substring counts
+func TestCharOps_CountSubstring(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		sub         string
+		overlapping int
+		separate    int
+	}{
+		{"repeated letters", "aaa", "aa", 2, 1},
+		{"words", "the cat and the hat", "the", 2, 2},
+		{"periodic pattern", "abababa", "aba", 3, 2},
+		{"unicode", "你好你好你", "你好你", 2, 1},
+		{"not found", "hello", "xyz", 0, 0},
+		{"longer than text", "ab", "abc", 0, 0},
+		{"empty substring", "héllo", "", 6, 6},
+		{"empty rope", "", "a", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range []*Rope{New(tt.text), chunkedRope(tt.text, 1), chunkedRope(tt.text, 2)} {
+				assert.Equal(t, tt.overlapping, r.CountSubstring(tt.sub, true))
+				assert.Equal(t, tt.separate, r.CountSubstring(tt.sub, false))
+			}
+		})
+	}
+}
+
+// TestCharOps_CountSubstring_ChunkBoundary tests matches spanning chunk boundaries
+func TestCharOps_CountSubstring_ChunkBoundary(t *testing.T) {
+	r := New(strings.Repeat("x", DefaultMaxLeafSize-2) + "nee").
+		Concat(New("dle" + strings.Repeat("x", DefaultMaxLeafSize) + "need")).
+		Concat(New("leneedle"))
+	require.GreaterOrEqual(t, r.LeafCount(), 3)
+
+	assert.Equal(t, 3, r.CountSubstring("needle", false))
+	assert.Equal(t, 3, r.CountSubstring("needle", true))
+	assert.Equal(t, strings.Count(r.String(), "x"), r.CountSubstring("x", true))
+	assert.Equal(t, strings.Count(r.String(), "xx"), r.CountSubstring("xx", false))
+}
+
 // TestCharOps_CollectChars tests collecting all characters
 func TestCharOps_CollectChars(t *testing.T) {
 	tests := []struct {
@@ -483,6 +528,26 @@ func TestCharOps_CollectChars(t *testing.T) {
 	}
 }
 
+// TestCharOps_RunesRange tests collecting a window of characters
+func TestCharOps_RunesRange(t *testing.T) {
+	r := chunkedRope("Hello, 世界! naïve 🎯 text spanning several leaves", 5)
+	all := r.CollectChars()
+
+	for start := 0; start <= len(all); start++ {
+		for count := 0; start+count <= len(all); count++ {
+			runes, err := r.RunesRange(start, count)
+			assert.NoError(t, err)
+			assert.Equal(t, all[start:start+count], runes, "start=%d count=%d", start, count)
+		}
+	}
+
+	for _, tt := range [][2]int{{-1, 2}, {0, -1}, {len(all), 1}, {3, len(all)}} {
+		_, err := r.RunesRange(tt[0], tt[1])
+		var rangeErr *ErrInvalidRange
+		assert.ErrorAs(t, err, &rangeErr, "start=%d count=%d", tt[0], tt[1])
+	}
+}
+
 // TestCharOps_UniqueChars tests unique character collection
 func TestCharOps_UniqueChars(t *testing.T) {
 	tests := []struct {
@@ -723,6 +788,30 @@ func TestCharOps_ReverseChars(t *testing.T) {
 	}
 }
 
+// TestCharOps_ReverseGraphemes tests that reversal keeps grapheme clusters intact
+func TestCharOps_ReverseGraphemes(t *testing.T) {
+	// "cafe" with a combining acute, a family emoji joined by ZWJs, and CRLF
+	family := "\U0001F468\u200D\U0001F469\u200D\U0001F467"
+	r := New("cafe\u0301 " + family + "!\r\n")
+
+	result, err := r.ReverseGraphemes()
+	require.NoError(t, err)
+	assert.Equal(t, "\r\n!"+family+" e\u0301fac", result.String())
+	assert.Equal(t, r.LenGraphemes(), result.LenGraphemes())
+
+	// Reversing code points moves the accent onto the space and breaks the family apart
+	naive, err := r.ReverseChars()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(naive.String(), "\n\r!\U0001F467\u200D"))
+	assert.True(t, strings.HasSuffix(naive.String(), " \u0301efac"))
+	assert.NotEqual(t, result.String(), naive.String())
+
+	// Reversing twice restores the text
+	twice, err := result.ReverseGraphemes()
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), twice.String())
+}
+
 // TestCharOps_CategoryTests tests character category functions
 func TestCharOps_CategoryTests(t *testing.T) {
 	tests := []struct {
@@ -866,3 +955,62 @@ func TestCharOps_NilRope(t *testing.T) {
 	filtered, _ := r.FilterChars(func(ch rune) bool { return true })
 	assert.Equal(t, "", filtered.String())
 }
+
+// TestCharOps_IndexFunc tests finding the first character matching a predicate
+func TestCharOps_IndexFunc(t *testing.T) {
+	r := chunkedRope("plain ascii, then café and 日本", 4)
+	isNonASCII := func(ch rune) bool { return ch > unicode.MaxASCII }
+
+	assert.Equal(t, 21, r.IndexFunc(isNonASCII))
+	assert.True(t, r.ContainsFunc(isNonASCII))
+
+	ascii := New("only ascii")
+	assert.Equal(t, -1, ascii.IndexFunc(isNonASCII))
+	assert.False(t, ascii.ContainsFunc(isNonASCII))
+	assert.Equal(t, -1, Empty().IndexFunc(isNonASCII))
+}
+
+// TestCharOps_LastIndexFunc tests finding the last character matching a predicate
+func TestCharOps_LastIndexFunc(t *testing.T) {
+	r := chunkedRope("a1 b22 ç333 déjà", 3)
+
+	assert.Equal(t, 10, r.LastIndexFunc(unicode.IsDigit))
+	assert.Equal(t, 15, r.LastIndexFunc(func(ch rune) bool { return ch == 'à' }))
+	assert.Equal(t, -1, r.LastIndexFunc(unicode.IsUpper))
+	assert.Equal(t, -1, Empty().LastIndexFunc(unicode.IsDigit))
+}
+
+// TestCollapseWhitespace tests collapsing runs of whitespace
+func TestCollapseWhitespace(t *testing.T) {
+	r := New("  one\t\ttwo   three \r\n\n\t four ")
+
+	result, cs, err := r.CollapseWhitespace(false)
+	require.NoError(t, err)
+	assert.Equal(t, " one two three four ", result.String())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+
+	// Line breaks survive, the spaces around them do not
+	result, _, err = r.CollapseWhitespace(true)
+	require.NoError(t, err)
+	assert.Equal(t, " one two three\r\n\nfour ", result.String())
+}
+
+// TestCollapseWhitespace_Unchanged tests text that is already collapsed
+func TestCollapseWhitespace_Unchanged(t *testing.T) {
+	r := New("a b\nc")
+
+	result, _, err := r.CollapseWhitespace(true)
+	require.NoError(t, err)
+	assert.Same(t, r, result)
+
+	result, _, err = r.CollapseWhitespace(false)
+	require.NoError(t, err)
+	assert.Equal(t, "a b c", result.String())
+
+	result, _, err = Empty().CollapseWhitespace(false)
+	require.NoError(t, err)
+	assert.Equal(t, "", result.String())
+}
@@ -0,0 +1,533 @@
+package rope
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/clipperhouse/uax29/graphemes"
+)
+
+// Editing commands implement common editor keystrokes on top of the immutable
+// rope API. Each command returns the edited rope together with the ChangeSet
+// describing the edit, so callers can remap selections and record history.
+
+// InsertPair inserts an opening and closing character at pos, as editors do
+// when auto-closing brackets and quotes. Returns the new rope, the cursor
+// position between the two characters, and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("f")
+//	r2, cursor, _, _ := r.InsertPair(1, '(', ')')
+//	fmt.Println(r2.String(), cursor) // "f()" 2
+func (r *Rope) InsertPair(pos int, open, close rune) (*Rope, int, *ChangeSet, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, 0, nil, errInsertOutOfBounds(pos, r.Length())
+	}
+
+	result, cs, err := r.applyEdits([]EditOperation{
+		{From: pos, To: pos, Text: string([]rune{open, close})},
+	})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return result, pos + 1, cs, nil
+}
+
+// TypeOver replaces the selection with ch, as typing a character does when
+// text is selected; with an empty selection it simply inserts ch. Returns
+// the new rope, the collapsed cursor position after ch, and the ChangeSet of
+// the edit. Committed with a CoalescePolicy such as DefaultCoalescePolicy,
+// the characters typed after it join the same undo step.
+//
+// Example:
+//
+//	r := rope.New("say hello")
+//	r2, cursor, _, _ := r.TypeOver(rope.NewRange(4, 9), 'x')
+//	fmt.Println(r2.String(), cursor) // "say x" 5
+func (r *Rope) TypeOver(sel Range, ch rune) (*Rope, int, *ChangeSet, error) {
+	from, to := sel.From(), sel.To()
+	if from < 0 || to > r.Length() {
+		return nil, 0, nil, &ErrInvalidRange{
+			Operation: "TypeOver",
+			Start:     from,
+			End:       to,
+			ValidMax:  r.Length(),
+		}
+	}
+
+	result, cs, err := r.applyEdits([]EditOperation{{From: from, To: to, Text: string(ch)}})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return result, from + 1, cs, nil
+}
+
+// InsertLineBreak inserts a line break at pos, as pressing Enter does,
+// using the document's dominant line ending as reported by
+// DetectLineEnding, or "\n" if it has none yet. Returns the new rope, the
+// cursor position after the line break, and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("a\r\nbc")
+//	r2, cursor, _, _ := r.InsertLineBreak(4)
+//	fmt.Printf("%q %d\n", r2.String(), cursor) // "a\r\nb\r\nc" 6
+func (r *Rope) InsertLineBreak(pos int) (*Rope, int, *ChangeSet, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, 0, nil, errInsertOutOfBounds(pos, r.Length())
+	}
+
+	lineBreak := "\n"
+	switch r.DetectLineEnding() {
+	case "CRLF":
+		lineBreak = "\r\n"
+	case "CR":
+		lineBreak = "\r"
+	}
+
+	result, cs, err := r.applyEdits([]EditOperation{{From: pos, To: pos, Text: lineBreak}})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return result, pos + len(lineBreak), cs, nil
+}
+
+// SurroundRange wraps the characters in [start, end) with open and close,
+// e.g. to quote or parenthesize a selection.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("say hello")
+//	r2, _, _ := r.SurroundRange(4, 9, "\"", "\"")
+//	fmt.Println(r2.String()) // "say \"hello\""
+func (r *Rope) SurroundRange(start, end int, open, close string) (*Rope, *ChangeSet, error) {
+	if start < 0 || end > r.Length() || start > end {
+		return nil, nil, &ErrInvalidRange{
+			Operation: "SurroundRange",
+			Start:     start,
+			End:       end,
+			ValidMax:  r.Length(),
+		}
+	}
+
+	if start == end {
+		return r.applyEdits([]EditOperation{{From: start, To: start, Text: open + close}})
+	}
+	return r.applyEdits([]EditOperation{
+		{From: start, To: start, Text: open},
+		{From: end, To: end, Text: close},
+	})
+}
+
+// ReplaceRangeFunc replaces the characters in [start, end) with the result
+// of calling fn on them, e.g. to re-case or re-wrap a selection in one
+// undoable step. The replacement may be shorter or longer than the region.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("say hello")
+//	r2, _, _ := r.ReplaceRangeFunc(4, 9, strings.ToUpper)
+//	fmt.Println(r2.String()) // "say HELLO"
+func (r *Rope) ReplaceRangeFunc(start, end int, fn func(string) string) (*Rope, *ChangeSet, error) {
+	if start < 0 || end > r.Length() || start > end {
+		return nil, nil, &ErrInvalidRange{
+			Operation: "ReplaceRangeFunc",
+			Start:     start,
+			End:       end,
+			ValidMax:  r.Length(),
+		}
+	}
+
+	text, err := r.Slice(start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	replacement := fn(text)
+	if replacement == text {
+		return r.applyEdits(nil)
+	}
+	return r.applyEdits([]EditOperation{{From: start, To: end, Text: replacement}})
+}
+
+// RotateSelections rotates the text of the selected ranges among them, in
+// document order: with dir > 0 each range receives the text of the range
+// before it (the first receives the last's), with dir < 0 the text of the
+// range after it. dir == 0 is a no-op. Ranges must not overlap.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("a, bb, ccc")
+//	sel := rope.NewSelection(rope.NewRange(0, 1), rope.NewRange(3, 5), rope.NewRange(7, 10))
+//	r2, _, _ := r.RotateSelections(sel, 1)
+//	fmt.Println(r2.String()) // "ccc, a, bb"
+func (r *Rope) RotateSelections(s *Selection, dir int) (*Rope, *ChangeSet, error) {
+	ranges := make([]Range, s.Len())
+	copy(ranges, s.Iter())
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].From() < ranges[j].From() })
+
+	texts := make([]string, len(ranges))
+	for i, rng := range ranges {
+		if i > 0 && rng.From() < ranges[i-1].To() {
+			return nil, nil, &ErrInvalidInput{
+				Parameter: "s",
+				Value:     rng,
+				Reason:    "selection ranges overlap",
+			}
+		}
+		text, err := r.Slice(rng.From(), rng.To())
+		if err != nil {
+			return nil, nil, err
+		}
+		texts[i] = text
+	}
+
+	var edits []EditOperation
+	if dir != 0 && len(ranges) > 1 {
+		n := len(ranges)
+		for i, rng := range ranges {
+			src := i - 1
+			if dir < 0 {
+				src = i + 1
+			}
+			edits = append(edits, EditOperation{
+				From: rng.From(),
+				To:   rng.To(),
+				Text: texts[(src+n)%n],
+			})
+		}
+	}
+	return r.applyEdits(edits)
+}
+
+// BackspaceOptions configures Backspace.
+type BackspaceOptions struct {
+	// SmartIndent deletes back to the previous tab stop when the cursor is
+	// inside a line's leading whitespace.
+	SmartIndent bool
+
+	// TabWidth is the distance between tab stops. Zero means 4.
+	TabWidth int
+}
+
+// Backspace deletes the text before pos as the Backspace key does.
+// Normally the previous grapheme cluster is removed as a whole, so an emoji
+// sequence or a base character with combining marks disappears in one step.
+// At the start of a line the previous line's ending is removed, a CRLF as a
+// unit, joining the two lines.
+//
+// When opts.SmartIndent is set and the cursor is inside the line's leading
+// whitespace, whitespace is deleted back to the previous tab stop instead.
+//
+// Returns the new rope, the new cursor position and the ChangeSet of the edit.
+// Backspace at position 0 is a no-op.
+func (r *Rope) Backspace(pos int, opts BackspaceOptions) (*Rope, int, *ChangeSet, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, 0, nil, &ErrOutOfBounds{
+			Operation: "Backspace",
+			Position:  pos,
+			Min:       0,
+			Max:       r.Length() + 1,
+		}
+	}
+	if pos == 0 {
+		return r, 0, NewChangeSet(r.Length()), nil
+	}
+
+	start, err := r.backspaceStart(pos, opts)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	result, cs, err := r.applyEdits([]EditOperation{{From: start, To: pos}})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return result, start, cs, nil
+}
+
+// DeleteForward deletes the text after pos as the Delete key does, the
+// counterpart of Backspace. The next grapheme cluster is removed as a
+// whole; at the end of a line that is the line ending, a CRLF as a unit,
+// so the line is joined with the next one. The cursor stays at pos.
+//
+// Returns the new rope and the ChangeSet of the edit. DeleteForward at the
+// end of the document is a no-op.
+func (r *Rope) DeleteForward(pos int) (*Rope, *ChangeSet, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, nil, &ErrOutOfBounds{
+			Operation: "DeleteForward",
+			Position:  pos,
+			Min:       0,
+			Max:       r.Length() + 1,
+		}
+	}
+	if pos == r.Length() {
+		return r, NewChangeSet(r.Length()), nil
+	}
+
+	return r.applyEdits([]EditOperation{{From: pos, To: pos + r.nextGraphemeLen(pos)}})
+}
+
+// backspaceStart returns where a backspace at pos (> 0) starts deleting.
+func (r *Rope) backspaceStart(pos int, opts BackspaceOptions) (int, error) {
+	prev, err := r.CharAt(pos - 1)
+	if err != nil {
+		return 0, err
+	}
+	if prev == '\n' {
+		if pos >= 2 {
+			if ch, _ := r.CharAt(pos - 2); ch == '\r' {
+				return pos - 2, nil
+			}
+		}
+		return pos - 1, nil
+	}
+
+	// Only whitespace before the cursor can be indentation, so the line
+	// prefix is read only then
+	if opts.SmartIndent && (prev == ' ' || prev == '\t') {
+		lineStart := r.LineStart(r.LineAtChar(pos - 1))
+		before, err := r.Slice(lineStart, pos)
+		if err != nil {
+			return 0, err
+		}
+		if isIndentation(before) {
+			tabWidth := opts.TabWidth
+			if tabWidth <= 0 {
+				tabWidth = 4
+			}
+			return lineStart + smartBackspaceLen(before, tabWidth, tabWidth), nil
+		}
+	}
+
+	return pos - r.prevGraphemeLen(pos), nil
+}
+
+// graphemeWindow is the initial number of characters examined around a
+// position when locating the adjacent grapheme cluster.
+const graphemeWindow = 32
+
+// prevGraphemeLen returns the length in characters of the grapheme cluster
+// that ends at pos. A CRLF pair counts as a single cluster.
+func (r *Rope) prevGraphemeLen(pos int) int {
+	for window := graphemeWindow; ; window *= 2 {
+		start := pos - window
+		if start < 0 {
+			start = 0
+		}
+		text, err := r.Slice(start, pos)
+		if err != nil || text == "" {
+			return 0
+		}
+		clusters := graphemes.SegmentAllString(text)
+		// A single cluster may have been cut off by the window; widen it
+		if len(clusters) > 1 || start == 0 {
+			return utf8.RuneCountInString(clusters[len(clusters)-1])
+		}
+	}
+}
+
+// nextGraphemeLen returns the length in characters of the grapheme cluster
+// that starts at pos. A CRLF pair counts as a single cluster.
+func (r *Rope) nextGraphemeLen(pos int) int {
+	for window := graphemeWindow; ; window *= 2 {
+		end := pos + window
+		if end > r.Length() {
+			end = r.Length()
+		}
+		text, err := r.Slice(pos, end)
+		if err != nil || text == "" {
+			return 0
+		}
+		clusters := graphemes.SegmentAllString(text)
+		if len(clusters) > 1 || end == r.Length() {
+			return utf8.RuneCountInString(clusters[0])
+		}
+	}
+}
+
+// isIndentation reports whether s consists solely of spaces and tabs.
+func isIndentation(s string) bool {
+	for _, ch := range s {
+		if ch != ' ' && ch != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// smartBackspaceLen returns how many characters of the indentation remain
+// after deleting back to the previous multiple of stop, tabs in indent
+// expanding to tabWidth columns.
+func smartBackspaceLen(indent string, stop, tabWidth int) int {
+	col := indentWidth(indent, tabWidth)
+	target := ((col - 1) / stop) * stop
+
+	keep := len(indent)
+	for keep > 0 {
+		if indent[keep-1] == '\t' {
+			// A tab spans up to the stop on its own
+			return keep - 1
+		}
+		keep--
+		if indentWidth(indent[:keep], tabWidth) <= target {
+			break
+		}
+	}
+	return keep
+}
+
+// ========== Transpose ==========
+
+// TransposeChars swaps the grapheme clusters on either side of pos,
+// as Emacs' transpose-chars does: "ab|c" becomes "acb".
+// Transposing at the start or end of the document is a no-op.
+// Returns the new rope and the ChangeSet of the edit.
+func (r *Rope) TransposeChars(pos int) (*Rope, *ChangeSet, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, nil, &ErrOutOfBounds{
+			Operation: "TransposeChars",
+			Position:  pos,
+			Min:       0,
+			Max:       r.Length() + 1,
+		}
+	}
+	if pos == 0 || pos == r.Length() {
+		return r, NewChangeSet(r.Length()), nil
+	}
+
+	start := pos - r.prevGraphemeLen(pos)
+	end := pos + r.nextGraphemeLen(pos)
+
+	prev, err := r.Slice(start, pos)
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err := r.Slice(pos, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.applyEdits([]EditOperation{{From: start, To: end, Text: next + prev}})
+}
+
+// TransposeWords swaps the word at or before pos with the word after it,
+// keeping the text between them in place: "one |two" becomes "two one".
+// Words are runs of letters, digits and underscores.
+// If there is no word on either side the call is a no-op.
+// Returns the new rope and the ChangeSet of the edit.
+func (r *Rope) TransposeWords(pos int) (*Rope, *ChangeSet, error) {
+	if pos < 0 || pos > r.Length() {
+		return nil, nil, &ErrOutOfBounds{
+			Operation: "TransposeWords",
+			Position:  pos,
+			Min:       0,
+			Max:       r.Length() + 1,
+		}
+	}
+
+	wb := NewWordBoundary(r)
+
+	// Look for the words in a window around pos, widening it while a word
+	// or the gap before it reaches the window's edge
+	for margin := textObjectWindow; ; margin *= 2 {
+		w := r.textWindowAround(pos, pos, margin)
+		firstStart, firstEnd, secondStart, secondEnd := transposeWordSpans(wb, w.runes, pos-w.start)
+		if w.clipped(NewRange(firstStart, secondEnd)) {
+			continue
+		}
+		if firstStart == firstEnd || secondStart == secondEnd {
+			return r, NewChangeSet(r.Length()), nil
+		}
+
+		runes := w.runes
+		swapped := string(runes[secondStart:secondEnd]) +
+			string(runes[firstEnd:secondStart]) +
+			string(runes[firstStart:firstEnd])
+		return r.applyEdits([]EditOperation{{From: w.start + firstStart, To: w.start + secondEnd, Text: swapped}})
+	}
+}
+
+// transposeWordSpans returns the spans of the two words TransposeWords
+// swaps for a cursor at pos in runes. A missing word has an empty span.
+func transposeWordSpans(wb *WordBoundary, runes []rune, pos int) (firstStart, firstEnd, secondStart, secondEnd int) {
+	// Move to the end of the word containing pos, so that a cursor inside
+	// or right after a word transposes that word with the next one
+	for pos < len(runes) && pos > 0 && wb.IsWordChar(runes[pos]) && wb.IsWordChar(runes[pos-1]) {
+		pos++
+	}
+
+	// First word: the closest one ending at or before pos
+	firstEnd = pos
+	for firstEnd > 0 && !wb.IsWordChar(runes[firstEnd-1]) {
+		firstEnd--
+	}
+	firstStart = firstEnd
+	for firstStart > 0 && wb.IsWordChar(runes[firstStart-1]) {
+		firstStart--
+	}
+
+	// Second word: the closest one starting at or after pos
+	secondStart = pos
+	for secondStart < len(runes) && !wb.IsWordChar(runes[secondStart]) {
+		secondStart++
+	}
+	secondEnd = secondStart
+	for secondEnd < len(runes) && wb.IsWordChar(runes[secondEnd]) {
+		secondEnd++
+	}
+	return firstStart, firstEnd, secondStart, secondEnd
+}
+
+// TransposeLines swaps line lineNum with the line above it.
+// Line endings stay where they are, so CRLF documents keep their endings.
+// Transposing line 0 is a no-op.
+// Returns the new rope and the ChangeSet of the edit.
+func (r *Rope) TransposeLines(lineNum int) (*Rope, *ChangeSet, error) {
+	lineCount := r.LineCount()
+	if lineNum < 0 || lineNum >= lineCount {
+		return nil, nil, &ErrOutOfBounds{
+			Operation: "TransposeLines",
+			Position:  lineNum,
+			Min:       0,
+			Max:       lineCount,
+		}
+	}
+	if lineNum == 0 {
+		return r, NewChangeSet(r.Length()), nil
+	}
+
+	upper, err := r.Line(lineNum - 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	lower, err := r.Line(lineNum)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keep a CR of a CRLF ending with its position rather than its text
+	upper, upperCR := strings.CutSuffix(upper, "\r")
+	lower, lowerCR := strings.CutSuffix(lower, "\r")
+	sep := "\n"
+	if upperCR {
+		sep = "\r\n"
+	}
+	tail := ""
+	if lowerCR {
+		tail = "\r"
+	}
+
+	start := r.LineStart(lineNum - 1)
+	end, err := r.LineEnd(lineNum)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.applyEdits([]EditOperation{{From: start, To: end, Text: lower + sep + upper + tail}})
+}
@@ -0,0 +1,415 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInsertPair tests inserting a bracket pair with the cursor between
+func TestInsertPair(t *testing.T) {
+	r := New("call")
+
+	result, cursor, cs, err := r.InsertPair(4, '(', ')')
+	require.NoError(t, err)
+	assert.Equal(t, "call()", result.String())
+	assert.Equal(t, 5, cursor)
+
+	// Cursor sits between the pair
+	before, _ := result.Slice(0, cursor)
+	assert.Equal(t, "call(", before)
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, "call()", applied.String())
+
+	_, _, _, err = r.InsertPair(10, '(', ')')
+	assert.Error(t, err)
+}
+
+// TestInsertPair_Unicode tests pairs made of multi-byte runes
+func TestInsertPair_Unicode(t *testing.T) {
+	r := New("日本")
+
+	result, cursor, _, err := r.InsertPair(1, '「', '」')
+	require.NoError(t, err)
+	assert.Equal(t, "日「」本", result.String())
+	assert.Equal(t, 2, cursor)
+}
+
+// TestTypeOver tests typing over a selection and continuing to type
+func TestTypeOver(t *testing.T) {
+	h := NewHistory()
+	policy := NewDefaultCoalescePolicy()
+	r := New("say abc now")
+
+	// A backward selection is replaced all the same
+	doc, cursor, cs, err := r.TypeOver(NewRange(7, 4), 'x')
+	require.NoError(t, err)
+	assert.Equal(t, "say x now", doc.String())
+	assert.Equal(t, 5, cursor)
+	require.NoError(t, h.CommitRevisionCoalesced(NewTransaction(cs), r, policy))
+
+	for _, ch := range "yz" {
+		next, c, cs, err := doc.TypeOver(Point(cursor), ch)
+		require.NoError(t, err)
+		require.NoError(t, h.CommitRevisionCoalesced(NewTransaction(cs), doc, policy))
+		doc, cursor = next, c
+	}
+	assert.Equal(t, "say xyz now", doc.String())
+	assert.Equal(t, 7, cursor)
+
+	// The replacement and the typing after it undo together
+	assert.Equal(t, 1, h.Len())
+	assert.Equal(t, "say abc now", undo(t, h, doc).String())
+
+	_, _, _, err = r.TypeOver(NewRange(4, 20), 'x')
+	assert.Error(t, err)
+}
+
+// TestInsertLineBreak tests that Enter follows the document's line endings
+func TestInsertLineBreak(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		pos    int
+		want   string
+		cursor int
+	}{
+		{"CRLF document", "one\r\ntwo\r\n", 7, "one\r\ntw\r\no\r\n", 9},
+		{"LF document", "one\ntwo\n", 7, "one\ntwo\n\n", 8},
+		{"mostly CRLF", "a\r\nb\r\nc\nd", 0, "\r\na\r\nb\r\nc\nd", 2},
+		{"CR document", "a\rb", 3, "a\rb\r", 4},
+		{"no line endings", "abc", 1, "a\nbc", 2},
+		{"empty document", "", 0, "\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.text)
+			result, cursor, cs, err := r.InsertLineBreak(tt.pos)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.String())
+			assert.Equal(t, tt.cursor, cursor)
+
+			applied, err := cs.Apply(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, applied.String())
+		})
+	}
+
+	_, _, _, err := New("ab").InsertLineBreak(3)
+	assert.Error(t, err)
+}
+
+// TestSurroundRange tests wrapping a word in quotes
+func TestSurroundRange(t *testing.T) {
+	r := New("say hello world")
+
+	result, cs, err := r.SurroundRange(4, 9, "\"", "\"")
+	require.NoError(t, err)
+	assert.Equal(t, "say \"hello\" world", result.String())
+	assert.Equal(t, r.Length()+2, cs.LenAfter())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+
+	_, _, err = r.SurroundRange(9, 4, "(", ")")
+	assert.Error(t, err)
+}
+
+// TestSurroundRange_Empty tests surrounding an empty selection
+func TestSurroundRange_Empty(t *testing.T) {
+	r := New("ab")
+
+	result, _, err := r.SurroundRange(1, 1, "[", "]")
+	require.NoError(t, err)
+	assert.Equal(t, "a[]b", result.String())
+}
+
+// TestReplaceRangeFunc_Uppercase tests transforming a region in place
+func TestReplaceRangeFunc_Uppercase(t *testing.T) {
+	r := New("say héllo world")
+
+	result, cs, err := r.ReplaceRangeFunc(4, 9, strings.ToUpper)
+	require.NoError(t, err)
+	assert.Equal(t, "say HÉLLO world", result.String())
+	assert.Equal(t, r.Length(), cs.LenAfter())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+
+	// The changeset is undoable
+	inverse, err := cs.Invert(r)
+	require.NoError(t, err)
+	undone, err := inverse.Apply(result)
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), undone.String())
+}
+
+// TestReplaceRangeFunc_ChangesLength tests replacements shorter and longer than the region
+func TestReplaceRangeFunc_ChangesLength(t *testing.T) {
+	r := New("a [one two three] b")
+
+	shorter, cs, err := r.ReplaceRangeFunc(3, 16, func(s string) string {
+		return strings.Fields(s)[0]
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a [one] b", shorter.String())
+	assert.Equal(t, shorter.Length(), cs.LenAfter())
+
+	longer, cs, err := r.ReplaceRangeFunc(3, 16, func(s string) string {
+		return strings.Join(strings.Fields(s), ",\n  ")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a [one,\n  two,\n  three] b", longer.String())
+	assert.Equal(t, longer.Length(), cs.LenAfter())
+
+	// An unchanged region produces an identity changeset
+	same, cs, err := r.ReplaceRangeFunc(0, 1, func(s string) string { return s })
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), same.String())
+	assert.True(t, cs.Normalize().IsEmpty())
+
+	_, _, err = r.ReplaceRangeFunc(5, 100, strings.ToUpper)
+	var rangeErr *ErrInvalidRange
+	assert.ErrorAs(t, err, &rangeErr)
+}
+
+// TestBackspace_Grapheme tests that backspace removes a whole grapheme cluster
+func TestBackspace_Grapheme(t *testing.T) {
+	family := "👨‍👩‍👧"
+	r := New("hi " + family)
+
+	result, cursor, cs, err := r.Backspace(r.Length(), BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "hi ", result.String())
+	assert.Equal(t, 3, cursor)
+	assert.Equal(t, 3, cs.LenAfter())
+
+	// Base character plus combining mark
+	r = New("café!")
+	result, cursor, _, err = r.Backspace(5, BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "caf!", result.String())
+	assert.Equal(t, 3, cursor)
+}
+
+// TestBackspace_LineEndings tests joining lines across LF and CRLF
+func TestBackspace_LineEndings(t *testing.T) {
+	r := New("ab\ncd")
+	result, cursor, _, err := r.Backspace(3, BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", result.String())
+	assert.Equal(t, 2, cursor)
+
+	r = New("ab\r\ncd")
+	result, cursor, _, err = r.Backspace(4, BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", result.String())
+	assert.Equal(t, 2, cursor)
+}
+
+// TestBackspace_SmartIndent tests deleting back to the previous tab stop
+func TestBackspace_SmartIndent(t *testing.T) {
+	r := New("x\n        foo")
+	opts := BackspaceOptions{SmartIndent: true, TabWidth: 4}
+
+	// Cursor at column 8 removes four spaces
+	result, cursor, _, err := r.Backspace(10, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "x\n    foo", result.String())
+	assert.Equal(t, 6, cursor)
+
+	// Off a tab stop, only the spaces back to the stop are removed
+	r = New("      foo")
+	result, cursor, _, err = r.Backspace(6, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "    foo", result.String())
+	assert.Equal(t, 4, cursor)
+
+	// A tab is removed on its own
+	r = New("\t\tfoo")
+	result, cursor, _, err = r.Backspace(2, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "\tfoo", result.String())
+	assert.Equal(t, 1, cursor)
+
+	// Without SmartIndent a single space is removed
+	r = New("        foo")
+	result, _, _, err = r.Backspace(8, BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "       foo", result.String())
+
+	// Outside indentation a single character is removed
+	r = New("    foo")
+	result, _, _, err = r.Backspace(7, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "    fo", result.String())
+}
+
+// TestBackspace_Boundaries tests backspace at the document start and out of range
+func TestBackspace_Boundaries(t *testing.T) {
+	r := New("abc")
+
+	result, cursor, cs, err := r.Backspace(0, BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", result.String())
+	assert.Equal(t, 0, cursor)
+	assert.Equal(t, 3, cs.LenAfter())
+
+	_, _, _, err = r.Backspace(4, BackspaceOptions{})
+	assert.Error(t, err)
+}
+
+// TestDeleteForward_LineEndings tests joining lines by deleting forward at the line end
+func TestDeleteForward_LineEndings(t *testing.T) {
+	r := New("ab\ncd")
+	result, cs, err := r.DeleteForward(2)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", result.String())
+	assert.Equal(t, 2, cs.MapPosition(2, AssocBefore))
+
+	r = New("ab\r\ncd")
+	result, _, err = r.DeleteForward(2)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", result.String())
+
+	// Backspace at the start of the next line joins the same lines
+	result, cursor, _, err := r.Backspace(4, BackspaceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", result.String())
+	assert.Equal(t, 2, cursor)
+}
+
+// TestDeleteForward tests deleting graphemes and the document boundaries
+func TestDeleteForward(t *testing.T) {
+	r := New("a\U0001F44D\U0001F3FDb") // Thumbs up with skin tone modifier
+	result, _, err := r.DeleteForward(1)
+	require.NoError(t, err)
+	assert.Equal(t, "ab", result.String())
+
+	r = New("cafe\u0301!")
+	result, _, err = r.DeleteForward(3)
+	require.NoError(t, err)
+	assert.Equal(t, "caf!", result.String())
+
+	result, cs, err := r.DeleteForward(r.Length())
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), result.String())
+	assert.Equal(t, r.Length(), cs.LenAfter())
+
+	_, _, err = r.DeleteForward(-1)
+	assert.Error(t, err)
+	_, _, err = r.DeleteForward(r.Length() + 1)
+	assert.Error(t, err)
+}
+
+// TestTransposeChars tests swapping the characters around the cursor
+func TestTransposeChars(t *testing.T) {
+	r := New("abc")
+
+	result, cs, err := r.TransposeChars(2)
+	require.NoError(t, err)
+	assert.Equal(t, "acb", result.String())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, "acb", applied.String())
+
+	// Grapheme clusters move as a unit
+	r = New("éx")
+	result, _, err = r.TransposeChars(2)
+	require.NoError(t, err)
+	assert.Equal(t, "xé", result.String())
+}
+
+// TestTransposeChars_Boundaries tests that transposing at the ends is a no-op
+func TestTransposeChars_Boundaries(t *testing.T) {
+	r := New("abc")
+
+	result, cs, err := r.TransposeChars(0)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", result.String())
+	assert.Equal(t, 3, cs.LenAfter())
+
+	result, _, err = r.TransposeChars(3)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", result.String())
+
+	_, _, err = r.TransposeChars(4)
+	assert.Error(t, err)
+}
+
+// TestTransposeWords tests swapping two words across whitespace
+func TestTransposeWords(t *testing.T) {
+	r := New("hello   world!")
+
+	result, cs, err := r.TransposeWords(8)
+	require.NoError(t, err)
+	assert.Equal(t, "world   hello!", result.String())
+	assert.Equal(t, r.Length(), cs.LenAfter())
+
+	// Cursor right after the first word
+	result, _, err = r.TransposeWords(5)
+	require.NoError(t, err)
+	assert.Equal(t, "world   hello!", result.String())
+
+	// Punctuation between words is kept in place
+	r = New("a, b")
+	result, _, err = r.TransposeWords(1)
+	require.NoError(t, err)
+	assert.Equal(t, "b, a", result.String())
+
+	// No word after the cursor
+	r = New("only")
+	result, _, err = r.TransposeWords(4)
+	require.NoError(t, err)
+	assert.Equal(t, "only", result.String())
+
+	// Words far apart in a large document
+	gap := strings.Repeat(" ", 300) + strings.Repeat("\n", 300)
+	text := strings.Repeat("lead ", 1000) + "alpha" + gap + "omega" + strings.Repeat(" tail", 1000)
+	r = chunkedRope(text, 64)
+	result, _, err = r.TransposeWords(strings.Index(text, "alpha") + 2)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(text, "alpha"+gap+"omega", "omega"+gap+"alpha", 1), result.String())
+}
+
+// TestTransposeLines tests swapping a line with the previous one
+func TestTransposeLines(t *testing.T) {
+	r := New("one\ntwo\nthree")
+
+	result, cs, err := r.TransposeLines(2)
+	require.NoError(t, err)
+	assert.Equal(t, "one\nthree\ntwo", result.String())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+
+	result, _, err = r.TransposeLines(1)
+	require.NoError(t, err)
+	assert.Equal(t, "two\none\nthree", result.String())
+
+	// Line 0 has nothing above it
+	result, _, err = r.TransposeLines(0)
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\nthree", result.String())
+
+	_, _, err = r.TransposeLines(3)
+	assert.Error(t, err)
+}
+
+// TestTransposeLines_CRLF tests that CRLF endings stay in place
+func TestTransposeLines_CRLF(t *testing.T) {
+	r := New("one\r\ntwo")
+
+	result, _, err := r.TransposeLines(1)
+	require.NoError(t, err)
+	assert.Equal(t, "two\r\none", result.String())
+}
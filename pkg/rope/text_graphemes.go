@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/clipperhouse/uax29/graphemes"
@@ -24,9 +25,26 @@ type Grapheme struct {
 	CharLen  int    // Length in characters (code points)
 }
 
+// GraphemeMode selects the Unicode UAX #29 rules used to find grapheme
+// cluster boundaries.
+type GraphemeMode int
+
+const (
+	// GraphemeExtended uses extended grapheme clusters, which keep emoji ZWJ
+	// sequences, flags, spacing marks and prepended characters together.
+	// This is the default.
+	GraphemeExtended GraphemeMode = iota
+
+	// GraphemeLegacy uses legacy grapheme clusters, which additionally break
+	// before spacing marks and after prepended characters, as older
+	// software does.
+	GraphemeLegacy
+)
+
 // GraphemeIterator iterates over grapheme clusters in a rope.
 type GraphemeIterator struct {
 	rope      *Rope
+	mode      GraphemeMode
 	graphemes []Grapheme
 	index     int
 	exhausted bool
@@ -45,15 +63,8 @@ func (r *Rope) Graphemes() *GraphemeIterator {
 	graphemes := make([]Grapheme, len(segments))
 	charPos := 0
 	for i, seg := range segments {
-		byteLen := len(seg)
-		charLen := utf8.RuneCountInString(seg)
-		graphemes[i] = Grapheme{
-			Text:     seg,
-			StartPos: charPos,
-			byteLen:  byteLen,
-			CharLen:  charLen,
-		}
-		charPos += charLen
+		graphemes[i] = newGrapheme(seg, charPos)
+		charPos += graphemes[i].CharLen
 	}
 
 	return &GraphemeIterator{
@@ -64,6 +75,93 @@ func (r *Rope) Graphemes() *GraphemeIterator {
 	}
 }
 
+// GraphemesWithMode returns an iterator over the grapheme clusters of the
+// rope using the given rules. Graphemes is the same as
+// GraphemesWithMode(GraphemeExtended).
+//
+// Example:
+//
+//	it := rope.New("\u0915\u093F").GraphemesWithMode(rope.GraphemeLegacy)
+//	fmt.Println(len(it.Collect())) // 2: the vowel sign is a spacing mark
+func (r *Rope) GraphemesWithMode(mode GraphemeMode) *GraphemeIterator {
+	it := r.Graphemes()
+	it.mode = mode
+	if mode != GraphemeLegacy {
+		return it
+	}
+
+	var legacy []Grapheme
+	for _, g := range it.graphemes {
+		legacy = appendLegacyGraphemes(legacy, g)
+	}
+	it.graphemes = legacy
+	return it
+}
+
+// appendLegacyGraphemes splits an extended grapheme cluster where the legacy
+// rules break, before a spacing mark and after a prepended character, and
+// appends the pieces to dst.
+func appendLegacyGraphemes(dst []Grapheme, g Grapheme) []Grapheme {
+	if g.byteLen == g.CharLen {
+		return append(dst, g) // ASCII clusters are the same in both modes
+	}
+
+	start, startPos, pos := 0, g.StartPos, g.StartPos
+	var prev rune
+	for i, ch := range g.Text {
+		if i > 0 && (isSpacingMark(ch) || isPrepend(prev)) {
+			dst = append(dst, newGrapheme(g.Text[start:i], startPos))
+			start, startPos = i, pos
+		}
+		prev = ch
+		pos++
+	}
+	return append(dst, newGrapheme(g.Text[start:], startPos))
+}
+
+// newGrapheme creates a Grapheme for text starting at character pos.
+func newGrapheme(text string, pos int) Grapheme {
+	return Grapheme{
+		Text:     text,
+		StartPos: pos,
+		byteLen:  len(text),
+		CharLen:  utf8.RuneCountInString(text),
+	}
+}
+
+// isSpacingMark approximates Grapheme_Cluster_Break=SpacingMark: spacing
+// combining marks, except those that UAX #29 lists as Extend, plus the Thai
+// and Lao SARA AM.
+func isSpacingMark(ch rune) bool {
+	switch ch {
+	case '\u0E33', '\u0EB3':
+		return true
+	case '\u102B', '\u102C', '\u1038', '\u1062', '\u1063', '\u1064',
+		'\u1067', '\u1068', '\u1069', '\u106A', '\u106B', '\u106C',
+		'\u106D', '\u1083', '\u1087', '\u1088', '\u1089', '\u108A',
+		'\u108B', '\u108C', '\u108F', '\u109A', '\u109B', '\u109C',
+		'\u1A61', '\u1A63', '\u1A64', '\uAA7B', '\uAA7D':
+		return false
+	}
+	return unicode.In(ch, unicode.Mc)
+}
+
+// isPrepend reports whether ch has Grapheme_Cluster_Break=Prepend, which
+// covers the prepended concatenation marks and a few preceding repha forms.
+func isPrepend(ch rune) bool {
+	switch {
+	case ch >= '\u0600' && ch <= '\u0605',
+		ch == '\u06DD', ch == '\u070F', ch == '\u0890', ch == '\u0891',
+		ch == '\u08E2', ch == '\u0D4E', ch == '\U000110BD', ch == '\U000110CD',
+		ch == '\U000111C2', ch == '\U000111C3', ch == '\U0001193F',
+		ch == '\U00011941', ch == '\U00011A3A', ch == '\U00011D46',
+		ch == '\U00011F02',
+		ch >= '\U00011A84' && ch <= '\U00011A89':
+		return true
+	}
+	return false
+}
+
 // Next advances to the next grapheme cluster and returns true if there are more.
 func (it *GraphemeIterator) Next() bool {
 	if it.exhausted {
@@ -103,7 +201,7 @@ func (it *GraphemeIterator) Reset() {
 		return
 	}
 
-	newIt := it.rope.Graphemes()
+	newIt := it.rope.GraphemesWithMode(it.mode)
 	it.graphemes = newIt.graphemes
 	it.index = -1
 	it.exhausted = len(it.graphemes) == 0
@@ -134,17 +232,39 @@ func (it *GraphemeIterator) HasNext() bool {
 }
 
 // LenGraphemes returns the total number of grapheme clusters in the rope.
-// This is O(n) where n is the byte length of the rope.
+// It is the same as GraphemeLength.
 func (r *Rope) LenGraphemes() int {
+	return r.GraphemeLength()
+}
+
+// GraphemeLength returns the number of user-perceived characters (grapheme
+// clusters) in the rope, which is at most Length(). Counting is O(n) on the
+// first call; the count is then remembered by the rope, while edited ropes
+// count their own.
+//
+// Example:
+//
+//	r := rope.New("e\u0301!")
+//	fmt.Println(r.Length(), r.GraphemeLength()) // 3 2
+func (r *Rope) GraphemeLength() int {
 	if r == nil || r.Length() == 0 {
 		return 0
 	}
 
-	count := 0
-	it := r.Graphemes()
-	for it.Next() {
-		count++
+	caches := r.lazyCaches()
+	caches.mu.Lock()
+	count, ok := caches.graphemes, caches.hasGraphemes
+	caches.mu.Unlock()
+	if ok {
+		return count
 	}
+
+	it := r.Graphemes()
+	count = len(it.graphemes)
+
+	caches.mu.Lock()
+	caches.graphemes, caches.hasGraphemes = count, true
+	caches.mu.Unlock()
 	return count
 }
 
@@ -163,6 +283,23 @@ func (r *Rope) GraphemeAt(charIdx int) Grapheme {
 	return it.Current()
 }
 
+// GraphemeText returns the text of the grapheme cluster with the given
+// index, counting in grapheme clusters rather than characters. Unlike
+// GraphemeAt, it returns an error instead of panicking when index is out of
+// bounds.
+func (r *Rope) GraphemeText(index int) (string, error) {
+	it := r.Graphemes()
+	if index < 0 || index >= len(it.graphemes) {
+		return "", &ErrOutOfBounds{
+			Operation: "GraphemeText",
+			Position:  index,
+			Min:       0,
+			Max:       len(it.graphemes),
+		}
+	}
+	return it.graphemes[index].Text, nil
+}
+
 // PrevGraphemeStart returns the character position of the start
 // of the grapheme cluster containing the given position.
 // Panics if position is out of bounds.
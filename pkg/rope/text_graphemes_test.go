@@ -152,6 +152,78 @@ func TestGrapheme_AtCombining(t *testing.T) {
 	assert.Equal(t, 2, g1.CharLen) // 2 runes
 }
 
+// TestGrapheme_Length tests that emoji clusters count as one grapheme each
+func TestGrapheme_Length(t *testing.T) {
+	// Family (ZWJ sequence), flag (regional indicators), thumbs up with skin tone
+	r := New("👨\u200d👩\u200d👧🇯🇵👍🏽")
+
+	assert.Greater(t, r.Length(), 3)
+	assert.Equal(t, 3, r.GraphemeLength())
+	assert.Equal(t, 3, r.GraphemeLength()) // Cached
+	assert.Equal(t, r.GraphemeLength(), r.LenGraphemes())
+
+	text, err := r.GraphemeText(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "🇯🇵", text)
+	text, err = r.GraphemeText(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "👍🏽", text)
+
+	_, err = r.GraphemeText(3)
+	assert.Error(t, err)
+	_, err = r.GraphemeText(-1)
+	assert.Error(t, err)
+
+	// An edited rope counts its own graphemes
+	r2, err := r.Insert(r.Length(), "ab")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, r2.GraphemeLength())
+	assert.Equal(t, 3, r.GraphemeLength())
+
+	var nilRope *Rope
+	assert.Equal(t, 0, nilRope.GraphemeLength())
+	_, err = nilRope.GraphemeText(0)
+	assert.Error(t, err)
+}
+
+// graphemeTexts collects the text of every grapheme of it.
+func graphemeTexts(it *GraphemeIterator) []string {
+	var texts []string
+	for it.Next() {
+		texts = append(texts, it.Current().Text)
+	}
+	return texts
+}
+
+// TestGrapheme_Modes tests extended and legacy cluster boundaries
+func TestGrapheme_Modes(t *testing.T) {
+	flags := New("🇺🇸🇬🇧")
+	family := New("👨\u200d👩\u200d👧\u200d👦")
+	for _, mode := range []GraphemeMode{GraphemeExtended, GraphemeLegacy} {
+		assert.Equal(t, []string{"🇺🇸", "🇬🇧"}, graphemeTexts(flags.GraphemesWithMode(mode)))
+		assert.Equal(t, []string{family.String()}, graphemeTexts(family.GraphemesWithMode(mode)))
+	}
+	assert.Equal(t, graphemeTexts(flags.Graphemes()), graphemeTexts(flags.GraphemesWithMode(GraphemeExtended)))
+
+	// KA + vowel sign I (a spacing mark), then an Arabic number sign (prepend)
+	r := New("a\u0915\u093Fb\u06001")
+	assert.Equal(t, []string{"a", "\u0915\u093F", "b", "\u06001"},
+		graphemeTexts(r.GraphemesWithMode(GraphemeExtended)))
+
+	it := r.GraphemesWithMode(GraphemeLegacy)
+	legacy := it.Collect()
+	var texts []string
+	for _, g := range legacy {
+		texts = append(texts, g.Text)
+		assert.Equal(t, g.Text, sliceRange(r, NewRange(g.StartPos, g.StartPos+g.CharLen)))
+	}
+	assert.Equal(t, []string{"a", "\u0915", "\u093F", "b", "\u0600", "1"}, texts)
+
+	// Reset keeps the mode
+	it.Reset()
+	assert.Len(t, it.Collect(), 6)
+}
+
 // ========== GraphemeSlice Tests ==========
 
 func TestGrapheme_Slice(t *testing.T) {
@@ -0,0 +1,334 @@
+package rope
+
+import (
+	"strings"
+)
+
+// Indentation operations convert and adjust the leading whitespace of lines.
+// Only the indentation at the start of each line is touched; tabs and spaces
+// inside the rest of the line (code, strings, alignment) are left alone.
+
+// lineIndent describes the leading whitespace of a single line.
+type lineIndent struct {
+	start int    // Character position of the line start
+	text  string // Leading spaces and tabs
+	blank bool   // The line contains only whitespace
+}
+
+// lineIndents collects the leading indentation of every line in a single pass.
+func (r *Rope) lineIndents() []lineIndent {
+	return r.lineIndentsIn(0, r.Length())
+}
+
+// lineIndentsIn collects the leading indentation of the lines in the
+// character range [from, to), where from is the start of a line.
+func (r *Rope) lineIndentsIn(from, to int) []lineIndent {
+	if r == nil || from >= to {
+		return nil
+	}
+
+	var indents []lineIndent
+	var indent strings.Builder
+	cur := lineIndent{start: from, blank: true}
+	inIndent := true
+	pos := from
+
+	it := r.IteratorAt(from)
+	for pos < to && it.Next() {
+		ch := it.Current()
+		switch {
+		case ch == '\n':
+			cur.text = indent.String()
+			indents = append(indents, cur)
+			indent.Reset()
+			cur = lineIndent{start: pos + 1, blank: true}
+			inIndent = true
+		case inIndent && (ch == ' ' || ch == '\t'):
+			indent.WriteRune(ch)
+		case ch == '\r':
+			inIndent = false
+		default:
+			inIndent = false
+			cur.blank = false
+		}
+		pos++
+	}
+
+	// The last line only counts if it has content (matches LineCount)
+	if pos > cur.start {
+		cur.text = indent.String()
+		indents = append(indents, cur)
+	}
+
+	return indents
+}
+
+// indentWidth returns the visual width of indentation text, expanding tabs
+// to the next multiple of tabWidth.
+func indentWidth(indent string, tabWidth int) int {
+	col := 0
+	for _, ch := range indent {
+		if ch == '\t' {
+			col += tabWidth - col%tabWidth
+		} else {
+			col++
+		}
+	}
+	return col
+}
+
+func errInvalidTabWidth(tabWidth int) error {
+	return &ErrInvalidInput{
+		Parameter: "tabWidth",
+		Value:     tabWidth,
+		Reason:    "must be positive",
+	}
+}
+
+// TabsToSpaces expands tabs in the leading indentation of every line to spaces.
+// Tab expansion respects tab stops, so a tab after two spaces expands to
+// tabWidth-2 spaces. Returns the new rope and the ChangeSet describing the edit.
+//
+// Example:
+//
+//	r := rope.New("\tfoo\n  \tbar")
+//	r2, cs, _ := r.TabsToSpaces(4)
+//	fmt.Println(r2.String()) // "    foo\n    bar"
+func (r *Rope) TabsToSpaces(tabWidth int) (*Rope, *ChangeSet, error) {
+	if tabWidth <= 0 {
+		return nil, nil, errInvalidTabWidth(tabWidth)
+	}
+
+	var edits []EditOperation
+	for _, li := range r.lineIndents() {
+		if !strings.ContainsRune(li.text, '\t') {
+			continue
+		}
+		edits = append(edits, EditOperation{
+			From: li.start,
+			To:   li.start + len(li.text),
+			Text: strings.Repeat(" ", indentWidth(li.text, tabWidth)),
+		})
+	}
+
+	return r.applyEdits(edits)
+}
+
+// SpacesToTabs converts the leading indentation of every line to tabs.
+// The indentation's visual width is preserved: it is rewritten as tabs for
+// each full tab stop followed by spaces for any remainder.
+// Returns the new rope and the ChangeSet describing the edit.
+//
+// Example:
+//
+//	r := rope.New("    foo\n      bar")
+//	r2, cs, _ := r.SpacesToTabs(4)
+//	fmt.Println(r2.String()) // "\tfoo\n\t  bar"
+func (r *Rope) SpacesToTabs(tabWidth int) (*Rope, *ChangeSet, error) {
+	if tabWidth <= 0 {
+		return nil, nil, errInvalidTabWidth(tabWidth)
+	}
+
+	var edits []EditOperation
+	for _, li := range r.lineIndents() {
+		width := indentWidth(li.text, tabWidth)
+		converted := strings.Repeat("\t", width/tabWidth) + strings.Repeat(" ", width%tabWidth)
+		if converted == li.text {
+			continue
+		}
+		edits = append(edits, EditOperation{
+			From: li.start,
+			To:   li.start + len(li.text),
+			Text: converted,
+		})
+	}
+
+	return r.applyEdits(edits)
+}
+
+// lineRangeIndents returns the indentation of lines startLine..endLine
+// (inclusive), or an error if the range is invalid. Like lineIndents it
+// splits lines at '\n', and only the lines in the range are scanned: their
+// bounds come from the cached line counts.
+func (r *Rope) lineRangeIndents(operation string, startLine, endLine int) ([]lineIndent, error) {
+	breaks, lineCount := 0, 0
+	if r.Length() > 0 {
+		breaks = nodeLineBreaks(r.root)
+		lineCount = breaks
+		if last, _ := r.CharAt(r.Length() - 1); last != '\n' {
+			lineCount++
+		}
+	}
+	if startLine < 0 || endLine >= lineCount || startLine > endLine {
+		return nil, &ErrInvalidRange{
+			Operation: operation,
+			Start:     startLine,
+			End:       endLine,
+			ValidMax:  lineCount,
+		}
+	}
+
+	from, to := 0, r.Length()
+	if startLine > 0 {
+		from = lineBreakPos(r.root, startLine) + 1
+	}
+	if endLine < breaks {
+		to = lineBreakPos(r.root, endLine+1) + 1
+	}
+	return r.lineIndentsIn(from, to), nil
+}
+
+// ToggleLineComment comments or uncomments lines startLine..endLine (inclusive).
+//
+// If every non-blank line in the range already starts with prefix after its
+// indentation, the prefix is removed from each of them. Otherwise prefix is
+// inserted at the first non-whitespace column of every non-blank line, which
+// preserves the lines' relative indentation. Blank lines are never changed.
+//
+// When uncommenting, a prefix without its trailing spaces is also recognized,
+// so "//x" is uncommented by the prefix "// ".
+// Returns the new rope and the ChangeSet describing the edit.
+//
+// Example:
+//
+//	r := rope.New("if x {\n    y()\n}")
+//	r2, _, _ := r.ToggleLineComment(0, 2, "// ")
+//	fmt.Println(r2.String()) // "// if x {\n    // y()\n// }"
+func (r *Rope) ToggleLineComment(startLine, endLine int, prefix string) (*Rope, *ChangeSet, error) {
+	if prefix == "" {
+		return nil, nil, &ErrInvalidInput{
+			Parameter: "prefix",
+			Value:     prefix,
+			Reason:    "must not be empty",
+		}
+	}
+
+	lines, err := r.lineRangeIndents("ToggleLineComment", startLine, endLine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trimmed := strings.TrimRight(prefix, " \t")
+	prefixLen := len([]rune(prefix))
+	trimmedLen := len([]rune(trimmed))
+
+	// commentLen returns the length of the comment marker on a line, or 0
+	commentLen := func(li lineIndent) int {
+		pos := li.start + len(li.text)
+		if head, err := r.Slice(pos, min(pos+prefixLen, r.Length())); err == nil && head == prefix {
+			return prefixLen
+		}
+		if head, err := r.Slice(pos, min(pos+trimmedLen, r.Length())); err == nil && head == trimmed && trimmed != "" {
+			return trimmedLen
+		}
+		return 0
+	}
+
+	uncomment := false
+	for _, li := range lines {
+		if li.blank {
+			continue
+		}
+		if commentLen(li) == 0 {
+			uncomment = false
+			break
+		}
+		uncomment = true
+	}
+
+	var edits []EditOperation
+	for _, li := range lines {
+		if li.blank {
+			continue
+		}
+		pos := li.start + len(li.text)
+		if uncomment {
+			edits = append(edits, EditOperation{From: pos, To: pos + commentLen(li)})
+		} else {
+			edits = append(edits, EditOperation{From: pos, To: pos, Text: prefix})
+		}
+	}
+
+	return r.applyEdits(edits)
+}
+
+// checkIndentUnit returns an error unless unit is a non-empty run of
+// spaces and tabs.
+func checkIndentUnit(unit string) error {
+	if unit == "" || !isIndentation(unit) {
+		return &ErrInvalidInput{
+			Parameter: "unit",
+			Value:     unit,
+			Reason:    "must be non-empty spaces or tabs",
+		}
+	}
+	return nil
+}
+
+// IndentRange adds one level of indentation, unit, to the start of lines
+// startLine..endLine (inclusive), as pressing Tab on a multi-line selection
+// does. Blank lines are left alone so no trailing whitespace is created.
+// Returns the new rope and the ChangeSet describing the edit, so selections
+// can be remapped.
+//
+// Example:
+//
+//	r := rope.New("a\nb\nc")
+//	r2, _, _ := r.IndentRange(1, 2, "    ")
+//	fmt.Println(r2.String()) // "a\n    b\n    c"
+func (r *Rope) IndentRange(startLine, endLine int, unit string) (*Rope, *ChangeSet, error) {
+	if err := checkIndentUnit(unit); err != nil {
+		return nil, nil, err
+	}
+
+	lines, err := r.lineRangeIndents("IndentRange", startLine, endLine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var edits []EditOperation
+	for _, li := range lines {
+		if !li.blank {
+			edits = append(edits, EditOperation{From: li.start, To: li.start, Text: unit})
+		}
+	}
+	return r.applyEdits(edits)
+}
+
+// DedentRange removes up to one level of indentation from lines
+// startLine..endLine (inclusive), as Shift+Tab does. The level's width is
+// the visual width of unit, tabs expanding to tabWidth columns, and each
+// line's indentation moves back to the previous multiple of it: with a
+// unit of four spaces, six leading spaces become four. A leading tab is
+// always removed as a whole level.
+// Returns the new rope and the ChangeSet describing the edit.
+//
+// Example:
+//
+//	r := rope.New("    a\n      b\n\tc")
+//	r2, _, _ := r.DedentRange(0, 2, "    ", 4)
+//	fmt.Println(r2.String()) // "a\n    b\nc"
+func (r *Rope) DedentRange(startLine, endLine int, unit string, tabWidth int) (*Rope, *ChangeSet, error) {
+	if err := checkIndentUnit(unit); err != nil {
+		return nil, nil, err
+	}
+	if tabWidth <= 0 {
+		return nil, nil, errInvalidTabWidth(tabWidth)
+	}
+	width := indentWidth(unit, tabWidth)
+
+	lines, err := r.lineRangeIndents("DedentRange", startLine, endLine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var edits []EditOperation
+	for _, li := range lines {
+		if li.text == "" {
+			continue
+		}
+		keep := smartBackspaceLen(li.text, width, tabWidth)
+		edits = append(edits, EditOperation{From: li.start + keep, To: li.start + len(li.text)})
+	}
+	return r.applyEdits(edits)
+}
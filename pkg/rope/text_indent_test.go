@@ -0,0 +1,210 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTabsToSpaces_MixedIndentation tests expanding mixed leading indentation
+func TestTabsToSpaces_MixedIndentation(t *testing.T) {
+	r := New("\tfoo\n  \tbar\tbaz\n    qux\n\t\t\"a\tb\"")
+
+	result, cs, err := r.TabsToSpaces(4)
+	require.NoError(t, err)
+	assert.Equal(t, "    foo\n    bar\tbaz\n    qux\n        \"a\tb\"", result.String())
+
+	// The changeset reproduces the same edit
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+	assert.Equal(t, result.Length(), cs.LenAfter())
+}
+
+// TestTabsToSpaces_TabStops tests that tabs expand to the next tab stop
+func TestTabsToSpaces_TabStops(t *testing.T) {
+	r := New("  \tx")
+	result, _, err := r.TabsToSpaces(8)
+	require.NoError(t, err)
+	assert.Equal(t, "        x", result.String())
+}
+
+// TestSpacesToTabs_MixedIndentation tests converting leading spaces to tabs
+func TestSpacesToTabs_MixedIndentation(t *testing.T) {
+	r := New("    foo\n      bar  baz\n \tqux\nnone")
+
+	result, cs, err := r.SpacesToTabs(4)
+	require.NoError(t, err)
+	assert.Equal(t, "\tfoo\n\t  bar  baz\n\tqux\nnone", result.String())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, result.String(), applied.String())
+}
+
+// TestIndentConversion_Idempotent tests that running a conversion twice is a no-op
+func TestIndentConversion_Idempotent(t *testing.T) {
+	r := New("\t  a\n    b\n  \t c\n\n")
+
+	spaces, _, err := r.TabsToSpaces(4)
+	require.NoError(t, err)
+	again, cs, err := spaces.TabsToSpaces(4)
+	require.NoError(t, err)
+	assert.Equal(t, spaces.String(), again.String())
+	assert.Equal(t, cs.LenBefore(), cs.LenAfter())
+
+	tabs, _, err := r.SpacesToTabs(4)
+	require.NoError(t, err)
+	again, cs, err = tabs.SpacesToTabs(4)
+	require.NoError(t, err)
+	assert.Equal(t, tabs.String(), again.String())
+	assert.Equal(t, cs.LenBefore(), cs.LenAfter())
+
+	// Converting back and forth preserves the spaces form
+	roundTrip, _, err := tabs.TabsToSpaces(4)
+	require.NoError(t, err)
+	assert.Equal(t, spaces.String(), roundTrip.String())
+}
+
+// TestIndentConversion_InvalidTabWidth tests rejecting non-positive tab widths
+func TestIndentConversion_InvalidTabWidth(t *testing.T) {
+	r := New("\tx")
+
+	_, _, err := r.TabsToSpaces(0)
+	var inputErr *ErrInvalidInput
+	assert.ErrorAs(t, err, &inputErr)
+
+	_, _, err = r.SpacesToTabs(-1)
+	assert.ErrorAs(t, err, &inputErr)
+}
+
+// TestToggleLineComment_MixedIndent tests commenting a mixed-indent block and back
+func TestToggleLineComment_MixedIndent(t *testing.T) {
+	original := "func f() {\n\tif x {\n        y()\n\n\t}\n}\n"
+	r := New(original)
+
+	commented, cs, err := r.ToggleLineComment(1, 4, "// ")
+	require.NoError(t, err)
+	assert.Equal(t, "func f() {\n\t// if x {\n        // y()\n\n\t// }\n}\n", commented.String())
+
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, commented.String(), applied.String())
+
+	// Toggling again restores the exact original
+	restored, _, err := commented.ToggleLineComment(1, 4, "// ")
+	require.NoError(t, err)
+	assert.Equal(t, original, restored.String())
+}
+
+// TestToggleLineComment_PartiallyCommented tests that a partly commented range gets commented
+func TestToggleLineComment_PartiallyCommented(t *testing.T) {
+	r := New("// a\nb")
+
+	result, _, err := r.ToggleLineComment(0, 1, "// ")
+	require.NoError(t, err)
+	assert.Equal(t, "// // a\n// b", result.String())
+}
+
+// TestToggleLineComment_TrimmedPrefix tests uncommenting markers without the trailing space
+func TestToggleLineComment_TrimmedPrefix(t *testing.T) {
+	r := New("  //a\n  // b")
+
+	result, _, err := r.ToggleLineComment(0, 1, "// ")
+	require.NoError(t, err)
+	assert.Equal(t, "  a\n  b", result.String())
+}
+
+// TestToggleLineComment_InvalidRange tests rejecting bad line ranges and prefixes
+func TestToggleLineComment_InvalidRange(t *testing.T) {
+	r := New("a\nb")
+
+	_, _, err := r.ToggleLineComment(1, 2, "#")
+	var rangeErr *ErrInvalidRange
+	assert.ErrorAs(t, err, &rangeErr)
+
+	_, _, err = r.ToggleLineComment(1, 0, "#")
+	assert.ErrorAs(t, err, &rangeErr)
+
+	_, _, err = r.ToggleLineComment(0, 1, "")
+	assert.Error(t, err)
+}
+
+// TestIndentRange_RoundTrip tests indenting lines 2-4 and dedenting them back
+func TestIndentRange_RoundTrip(t *testing.T) {
+	r := New("func f() {\nif x {\n\ny()\n}\n}")
+
+	indented, cs, err := r.IndentRange(1, 4, "    ")
+	require.NoError(t, err)
+	assert.Equal(t, "func f() {\n    if x {\n\n    y()\n    }\n}", indented.String())
+
+	// A cursor after "y" follows its line
+	assert.Equal(t, 28, cs.MapPosition(20, AssocBefore))
+
+	dedented, _, err := indented.DedentRange(1, 4, "    ", 4)
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), dedented.String())
+}
+
+// TestDedentRange_TabStops tests that dedenting moves back to the previous stop
+func TestDedentRange_TabStops(t *testing.T) {
+	r := New("      a\n  b\n\t  c\nd")
+
+	result, _, err := r.DedentRange(0, 3, "    ", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "    a\nb\n\tc\nd", result.String())
+
+	result, _, err = New("\t\ta\n    b").DedentRange(0, 1, "\t", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "\ta\nb", result.String())
+
+	// A tab unit is as wide as the tab width
+	result, _, err = New("        a\n            b").DedentRange(0, 1, "\t", 8)
+	require.NoError(t, err)
+	assert.Equal(t, "a\n        b", result.String())
+	result, _, err = New("        a").DedentRange(0, 0, "\t", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "    a", result.String())
+}
+
+// TestIndentRange_MiddleLines tests line ranges away from the document ends
+func TestIndentRange_MiddleLines(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 100; i++ {
+		sb.WriteString("  line\r\n")
+	}
+	r := chunkedRope(sb.String(), 7)
+
+	commented, _, err := r.ToggleLineComment(40, 41, "# ")
+	require.NoError(t, err)
+	lines := strings.Split(commented.String(), "\r\n")
+	assert.Equal(t, "  line", lines[39])
+	assert.Equal(t, "  # line", lines[40])
+	assert.Equal(t, "  # line", lines[41])
+	assert.Equal(t, "  line", lines[42])
+
+	dedented, _, err := r.DedentRange(99, 99, "  ", 4)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(dedented.String(), "  line\r\nline\r\n"))
+
+	_, _, err = r.DedentRange(99, 100, "  ", 4)
+	assert.Error(t, err)
+}
+
+// TestIndentRange_Invalid tests rejected line ranges and units
+func TestIndentRange_Invalid(t *testing.T) {
+	r := New("a\nb")
+
+	_, _, err := r.IndentRange(1, 2, "\t")
+	assert.Error(t, err)
+	_, _, err = r.DedentRange(1, 0, "\t", 4)
+	assert.Error(t, err)
+	_, _, err = r.IndentRange(0, 1, "")
+	assert.Error(t, err)
+	_, _, err = r.DedentRange(0, 1, "x", 4)
+	assert.Error(t, err)
+	_, _, err = r.DedentRange(0, 1, "\t", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,256 @@
+package rope
+
+import (
+	"strings"
+)
+
+// Line transformations rewrite a document line by line, keeping each line's
+// ending as it was. Lines are passed to callbacks without their ending, and
+// a CR before an LF counts as part of the ending even in LineEndingLF mode,
+// so CRLF documents stay CRLF.
+
+// lineExtent describes where a line's content and its line break lie.
+type lineExtent struct {
+	start      int // Character position of the line start
+	contentEnd int // End of the line's text, before its line break
+	end        int // End of the line break (contentEnd for the last line)
+}
+
+// lineExtents returns the extent of every line in a single pass, matching
+// LineCount.
+func (r *Rope) lineExtents() []lineExtent {
+	if r == nil || r.Length() == 0 {
+		return nil
+	}
+
+	var extents []lineExtent
+	start := 0
+	r.forEachLineBreak(func(breakStart, breakEnd int) bool {
+		extents = append(extents, lineExtent{start: start, contentEnd: breakStart, end: breakEnd})
+		start = breakEnd
+		return true
+	})
+	if start < r.Length() {
+		extents = append(extents, lineExtent{start: start, contentEnd: r.Length(), end: r.Length()})
+	}
+
+	if r.lineEnding == LineEndingLF {
+		for i, e := range extents {
+			if e.contentEnd > e.start && e.contentEnd < e.end {
+				if ch, err := r.CharAt(e.contentEnd - 1); err == nil && ch == '\r' {
+					extents[i].contentEnd--
+				}
+			}
+		}
+	}
+	return extents
+}
+
+// lineText returns the text of a line without its line break.
+func (r *Rope) lineText(e lineExtent) (string, error) {
+	return r.Slice(e.start, e.contentEnd)
+}
+
+// removeLines deletes the lines whose remove flag is set, together with
+// their line breaks. When the last line goes and it has no line break, the
+// break before it goes too, so the document does not end with a dangling
+// one.
+func (r *Rope) removeLines(extents []lineExtent, remove []bool) (*Rope, *ChangeSet, error) {
+	var edits []EditOperation
+	lastKept := -1
+	for i, e := range extents {
+		if !remove[i] {
+			lastKept = i
+			continue
+		}
+		if n := len(edits); n > 0 && edits[n-1].To == e.start {
+			edits[n-1].To = e.end
+		} else {
+			edits = append(edits, EditOperation{From: e.start, To: e.end})
+		}
+	}
+
+	if n := len(edits); n > 0 && lastKept >= 0 && lastKept < len(extents)-1 {
+		if last := extents[len(extents)-1]; last.contentEnd == last.end {
+			edits[n-1].From = extents[lastKept].contentEnd
+		}
+	}
+	return r.applyEdits(edits)
+}
+
+// FilterLines keeps only the lines for which keep returns true, removing the
+// others together with their line breaks, as one undoable edit.
+// keep receives the line number and the line's text without its ending.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("a\n\nb\n")
+//	r2, _, _ := r.FilterLines(func(_ int, line string) bool {
+//	    return strings.TrimSpace(line) != ""
+//	})
+//	fmt.Println(r2.String()) // "a\nb\n"
+func (r *Rope) FilterLines(keep func(lineNum int, line string) bool) (*Rope, *ChangeSet, error) {
+	extents := r.lineExtents()
+	remove := make([]bool, len(extents))
+	for i, e := range extents {
+		line, err := r.lineText(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		remove[i] = !keep(i, line)
+	}
+	return r.removeLines(extents, remove)
+}
+
+// MapLines replaces the text of every line with the result of calling fn on
+// it, as one undoable edit. fn receives the line without its ending, and the
+// ending is kept. The result should not contain line breaks of its own.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("a\r\nb")
+//	r2, _, _ := r.MapLines(strings.ToUpper)
+//	fmt.Println(r2.String()) // "A\r\nB"
+func (r *Rope) MapLines(fn func(line string) string) (*Rope, *ChangeSet, error) {
+	var edits []EditOperation
+	for _, e := range r.lineExtents() {
+		line, err := r.lineText(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if mapped := fn(line); mapped != line {
+			edits = append(edits, EditOperation{From: e.start, To: e.contentEnd, Text: mapped})
+		}
+	}
+	return r.applyEdits(edits)
+}
+
+// UniqueLines removes duplicate lines, comparing their text without line
+// endings, as one undoable edit. With adjacent set only a line equal to the
+// one before it is removed, like the uniq command; otherwise every repeat
+// of an earlier line is removed and first occurrences keep their order.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Example:
+//
+//	r := rope.New("a\nb\na\n")
+//	r2, _, _ := r.UniqueLines(false)
+//	fmt.Println(r2.String()) // "a\nb\n"
+func (r *Rope) UniqueLines(adjacent bool) (*Rope, *ChangeSet, error) {
+	extents := r.lineExtents()
+	remove := make([]bool, len(extents))
+	seen := make(map[string]bool)
+	prev := ""
+	for i, e := range extents {
+		line, err := r.lineText(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if adjacent {
+			remove[i] = i > 0 && line == prev
+			prev = line
+			continue
+		}
+		remove[i] = seen[line]
+		seen[line] = true
+	}
+	return r.removeLines(extents, remove)
+}
+
+// forEachLineText calls fn with the number and text of every line, without
+// its line break, until fn returns false. Lines are split at the breaks of
+// the rope's LineEndingMode and counted like LineCount, so line numbers
+// agree with Line. In LineEndingLF mode a '\r' before the '\n' is dropped
+// as well, and the lines are found in a single pass over the leaves, with
+// lines inside a leaf passed without copying; other modes slice each line.
+func (r *Rope) forEachLineText(fn func(lineNum int, line string) bool) {
+	if r == nil || r.Length() == 0 {
+		return
+	}
+	if r.lineEnding != LineEndingLF {
+		r.forEachLineSpan(0, r.LineCount(), fn)
+		return
+	}
+
+	var partial strings.Builder // Start of a line continued in the next leaf
+	lineNum := 0
+	emit := func(line string) bool {
+		line = strings.TrimSuffix(line, "\r")
+		ok := fn(lineNum, line)
+		lineNum++
+		return ok
+	}
+
+	stopped := false
+	forEachLeaf(r.root, func(text string) bool {
+		for {
+			i := strings.IndexByte(text, '\n')
+			if i < 0 {
+				partial.WriteString(text)
+				return true
+			}
+			line := text[:i]
+			if partial.Len() > 0 {
+				partial.WriteString(line)
+				line = partial.String()
+				partial.Reset()
+			}
+			if !emit(line) {
+				stopped = true
+				return false
+			}
+			text = text[i+1:]
+		}
+	})
+
+	// The last line only counts if it has content (matches LineCount)
+	if !stopped && partial.Len() > 0 {
+		emit(partial.String())
+	}
+}
+
+// CountLinesFunc returns the number of lines for which pred returns true,
+// e.g. for a status bar showing how many lines contain "TODO". pred
+// receives each line without its ending, and lines are split and numbered
+// like Line in the rope's LineEndingMode. In LineEndingLF mode the rope is
+// read in a single pass and lines are not copied unless they span leaves.
+//
+// Example:
+//
+//	r := rope.New("a\n\nb\n")
+//	n := r.CountLinesFunc(func(line string) bool { return line != "" })
+//	fmt.Println(n) // 2
+func (r *Rope) CountLinesFunc(pred func(line string) bool) int {
+	count := 0
+	r.forEachLineText(func(_ int, line string) bool {
+		if pred(line) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// FindLines returns the numbers of the lines for which pred returns true,
+// in order, reading the rope like CountLinesFunc. The numbers can be
+// passed to Line.
+//
+// Example:
+//
+//	r := rope.New("x := 1 // TODO\ny := 2\n// TODO: z")
+//	lines := r.FindLines(func(line string) bool {
+//	    return strings.Contains(line, "TODO")
+//	})
+//	fmt.Println(lines) // [0 2]
+func (r *Rope) FindLines(pred func(line string) bool) []int {
+	var lines []int
+	r.forEachLineText(func(lineNum int, line string) bool {
+		if pred(line) {
+			lines = append(lines, lineNum)
+		}
+		return true
+	})
+	return lines
+}
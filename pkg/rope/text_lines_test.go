@@ -0,0 +1,195 @@
+package rope
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertUndoable checks that cs turns before into after and that its
+// inverse turns after back into before.
+func assertUndoable(t *testing.T, before, after *Rope, cs *ChangeSet) {
+	t.Helper()
+	applied, err := cs.Apply(before)
+	require.NoError(t, err)
+	assert.Equal(t, after.String(), applied.String())
+
+	inverse, err := cs.Invert(before)
+	require.NoError(t, err)
+	undone, err := inverse.Apply(after)
+	require.NoError(t, err)
+	assert.Equal(t, before.String(), undone.String())
+}
+
+func notBlank(_ int, line string) bool {
+	return strings.TrimSpace(line) != ""
+}
+
+// TestFilterLines_RemoveBlank tests removing blank lines as one undoable edit
+func TestFilterLines_RemoveBlank(t *testing.T) {
+	r := chunkedRope("one\n\ntwo\n  \n\t\nthree\n", 4)
+
+	result, cs, err := r.FilterLines(notBlank)
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\nthree\n", result.String())
+	assertUndoable(t, r, result, cs)
+}
+
+// TestFilterLines_LineEndings tests that kept lines keep their endings
+func TestFilterLines_LineEndings(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"CRLF", "a\r\n\r\nb\r\n", "a\r\nb\r\n"},
+		{"blank last line", "a\nb\n   ", "a\nb"},
+		{"blank first line", "\na", "a"},
+		{"all blank", "\n \n", ""},
+		{"nothing removed", "a\nb", "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.text)
+			result, cs, err := r.FilterLines(notBlank)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.String())
+			assertUndoable(t, r, result, cs)
+		})
+	}
+}
+
+// TestFilterLines_LineNumbers tests removing lines by number and content
+func TestFilterLines_LineNumbers(t *testing.T) {
+	r := New("keep\n// drop\nkeep\nkeep")
+
+	result, _, err := r.FilterLines(func(lineNum int, line string) bool {
+		return lineNum != 3 && !strings.HasPrefix(line, "//")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "keep\nkeep", result.String())
+}
+
+// TestMapLines_PrefixLineNumbers tests prefixing every line with its number
+func TestMapLines_PrefixLineNumbers(t *testing.T) {
+	r := chunkedRope("alpha\r\nbeta\r\n\r\ngamma", 3)
+
+	lineNum := 0
+	result, cs, err := r.MapLines(func(line string) string {
+		lineNum++
+		return fmt.Sprintf("%d: %s", lineNum, line)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1: alpha\r\n2: beta\r\n3: \r\n4: gamma", result.String())
+	assertUndoable(t, r, result, cs)
+
+	// Unchanged lines produce no edits
+	same, _, err := r.MapLines(func(line string) string { return line })
+	require.NoError(t, err)
+	assert.Same(t, r, same)
+
+	empty, _, err := Empty().MapLines(strings.ToUpper)
+	require.NoError(t, err)
+	assert.Equal(t, "", empty.String())
+}
+
+// TestUniqueLines tests removing scattered and consecutive duplicates in both modes
+func TestUniqueLines(t *testing.T) {
+	text := "apple\napple\nbanana\ncherry\r\napple\ncherry\ncherry\nbanana"
+
+	tests := []struct {
+		name     string
+		adjacent bool
+		want     string
+	}{
+		{"all duplicates", false, "apple\nbanana\ncherry"},
+		{"adjacent only", true, "apple\nbanana\ncherry\r\napple\ncherry\nbanana"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chunkedRope(text, 5)
+			result, cs, err := r.UniqueLines(tt.adjacent)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.String())
+			assertUndoable(t, r, result, cs)
+		})
+	}
+}
+
+// TestUniqueLines_NoDuplicates tests that a document without duplicates is unchanged
+func TestUniqueLines_NoDuplicates(t *testing.T) {
+	r := New("a\nb\n\nc\n")
+
+	for _, adjacent := range []bool{false, true} {
+		result, _, err := r.UniqueLines(adjacent)
+		require.NoError(t, err)
+		assert.Same(t, r, result)
+	}
+
+	// Repeated blank lines count as duplicates too
+	result, _, err := New("a\n\n\nb\n").UniqueLines(true)
+	require.NoError(t, err)
+	assert.Equal(t, "a\n\nb\n", result.String())
+}
+
+// TestCountLinesFunc tests counting non-blank lines and lines with a substring
+func TestCountLinesFunc(t *testing.T) {
+	text := "func f() {\r\n\t// TODO: fix\r\n\r\n   \r\n\treturn // TODO\r\n}\r\n"
+	nonBlank := func(line string) bool { return strings.TrimSpace(line) != "" }
+	hasTODO := func(line string) bool { return strings.Contains(line, "TODO") }
+
+	for _, r := range []*Rope{New(text), chunkedRope(text, 5)} {
+		assert.Equal(t, 4, r.CountLinesFunc(nonBlank))
+		assert.Equal(t, 2, r.CountLinesFunc(hasTODO))
+		assert.Equal(t, []int{1, 4}, r.FindLines(hasTODO))
+		assert.Equal(t, []int{0, 1, 4, 5}, r.FindLines(nonBlank))
+	}
+
+	// Lines are counted like LineCount, and no line keeps its ending
+	r := New("a\n\nb")
+	var lines []string
+	r.FindLines(func(line string) bool { lines = append(lines, line); return false })
+	assert.Equal(t, []string{"a", "", "b"}, lines)
+	assert.Equal(t, r.LineCount(), r.CountLinesFunc(func(string) bool { return true }))
+	assert.Equal(t, 0, Empty().CountLinesFunc(func(string) bool { return true }))
+	assert.Nil(t, Empty().FindLines(func(string) bool { return true }))
+}
+
+// TestCountLinesFunc_LineEndingMode tests that lines follow the rope's line ending mode
+func TestCountLinesFunc_LineEndingMode(t *testing.T) {
+	all := func(string) bool { return true }
+	isB := func(line string) bool { return line == "b" }
+
+	cr := New("a\rb\rc").WithLineEnding(LineEndingCR)
+	assert.Equal(t, 3, cr.LineCount())
+	assert.Equal(t, 3, cr.CountLinesFunc(all))
+	lines := cr.FindLines(isB)
+	require.Equal(t, []int{1}, lines)
+	line, err := cr.Line(lines[0])
+	require.NoError(t, err)
+	assert.Equal(t, "b", line)
+
+	auto := chunkedRope("a\r\nb\rc\nb\n", 2).WithLineEnding(LineEndingAuto)
+	assert.Equal(t, auto.LineCount(), auto.CountLinesFunc(all))
+	assert.Equal(t, []int{1, 3}, auto.FindLines(isB))
+}
+
+// TestCountLinesFunc_SinglePass tests that lines are read without per-line lookups
+func TestCountLinesFunc_SinglePass(t *testing.T) {
+	r := chunkedRope(strings.Repeat("some text // TODO\nother text\n", 500), 64)
+
+	m := &Metrics{}
+	prev := SetMetrics(m)
+	defer SetMetrics(prev)
+
+	assert.Equal(t, 500, r.CountLinesFunc(func(line string) bool { return strings.Contains(line, "TODO") }))
+	assert.Len(t, r.FindLines(func(line string) bool { return line == "other text" }), 500)
+
+	stats := m.Snapshot()
+	assert.Zero(t, stats.Slices)
+	assert.Zero(t, stats.CharAts)
+	assert.Zero(t, stats.BytesCopied)
+}
@@ -0,0 +1,53 @@
+package rope
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ========== Unicode Normalization ==========
+
+// NormalizeUnicode converts the rope to the given Unicode normalization
+// form (norm.NFC, norm.NFD, norm.NFKC or norm.NFKD), as one undoable edit,
+// so that text that looks the same also compares and hashes the same.
+// Normalization may change the length, e.g. "e\u0301" becomes "\u00e9"
+// in NFC; the returned ChangeSet maps positions across the change.
+//
+// Only the segments that change are replaced, so positions in untouched
+// text map to themselves and a rope that is already normalized is
+// returned as is with an identity ChangeSet.
+//
+// Example:
+//
+//	r2, cs, _ := rope.New("cafe\u0301").NormalizeUnicode(norm.NFC)
+//	fmt.Println(cs.LenBefore(), r2.Length()) // 5 4
+func (r *Rope) NormalizeUnicode(form norm.Form) (*Rope, *ChangeSet, error) {
+	if r == nil {
+		r = Empty()
+	}
+
+	text := r.String()
+	if form.IsNormalString(text) {
+		return r.applyEdits(nil)
+	}
+
+	var edits []EditOperation
+	bytePos, charPos := 0, 0
+	for bytePos < len(text) {
+		rest := text[bytePos:]
+		n := form.NextBoundaryInString(rest, true)
+		if n <= 0 {
+			n = len(rest)
+		}
+
+		segment := rest[:n]
+		chars := utf8.RuneCountInString(segment)
+		if normalized := form.String(segment); normalized != segment {
+			edits = append(edits, EditOperation{From: charPos, To: charPos + chars, Text: normalized})
+		}
+		bytePos += n
+		charPos += chars
+	}
+	return r.applyEdits(edits)
+}
@@ -0,0 +1,64 @@
+package rope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+// TestNormalizeUnicode_NFC tests composing characters and tracking the length change
+func TestNormalizeUnicode_NFC(t *testing.T) {
+	r := New("cafe\u0301 and re\u0301sume\u0301!")
+
+	r2, cs, err := r.NormalizeUnicode(norm.NFC)
+	require.NoError(t, err)
+	assert.Equal(t, "caf\u00e9 and r\u00e9sum\u00e9!", r2.String())
+	assert.Equal(t, r.Length(), cs.LenBefore())
+	assert.Equal(t, r.Length()-3, cs.LenAfter())
+	assert.Equal(t, r2.Length(), cs.LenAfter())
+
+	// The ChangeSet round-trips
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, r2.String(), applied.String())
+	inverse, err := cs.Invert(r)
+	require.NoError(t, err)
+	restored, err := inverse.Apply(r2)
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), restored.String())
+
+	// Positions after a composed character shift left
+	assert.Equal(t, 5, cs.MapPosition(6, AssocBefore))
+	assert.Equal(t, 1, cs.MapPosition(1, AssocBefore))
+}
+
+// TestNormalizeUnicode_NFD tests decomposing characters
+func TestNormalizeUnicode_NFD(t *testing.T) {
+	r := chunkedRope("\u00e9t\u00e9 \u00c5", 2)
+
+	r2, cs, err := r.NormalizeUnicode(norm.NFD)
+	require.NoError(t, err)
+	assert.Equal(t, "e\u0301te\u0301 A\u030a", r2.String())
+	assert.Equal(t, r.Length()+3, cs.LenAfter())
+
+	back, _, err := r2.NormalizeUnicode(norm.NFC)
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), back.String())
+}
+
+// TestNormalizeUnicode_AlreadyNormal tests that normal text is left alone
+func TestNormalizeUnicode_AlreadyNormal(t *testing.T) {
+	r := New("plain \u00e9")
+
+	r2, cs, err := r.NormalizeUnicode(norm.NFC)
+	require.NoError(t, err)
+	assert.Same(t, r, r2)
+	assert.Equal(t, []Operation{{OpType: OpRetain, Length: 7}}, cs.Operations())
+
+	var nilRope *Rope
+	r2, _, err = nilRope.NormalizeUnicode(norm.NFC)
+	require.NoError(t, err)
+	assert.Equal(t, 0, r2.Length())
+}
@@ -0,0 +1,328 @@
+package rope
+
+// ========== Text Objects ==========
+
+// TextObject identifies a kind of text region that a selection can be
+// expanded to, in the spirit of Vim text objects and VS Code's
+// expand-selection command.
+type TextObject int
+
+const (
+	// TextObjectWord is a run of word characters (letters, digits, underscore).
+	TextObjectWord TextObject = iota
+
+	// TextObjectLine is a whole line, excluding its line ending.
+	TextObjectLine
+
+	// TextObjectParagraph is a run of non-blank lines, excluding the final line ending.
+	TextObjectParagraph
+
+	// TextObjectBrackets is the content of a (), [] or {} pair, and then the
+	// pair itself including the brackets.
+	TextObjectBrackets
+)
+
+// String returns the string representation of TextObject
+func (o TextObject) String() string {
+	switch o {
+	case TextObjectWord:
+		return "Word"
+	case TextObjectLine:
+		return "Line"
+	case TextObjectParagraph:
+		return "Paragraph"
+	case TextObjectBrackets:
+		return "Brackets"
+	default:
+		return "Unknown"
+	}
+}
+
+// ExpandSelection grows sel to the smallest text object of the given kind
+// that strictly contains it. Repeated calls expand outward: a word grows to
+// its line, then to its paragraph, then to the whole document; brackets grow
+// from the inner content to the pair including the brackets, then to the
+// next enclosing pair.
+//
+// If nothing larger exists, sel is returned unchanged. The direction of the
+// selection is preserved.
+//
+// Example:
+//
+//	r := rope.New("call(a, b)")
+//	sel := r.ExpandSelection(rope.Point(6), rope.TextObjectBrackets) // "a, b"
+//	sel = r.ExpandSelection(sel, rope.TextObjectBrackets)           // "(a, b)"
+func (r *Rope) ExpandSelection(sel Range, kind TextObject) Range {
+	sel = sel.Clamp(r.Length())
+	wb := NewWordBoundary(r)
+
+	// Look for the object in a window of lines around sel, widening the
+	// window while the object found reaches its edge
+	for margin := textObjectWindow; ; margin *= 2 {
+		w := r.textWindowAround(sel.From(), sel.To(), margin)
+		local := w.toLocal(sel)
+		clipped := false
+		for _, candidate := range textObjectChain(wb, w.runes, local, kind) {
+			if candidate.ContainsRange(local) && candidate.Len() > local.Len() {
+				if clipped = w.clipped(candidate); !clipped {
+					return withDirectionOf(w.toDoc(candidate), sel)
+				}
+				break
+			}
+		}
+		if !clipped && w.whole() {
+			return sel
+		}
+	}
+}
+
+// ShrinkSelection is the inverse of ExpandSelection: it returns the largest
+// text object of the given kind that lies strictly inside sel, centered on the
+// start of sel (just inside it when sel starts with an opening bracket).
+// Shrinking below the smallest object yields a cursor at the start of sel.
+//
+// The direction of the selection is preserved.
+func (r *Rope) ShrinkSelection(sel Range, kind TextObject) Range {
+	sel = sel.Clamp(r.Length())
+	if sel.IsCursor() {
+		return sel
+	}
+
+	// Objects inside sel are found exactly in the lines around it
+	w := r.textWindowAround(sel.From(), sel.To(), 1)
+	local := w.toLocal(sel)
+	runes := w.runes
+
+	// Center on the start of sel, stepping inside a leading opening bracket
+	center := local.From()
+	if kind == TextObjectBrackets && (runes[center] == '(' || runes[center] == '[' || runes[center] == '{') {
+		center++
+	}
+
+	best := Point(local.From())
+	for _, candidate := range textObjectChain(NewWordBoundary(r), runes, Point(center), kind) {
+		if local.ContainsRange(candidate) && candidate.Len() < local.Len() && candidate.Len() > best.Len() {
+			best = candidate
+		}
+	}
+	if best.IsCursor() {
+		return w.toDoc(best)
+	}
+	return withDirectionOf(w.toDoc(best), sel)
+}
+
+// textObjectWindow is the initial number of characters read on either side
+// of a selection when looking for the text objects around it.
+const textObjectWindow = 256
+
+// textWindow holds the whole lines around a position, split at '\n', so
+// that text objects near the cursor are found without reading the whole
+// document. Positions within the window are relative to start.
+type textWindow struct {
+	start  int // Character position of runes[0]
+	runes  []rune
+	docLen int
+}
+
+// textWindowAround returns the window of whole lines covering at least
+// margin characters before from and after to.
+func (r *Rope) textWindowAround(from, to, margin int) textWindow {
+	lo := max(from-margin, 0)
+	hi := min(to+margin, r.Length())
+	if n := lineBreaksBefore(r.root, lo); n > 0 {
+		lo = lineBreakPos(r.root, n) + 1
+	}
+	if n := lineBreaksBefore(r.root, hi); n < nodeLineBreaks(r.root) {
+		hi = lineBreakPos(r.root, n+1)
+	} else {
+		hi = r.Length()
+	}
+
+	runes, _ := r.SliceRunes(lo, hi)
+	return textWindow{start: lo, runes: runes, docLen: r.Length()}
+}
+
+// whole reports whether the window covers the whole document.
+func (w textWindow) whole() bool {
+	return w.start == 0 && len(w.runes) == w.docLen
+}
+
+// clipped reports whether rng touches an edge of the window that is not an
+// edge of the document, so the text it stands for may continue outside.
+func (w textWindow) clipped(rng Range) bool {
+	return (rng.From() == 0 && w.start > 0) ||
+		(rng.To() == len(w.runes) && w.start+len(w.runes) < w.docLen)
+}
+
+// toLocal converts a document range to window positions.
+func (w textWindow) toLocal(rng Range) Range {
+	return NewRange(rng.Anchor-w.start, rng.Head-w.start)
+}
+
+// toDoc converts a window range to document positions.
+func (w textWindow) toDoc(rng Range) Range {
+	return NewRange(rng.Anchor+w.start, rng.Head+w.start)
+}
+
+// textObjectChain returns the text objects around sel, ordered from the
+// innermost to the outermost.
+func textObjectChain(wb *WordBoundary, runes []rune, sel Range, kind TextObject) []Range {
+	var chain []Range
+
+	if kind == TextObjectBrackets {
+		inner := sel
+		for {
+			open, close, ok := enclosingBrackets(runes, inner)
+			if !ok {
+				break
+			}
+			chain = append(chain, NewRange(open+1, close), NewRange(open, close+1))
+			inner = NewRange(open, close+1)
+		}
+		return append(chain, NewRange(0, len(runes)))
+	}
+
+	if kind == TextObjectWord {
+		if word, ok := wordRange(wb, runes, sel); ok {
+			chain = append(chain, word)
+		}
+	}
+	if kind <= TextObjectLine {
+		chain = append(chain, lineRange(runes, sel))
+	}
+	chain = append(chain, paragraphRange(runes, sel))
+	return append(chain, NewRange(0, len(runes)))
+}
+
+// wordRange returns the word touching sel, preferring the word to the right.
+func wordRange(wb *WordBoundary, runes []rune, sel Range) (Range, bool) {
+	from, to := sel.From(), sel.To()
+
+	switch {
+	case from < len(runes) && wb.IsWordChar(runes[from]):
+	case from > 0 && wb.IsWordChar(runes[from-1]):
+		from--
+	default:
+		return Range{}, false
+	}
+
+	for from > 0 && wb.IsWordChar(runes[from-1]) {
+		from--
+	}
+	if to < from+1 {
+		to = from + 1
+	}
+	for to < len(runes) && wb.IsWordChar(runes[to]) {
+		to++
+	}
+	return NewRange(from, to), true
+}
+
+// lineRange returns the lines covered by sel, excluding the final line ending.
+func lineRange(runes []rune, sel Range) Range {
+	from, to := sel.From(), sel.To()
+	for from > 0 && runes[from-1] != '\n' {
+		from--
+	}
+	for to < len(runes) && runes[to] != '\n' {
+		to++
+	}
+	if to > from && runes[to-1] == '\r' && to < len(runes) {
+		to--
+	}
+	return NewRange(from, to)
+}
+
+// paragraphRange returns the run of non-blank lines around sel, excluding
+// the final line ending.
+func paragraphRange(runes []rune, sel Range) Range {
+	lines := lineRange(runes, sel)
+	from, to := lines.From(), lines.To()
+
+	// Extend upward while the previous line is not blank
+	for from > 0 {
+		prev := lineRange(runes, Point(from-1))
+		if isBlankRunes(runes[prev.From():prev.To()]) {
+			break
+		}
+		from = prev.From()
+	}
+
+	// Extend downward while the next line is not blank
+	for to < len(runes) {
+		next := to
+		for next < len(runes) && runes[next] != '\n' {
+			next++
+		}
+		if next >= len(runes) {
+			break
+		}
+		line := lineRange(runes, Point(next+1))
+		if line.Len() == 0 && line.From() >= len(runes) {
+			break
+		}
+		if isBlankRunes(runes[line.From():line.To()]) {
+			break
+		}
+		to = line.To()
+	}
+	return NewRange(from, to)
+}
+
+// isBlankRunes reports whether runes contains only whitespace.
+func isBlankRunes(runes []rune) bool {
+	for _, ch := range runes {
+		if !IsWhitespace(ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// enclosingBrackets finds the innermost bracket pair that strictly encloses
+// sel, returning the positions of the opening and closing bracket.
+func enclosingBrackets(runes []rune, sel Range) (int, int, bool) {
+	pairs := map[rune]rune{'(': ')', '[': ']', '{': '}'}
+	closers := map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+	// Scan backward for an unmatched opening bracket
+	var stack []rune
+	for open := sel.From() - 1; open >= 0; open-- {
+		ch := runes[open]
+		if _, ok := closers[ch]; ok {
+			stack = append(stack, ch)
+			continue
+		}
+		closeCh, ok := pairs[ch]
+		if !ok {
+			continue
+		}
+		if len(stack) > 0 {
+			if stack[len(stack)-1] == closeCh {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		// Scan forward from the selection end for the matching close
+		depth := 0
+		for close := sel.To(); close < len(runes); close++ {
+			switch runes[close] {
+			case ch:
+				depth++
+			case closeCh:
+				if depth == 0 {
+					return open, close, true
+				}
+				depth--
+			}
+		}
+		return 0, 0, false
+	}
+	return 0, 0, false
+}
+
+// withDirectionOf returns r oriented the same way as like.
+func withDirectionOf(r Range, like Range) Range {
+	return r.WithDirection(like.IsForward())
+}
@@ -0,0 +1,135 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sliceRange returns the text covered by a range.
+func sliceRange(r *Rope, rng Range) string {
+	s, _ := r.Slice(rng.From(), rng.To())
+	return s
+}
+
+// TestExpandSelection_WordLineParagraph tests expanding word -> line -> paragraph
+func TestExpandSelection_WordLineParagraph(t *testing.T) {
+	r := New("first para\nsecond line here\n\nother para")
+
+	sel := r.ExpandSelection(Point(14), TextObjectWord)
+	assert.Equal(t, "second", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(sel, TextObjectWord)
+	assert.Equal(t, "second line here", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(sel, TextObjectWord)
+	assert.Equal(t, "first para\nsecond line here", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(sel, TextObjectWord)
+	assert.Equal(t, r.String(), sliceRange(r, sel))
+
+	// Nothing is larger than the whole document
+	assert.Equal(t, sel, r.ExpandSelection(sel, TextObjectWord))
+}
+
+// TestExpandSelection_Line tests expanding directly to a line
+func TestExpandSelection_Line(t *testing.T) {
+	r := New("one\r\ntwo\r\n")
+
+	sel := r.ExpandSelection(Point(1), TextObjectLine)
+	assert.Equal(t, "one", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(Point(6), TextObjectParagraph)
+	assert.Equal(t, "one\r\ntwo", sliceRange(r, sel))
+}
+
+// TestExpandSelection_Brackets tests expanding from inside a bracket pair
+func TestExpandSelection_Brackets(t *testing.T) {
+	r := New("f(x, [a, b]) + g")
+
+	sel := r.ExpandSelection(Point(7), TextObjectBrackets)
+	assert.Equal(t, "a, b", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(sel, TextObjectBrackets)
+	assert.Equal(t, "[a, b]", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(sel, TextObjectBrackets)
+	assert.Equal(t, "x, [a, b]", sliceRange(r, sel))
+
+	sel = r.ExpandSelection(sel, TextObjectBrackets)
+	assert.Equal(t, "(x, [a, b])", sliceRange(r, sel))
+}
+
+// TestExpandSelection_PreservesDirection tests that backward selections stay backward
+func TestExpandSelection_PreservesDirection(t *testing.T) {
+	r := New("hello world")
+
+	sel := r.ExpandSelection(NewRange(8, 7), TextObjectWord)
+	assert.Equal(t, NewRange(11, 6), sel)
+}
+
+// TestShrinkSelection tests shrinking back down the object chain
+func TestShrinkSelection(t *testing.T) {
+	r := New("first para\nsecond line here\n\nother para")
+
+	sel := NewRange(0, r.Length())
+	sel = r.ShrinkSelection(sel, TextObjectWord)
+	assert.Equal(t, "first para\nsecond line here", sliceRange(r, sel))
+
+	sel = r.ShrinkSelection(sel, TextObjectWord)
+	assert.Equal(t, "first para", sliceRange(r, sel))
+
+	sel = r.ShrinkSelection(sel, TextObjectWord)
+	assert.Equal(t, "first", sliceRange(r, sel))
+
+	sel = r.ShrinkSelection(sel, TextObjectWord)
+	assert.True(t, sel.IsCursor())
+	assert.Equal(t, 0, sel.Head)
+
+	// Brackets shrink from the pair to its content
+	r = New("(a, b)")
+	sel = r.ShrinkSelection(NewRange(0, 6), TextObjectBrackets)
+	assert.Equal(t, "a, b", sliceRange(r, sel))
+}
+
+// TestExpandSelection_LargeDocument tests objects reaching far from the cursor
+func TestExpandSelection_LargeDocument(t *testing.T) {
+	para := strings.Repeat("some words on a line   \n", 100)
+	body := strings.Repeat("x, ", 500)
+	text := strings.Repeat("filler\n", 1000) + "\n" + para + "\n" +
+		"call(" + body + "[inner]\n" + body + ")\n" + strings.Repeat("filler\n", 1000)
+	r := chunkedRope(text, 97)
+
+	// A paragraph much longer than the initial window
+	paraStart := strings.Index(text, para)
+	sel := r.ExpandSelection(Point(paraStart+len(para)/2), TextObjectParagraph)
+	assert.Equal(t, strings.TrimSuffix(para, "\n"), sliceRange(r, sel))
+
+	// Brackets whose pair spans lines far from the cursor
+	inner := strings.Index(text, "inner")
+	sel = r.ExpandSelection(Point(inner), TextObjectBrackets)
+	assert.Equal(t, "inner", sliceRange(r, sel))
+	sel = r.ExpandSelection(sel, TextObjectBrackets)
+	sel = r.ExpandSelection(sel, TextObjectBrackets)
+	assert.Equal(t, body+"[inner]\n"+body, sliceRange(r, sel))
+
+	sel = r.ExpandSelection(Point(inner), TextObjectWord)
+	for i := 0; i < 3; i++ {
+		sel = r.ExpandSelection(sel, TextObjectBrackets)
+	}
+	assert.Equal(t, "("+body+"[inner]\n"+body+")", sliceRange(r, sel))
+	sel = r.ExpandSelection(sel, TextObjectBrackets)
+	assert.Equal(t, r.Length(), sel.Len())
+
+	// Shrinking a paragraph in the middle of the document
+	paraSel := NewRange(paraStart, paraStart+len(para)-1)
+	sel = r.ShrinkSelection(paraSel, TextObjectLine)
+	assert.Equal(t, "some words on a line   ", sliceRange(r, sel))
+}
+
+// TestTextObject_String tests the string representation
+func TestTextObject_String(t *testing.T) {
+	assert.Equal(t, "Word", TextObjectWord.String())
+	assert.Equal(t, "Brackets", TextObjectBrackets.String())
+}
@@ -0,0 +1,432 @@
+package rope
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ========== Search ==========
+
+// SearchOptions configures text searches.
+type SearchOptions struct {
+	// CaseInsensitive matches letters regardless of case, using simple
+	// Unicode case folding.
+	CaseInsensitive bool
+
+	// WholeWord only accepts matches that are not directly preceded or
+	// followed by a word character.
+	WholeWord bool
+}
+
+// searcher matches a pattern against rune slices according to SearchOptions.
+type searcher struct {
+	pattern []rune
+	opts    SearchOptions
+	wb      *WordBoundary // Decides word characters for WholeWord
+}
+
+func newSearcher(r *Rope, pattern string, opts SearchOptions) *searcher {
+	return &searcher{pattern: []rune(pattern), opts: opts, wb: NewWordBoundary(r)}
+}
+
+// equalRune compares two runes, folding case if requested.
+func (s *searcher) equalRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	return s.opts.CaseInsensitive && unicode.ToLower(a) == unicode.ToLower(b)
+}
+
+// matchAt reports whether the pattern matches text at index i.
+func (s *searcher) matchAt(text []rune, i int) bool {
+	if i < 0 || i+len(s.pattern) > len(text) {
+		return false
+	}
+	for j, ch := range s.pattern {
+		if !s.equalRune(text[i+j], ch) {
+			return false
+		}
+	}
+	if s.opts.WholeWord {
+		if i > 0 && s.wb.IsWordChar(text[i-1]) {
+			return false
+		}
+		if end := i + len(s.pattern); end < len(text) && s.wb.IsWordChar(text[end]) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexFrom returns the first index at or after from where the pattern
+// matches text, or -1.
+func (s *searcher) indexFrom(text []rune, from int) int {
+	for i := from; i+len(s.pattern) <= len(text); i++ {
+		if s.matchAt(text, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// GrepResult is a single match found by Grep.
+type GrepResult struct {
+	LineNumber int    // Zero-based line number of the match
+	Line       string // Text of the line, without its line ending
+	Match      Range  // Character range of the match in the document
+}
+
+// GrepIterator steps through the matches found by GrepIter in document order.
+type GrepIterator struct {
+	r       *Rope
+	matches *MatchIterator
+	result  GrepResult
+
+	// The line of the previous match: its number and text, where its text
+	// ends and the position of the '\n' ending it
+	lineNum     int
+	line        string
+	lineContent int
+	lineBreak   int
+}
+
+// Grep returns every non-overlapping occurrence of pattern, each reported
+// together with the line it occurs on. A line with several matches yields
+// several results. Lines are split at '\n', and a '\r' before it is not
+// part of the line; matches never span a line ending.
+//
+// Grep makes a single pass over the document; use GrepIter to stop early
+// without collecting every result.
+//
+// Example:
+//
+//	r := rope.New("foo bar\nbar baz")
+//	for _, res := range r.Grep("bar", rope.SearchOptions{}) {
+//	    fmt.Println(res.LineNumber, res.Match.From()) // "0 4", then "1 8"
+//	}
+func (r *Rope) Grep(pattern string, opts SearchOptions) []GrepResult {
+	return r.GrepIter(pattern, opts).Collect()
+}
+
+// GrepIter returns an iterator over the results of Grep.
+//
+// Matches are found lazily with the same search as FindIter, and the line
+// of a match is looked up through the rope's cached line counts only when
+// the match is on a new line, so the cost is linear in the part of the
+// document searched, however many matches there are.
+//
+// Example:
+//
+//	it := r.GrepIter("bar", rope.SearchOptions{})
+//	for it.Next() {
+//	    res := it.Result()
+//	    fmt.Println(res.LineNumber, res.Line)
+//	}
+func (r *Rope) GrepIter(pattern string, opts SearchOptions) *GrepIterator {
+	if strings.ContainsRune(pattern, '\n') {
+		pattern = "" // Can never match within a line
+	}
+	return &GrepIterator{r: r, matches: r.FindIter(pattern, opts), lineBreak: -1}
+}
+
+// Next advances to the next match, returning false when there is none.
+func (it *GrepIterator) Next() bool {
+	for it.matches.Next() {
+		m := it.matches.Match()
+		if m.From() > it.lineBreak {
+			it.seekLine(m.From())
+		}
+		// Skip a match that runs into the '\r' of a "\r\n" ending
+		if m.To() > it.lineContent {
+			continue
+		}
+		it.result = GrepResult{LineNumber: it.lineNum, Line: it.line, Match: m}
+		return true
+	}
+	return false
+}
+
+// Result returns the current match.
+func (it *GrepIterator) Result() GrepResult {
+	return it.result
+}
+
+// Collect returns the remaining matches.
+func (it *GrepIterator) Collect() []GrepResult {
+	var results []GrepResult
+	for it.Next() {
+		results = append(results, it.Result())
+	}
+	return results
+}
+
+// seekLine makes the line containing pos the current line.
+func (it *GrepIterator) seekLine(pos int) {
+	root := it.r.root
+	it.lineNum = lineBreaksBefore(root, pos)
+	lineStart := 0
+	if it.lineNum > 0 {
+		lineStart = lineBreakPos(root, it.lineNum) + 1
+	}
+	it.lineBreak = it.r.Length()
+	if it.lineNum < nodeLineBreaks(root) {
+		it.lineBreak = lineBreakPos(root, it.lineNum+1)
+	}
+
+	it.line, _ = it.r.Slice(lineStart, it.lineBreak)
+	it.lineContent = it.lineBreak
+	if strings.HasSuffix(it.line, "\r") {
+		it.line = it.line[:len(it.line)-1]
+		it.lineContent--
+	}
+}
+
+// MatchIterator steps through the non-overlapping matches of a pattern in
+// document order. Matches are found lazily: each call to Next decodes only
+// as many leaves as it needs to reach the next match.
+type MatchIterator struct {
+	s        *searcher
+	leaves   *leafWalker
+	buf      []rune // Decoded text starting at bufStart
+	bufStart int
+	pos      int // Next position to try
+	match    Range
+}
+
+// FindIter returns an iterator over the non-overlapping occurrences of
+// pattern, from the start of the document. Unlike Grep, matches may span
+// line endings. Use it for "find next" or for patterns that may match very
+// often, where collecting every match up front would be wasteful.
+//
+// Example:
+//
+//	it := r.FindIter("foo", rope.SearchOptions{})
+//	for it.Next() {
+//	    fmt.Println(it.Match().From())
+//	}
+func (r *Rope) FindIter(pattern string, opts SearchOptions) *MatchIterator {
+	return r.findIterFrom(pattern, 0, opts)
+}
+
+// findIterFrom returns a MatchIterator whose first match starts at or after
+// from, decoding the document only from the leaf before from onwards.
+func (r *Rope) findIterFrom(pattern string, from int, opts SearchOptions) *MatchIterator {
+	// WholeWord needs the character before from
+	leaves, bufStart := leafWalkerAt(r, max(from-1, 0))
+	return &MatchIterator{
+		s:        newSearcher(r, pattern, opts),
+		leaves:   leaves,
+		bufStart: bufStart,
+		pos:      max(from, 0),
+	}
+}
+
+// Next advances to the next match, returning false when there is none.
+func (it *MatchIterator) Next() bool {
+	n := len(it.s.pattern)
+	if n == 0 {
+		return false
+	}
+
+	for {
+		// The window must hold the pattern and the character after it,
+		// which WholeWord checks
+		if !it.fill(it.pos + n + 1) {
+			return false
+		}
+		if it.s.matchAt(it.buf, it.pos-it.bufStart) {
+			it.match = NewRange(it.pos, it.pos+n)
+			it.pos += n
+			it.trim()
+			return true
+		}
+		it.pos++
+		it.trim()
+	}
+}
+
+// Match returns the range of the current match.
+func (it *MatchIterator) Match() Range {
+	return it.match
+}
+
+// fill decodes leaves until the window reaches end or the document ends,
+// and reports whether a match at it.pos still fits.
+func (it *MatchIterator) fill(end int) bool {
+	for it.bufStart+len(it.buf) < end {
+		text, ok := it.leaves.next()
+		if !ok {
+			break
+		}
+		for _, ch := range text {
+			it.buf = append(it.buf, ch)
+		}
+	}
+	return it.pos+len(it.s.pattern) <= it.bufStart+len(it.buf)
+}
+
+// trim drops decoded text before it.pos, keeping the character before it
+// for WholeWord. The window is only shifted once the dead prefix outgrows
+// the live part, so each character is copied a bounded number of times.
+func (it *MatchIterator) trim() {
+	drop := it.pos - 1 - it.bufStart
+	if drop < 1024 || drop < len(it.buf)-drop {
+		return
+	}
+	it.buf = it.buf[:copy(it.buf, it.buf[drop:])]
+	it.bufStart += drop
+}
+
+// FindNextWrap returns the first occurrence of pattern starting at or after
+// from, wrapping around to the start of the document when there is none,
+// as editors do for "find next". It also reports whether the search
+// wrapped and whether the pattern occurs at all.
+//
+// Example:
+//
+//	r := rope.New("foo bar")
+//	m, wrapped, found := r.FindNextWrap("foo", 4, rope.SearchOptions{})
+//	fmt.Println(m.From(), wrapped, found) // 0 true true
+func (r *Rope) FindNextWrap(pattern string, from int, opts SearchOptions) (Range, bool, bool) {
+	if it := r.findIterFrom(pattern, from, opts); it.Next() {
+		return it.Match(), false, true
+	}
+	if from <= 0 {
+		return Range{}, false, false
+	}
+	if it := r.findIterFrom(pattern, 0, opts); it.Next() {
+		return it.Match(), true, true
+	}
+	return Range{}, false, false
+}
+
+// findPrevBlock is the number of candidate positions FindPrev decodes at a
+// time while searching backwards.
+const findPrevBlock = 4096
+
+// FindPrev returns the last occurrence of pattern that ends at or before
+// position before, for "find previous" from a cursor. It does not wrap
+// around: if there is no match before the position, it returns false.
+//
+// The document is searched backwards from before in blocks, so a match
+// near the cursor is found without reading the rest of the document.
+//
+// Example:
+//
+//	r := rope.New("foo bar foo")
+//	m, ok := r.FindPrev("foo", 8, rope.SearchOptions{})
+//	fmt.Println(m.From(), ok) // 0 true
+func (r *Rope) FindPrev(pattern string, before int, opts SearchOptions) (Range, bool) {
+	s := newSearcher(r, pattern, opts)
+	n := len(s.pattern)
+	if r == nil || n == 0 || before < n {
+		return Range{}, false
+	}
+
+	length := r.Length()
+	var buf []rune
+	for hi := min(before, length) - n; hi >= 0; hi -= findPrevBlock {
+		lo := max(hi-findPrevBlock+1, 0)
+
+		// Decode the candidates plus one character of context on each side
+		winStart, winEnd := max(lo-1, 0), min(hi+n+1, length)
+		if cap(buf) < winEnd-winStart {
+			buf = make([]rune, winEnd-winStart)
+		}
+		window := buf[:collectRunes(r.root, winStart, winEnd, buf[:winEnd-winStart])]
+
+		for i := hi; i >= lo; i-- {
+			if s.matchAt(window, i-winStart) {
+				return NewRange(i, i+n), true
+			}
+		}
+	}
+	return Range{}, false
+}
+
+// ReplaceMatch replaces the text of match m, typically one found by FindIter
+// or FindNextWrap, for interactive "replace" one match at a time. It returns
+// the new rope, the position just after the replacement, where "find next"
+// should continue so the replacement itself is not searched again, and the
+// ChangeSet of the edit.
+//
+// Example:
+//
+//	m, _, found := r.FindNextWrap("foo", cursor, opts)
+//	if found {
+//	    r, cursor, _, _ = r.ReplaceMatch(m, "bar")
+//	}
+func (r *Rope) ReplaceMatch(m Range, replacement string) (*Rope, int, *ChangeSet, error) {
+	if r == nil {
+		r = Empty()
+	}
+	from, to := m.From(), m.To()
+	if from < 0 || to > r.Length() {
+		return nil, 0, nil, &ErrInvalidRange{
+			Operation: "ReplaceMatch",
+			Start:     from,
+			End:       to,
+			ValidMax:  r.Length(),
+		}
+	}
+
+	result, cs, err := r.applyEdits([]EditOperation{{From: from, To: to, Text: replacement}})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return result, from + utf8.RuneCountInString(replacement), cs, nil
+}
+
+// ========== Regex Replace ==========
+
+// ReplaceRegexFunc replaces every match of re with the result of calling fn
+// on it, as one undoable edit. fn receives the full match followed by the
+// text of each capture group ("" for groups that did not participate).
+// Empty matches are handled like regexp.ReplaceAllStringFunc.
+// Returns the new rope and the ChangeSet of the edit.
+//
+// Unlike FindIter, which streams the leaves, ReplaceRegexFunc copies the
+// whole document into one string with String and matches that, so that
+// matches and capture groups may span leaves. Each call therefore costs
+// O(n) time and memory in the document length, even when nothing matches.
+//
+// Example:
+//
+//	re := regexp.MustCompile(`(\w+)=(\w+)`)
+//	r2, _, _ := rope.New("a=b").ReplaceRegexFunc(re, func(m []string) string {
+//	    return m[2] + "=" + m[1]
+//	})
+//	fmt.Println(r2.String()) // "b=a"
+func (r *Rope) ReplaceRegexFunc(re *regexp.Regexp, fn func(match []string) string) (*Rope, *ChangeSet, error) {
+	if re == nil {
+		return nil, nil, &ErrInvalidInput{
+			Parameter: "re",
+			Value:     re,
+			Reason:    "must not be nil",
+		}
+	}
+
+	text := r.String()
+	var edits []EditOperation
+	bytePos, charPos := 0, 0
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		match := make([]string, len(loc)/2)
+		for i := range match {
+			if loc[2*i] >= 0 {
+				match[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+
+		start := charPos + utf8.RuneCountInString(text[bytePos:loc[0]])
+		end := start + utf8.RuneCountInString(match[0])
+		bytePos, charPos = loc[1], end
+
+		if replacement := fn(match); replacement != match[0] {
+			edits = append(edits, EditOperation{From: start, To: end, Text: replacement})
+		}
+	}
+
+	return r.applyEdits(edits)
+}
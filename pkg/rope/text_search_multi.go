@@ -0,0 +1,158 @@
+package rope
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// ========== Multi-Pattern Search ==========
+
+// MultiMatch is a single match found by FindAny.
+type MultiMatch struct {
+	Pattern int   // Index of the matching pattern in the patterns slice
+	Range   Range // Character range of the match in the document
+}
+
+// acNode is a state of an Aho-Corasick automaton: a trie node extended
+// with the state to fall back to when no transition matches.
+type acNode struct {
+	next map[rune]int // Trie transitions
+	fail int          // Longest proper suffix of this state that is also a state
+	dict int          // Nearest state along the fail chain with outputs, or -1
+	out  []int        // Patterns ending at this state
+}
+
+// acAutomaton matches a set of patterns in a single pass over the text.
+type acAutomaton struct {
+	nodes   []acNode
+	lengths []int // Pattern lengths in characters
+}
+
+// newACAutomaton builds the automaton for patterns. Empty patterns never
+// match.
+func newACAutomaton(patterns []string) *acAutomaton {
+	a := &acAutomaton{
+		nodes:   []acNode{{dict: -1}},
+		lengths: make([]int, len(patterns)),
+	}
+
+	for i, p := range patterns {
+		a.lengths[i] = utf8.RuneCountInString(p)
+		if p == "" {
+			continue
+		}
+		state := 0
+		for _, ch := range p {
+			next, ok := a.nodes[state].next[ch]
+			if !ok {
+				next = len(a.nodes)
+				a.nodes = append(a.nodes, acNode{dict: -1})
+				if a.nodes[state].next == nil {
+					a.nodes[state].next = make(map[rune]int)
+				}
+				a.nodes[state].next[ch] = next
+			}
+			state = next
+		}
+		a.nodes[state].out = append(a.nodes[state].out, i)
+	}
+
+	// Breadth-first, so every fail target is finished before it is used
+	queue := make([]int, 0, len(a.nodes))
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for ch, child := range a.nodes[state].next {
+			fail := a.nodes[state].fail
+			for fail != 0 && !a.hasNext(fail, ch) {
+				fail = a.nodes[fail].fail
+			}
+			if next, ok := a.nodes[fail].next[ch]; ok {
+				fail = next
+			} else {
+				fail = 0
+			}
+			a.nodes[child].fail = fail
+			if len(a.nodes[fail].out) > 0 {
+				a.nodes[child].dict = fail
+			} else {
+				a.nodes[child].dict = a.nodes[fail].dict
+			}
+			queue = append(queue, child)
+		}
+	}
+	return a
+}
+
+func (a *acAutomaton) hasNext(state int, ch rune) bool {
+	_, ok := a.nodes[state].next[ch]
+	return ok
+}
+
+// step returns the state reached from state by reading ch.
+func (a *acAutomaton) step(state int, ch rune) int {
+	for {
+		if next, ok := a.nodes[state].next[ch]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = a.nodes[state].fail
+	}
+}
+
+// FindAny finds every occurrence of any of patterns in a single pass over
+// the rope, using an Aho-Corasick automaton, so searching for many
+// patterns costs about as much as searching for one. Matches may overlap,
+// and a pattern listed twice is reported under both indexes. Empty patterns
+// never match.
+//
+// Matches are ordered by start position, then by end position, then by
+// pattern index.
+//
+// Example:
+//
+//	r := rope.New("if x { return }")
+//	for _, m := range r.FindAny([]string{"if", "return"}) {
+//	    fmt.Println(m.Pattern, m.Range.From()) // "0 0", then "1 7"
+//	}
+func (r *Rope) FindAny(patterns []string) []MultiMatch {
+	if r == nil || r.Length() == 0 || len(patterns) == 0 {
+		return nil
+	}
+
+	a := newACAutomaton(patterns)
+	var matches []MultiMatch
+	state, pos := 0, 0
+	forEachLeaf(r.root, func(text string) bool {
+		for _, ch := range text {
+			pos++
+			state = a.step(state, ch)
+			for s := state; s >= 0; s = a.nodes[s].dict {
+				for _, p := range a.nodes[s].out {
+					matches = append(matches, MultiMatch{
+						Pattern: p,
+						Range:   NewRange(pos-a.lengths[p], pos),
+					})
+				}
+			}
+		}
+		return true
+	})
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Range.From() != b.Range.From() {
+			return a.Range.From() < b.Range.From()
+		}
+		if a.Range.To() != b.Range.To() {
+			return a.Range.To() < b.Range.To()
+		}
+		return a.Pattern < b.Pattern
+	})
+	return matches
+}
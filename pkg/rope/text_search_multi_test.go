@@ -0,0 +1,73 @@
+package rope
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveFindAny finds every occurrence of every pattern by brute force,
+// in the order FindAny reports them.
+func naiveFindAny(text string, patterns []string) []MultiMatch {
+	runes := []rune(text)
+	var matches []MultiMatch
+	for i := range runes {
+		for p, pattern := range patterns {
+			n := len([]rune(pattern))
+			if n > 0 && i+n <= len(runes) && string(runes[i:i+n]) == pattern {
+				matches = append(matches, MultiMatch{Pattern: p, Range: NewRange(i, i+n)})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		return a.Range.From() < b.Range.From() ||
+			a.Range.From() == b.Range.From() && a.Range.To() < b.Range.To()
+	})
+	return matches
+}
+
+// TestFindAny_Keywords tests matching Go keywords in source code
+func TestFindAny_Keywords(t *testing.T) {
+	src := "package main\n\nfunc main() {\n\tfor i := range items {\n\t\tif i > 0 {\n\t\t\treturn\n\t\t}\n\t}\n}\n"
+	keywords := []string{"package", "func", "for", "range", "if", "return"}
+
+	matches := New(src).FindAny(keywords)
+	got := make(map[string][]int)
+	for _, m := range matches {
+		text, _ := New(src).Slice(m.Range.From(), m.Range.To())
+		assert.Equal(t, keywords[m.Pattern], text)
+		got[text] = append(got[text], m.Range.From())
+	}
+	assert.Equal(t, map[string][]int{
+		"package": {0},
+		"func":    {14},
+		"for":     {29},
+		"range":   {38},
+		"if":      {54},
+		"return":  {68},
+	}, got)
+
+	// Leaf boundaries do not split matches
+	assert.Equal(t, matches, chunkedRope(src, 3).FindAny(keywords))
+}
+
+// TestFindAny_Overlapping tests patterns that are prefixes and suffixes of each other
+func TestFindAny_Overlapping(t *testing.T) {
+	text := "she sells seashells; he hers his ushers été étés"
+	patterns := []string{"he", "she", "his", "hers", "s", "", "été", "tés", "he"}
+
+	want := naiveFindAny(text, patterns)
+	assert.Equal(t, want, New(text).FindAny(patterns))
+	assert.Equal(t, want, chunkedRope(text, 2).FindAny(patterns))
+}
+
+// TestFindAny_Empty tests inputs that cannot match
+func TestFindAny_Empty(t *testing.T) {
+	assert.Nil(t, Empty().FindAny([]string{"a"}))
+	assert.Nil(t, New("abc").FindAny(nil))
+	assert.Nil(t, New("abc").FindAny([]string{"", "x"}))
+	assert.Len(t, New(strings.Repeat("a", 5)).FindAny([]string{"aa"}), 4)
+}
@@ -0,0 +1,351 @@
+package rope
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGrep_MultipleMatchesPerLine tests that each match on a line is reported
+func TestGrep_MultipleMatchesPerLine(t *testing.T) {
+	r := New("a foo foo\nnone\nfoo")
+
+	results := r.Grep("foo", SearchOptions{})
+	require.Len(t, results, 3)
+
+	assert.Equal(t, GrepResult{LineNumber: 0, Line: "a foo foo", Match: NewRange(2, 5)}, results[0])
+	assert.Equal(t, GrepResult{LineNumber: 0, Line: "a foo foo", Match: NewRange(6, 9)}, results[1])
+	assert.Equal(t, GrepResult{LineNumber: 2, Line: "foo", Match: NewRange(15, 18)}, results[2])
+
+	for _, res := range results {
+		assert.Equal(t, "foo", sliceRange(r, res.Match))
+	}
+}
+
+// TestGrep_CaseInsensitive tests case-insensitive matching
+func TestGrep_CaseInsensitive(t *testing.T) {
+	r := New("Hello\nHELLO world\nhello")
+
+	assert.Len(t, r.Grep("hello", SearchOptions{}), 1)
+
+	results := r.Grep("hello", SearchOptions{CaseInsensitive: true})
+	require.Len(t, results, 3)
+	assert.Equal(t, []int{0, 1, 2}, []int{results[0].LineNumber, results[1].LineNumber, results[2].LineNumber})
+	assert.Equal(t, "HELLO", sliceRange(r, results[1].Match))
+}
+
+// TestGrep_CRLF tests line numbers and line text in CRLF documents
+func TestGrep_CRLF(t *testing.T) {
+	r := New("one\r\ntwo x\r\n\r\nx three\r\n")
+
+	results := r.Grep("x", SearchOptions{})
+	require.Len(t, results, 2)
+
+	assert.Equal(t, 1, results[0].LineNumber)
+	assert.Equal(t, "two x", results[0].Line)
+	assert.Equal(t, 3, results[1].LineNumber)
+	assert.Equal(t, "x three", results[1].Line)
+	assert.Equal(t, "x", sliceRange(r, results[1].Match))
+}
+
+// TestGrep_WholeWord tests rejecting matches inside words
+func TestGrep_WholeWord(t *testing.T) {
+	r := New("cat concat cat_x cat.")
+
+	results := r.Grep("cat", SearchOptions{WholeWord: true})
+	require.Len(t, results, 2)
+	assert.Equal(t, 0, results[0].Match.From())
+	assert.Equal(t, 17, results[1].Match.From())
+}
+
+// TestGrep_Empty tests empty patterns and documents
+func TestGrep_Empty(t *testing.T) {
+	assert.Empty(t, New("abc").Grep("", SearchOptions{}))
+	assert.Empty(t, Empty().Grep("a", SearchOptions{}))
+	assert.Empty(t, New("a\nb").Grep("a\nb", SearchOptions{}))
+	assert.Empty(t, New("x\r\n").Grep("x\r", SearchOptions{}))
+}
+
+// TestGrep_Streaming tests stopping GrepIter early in a large document
+func TestGrep_Streaming(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 5000; i++ {
+		sb.WriteString("line " + strconv.Itoa(i) + " has a match: match\r\n")
+	}
+	r := chunkedRope(sb.String(), 101)
+
+	it := r.GrepIter("match", SearchOptions{})
+	for i := 0; i < 7; i++ {
+		require.True(t, it.Next())
+		res := it.Result()
+		assert.Equal(t, i/2, res.LineNumber)
+		assert.Equal(t, "line "+strconv.Itoa(i/2)+" has a match: match", res.Line)
+		assert.Equal(t, "match", sliceRange(r, res.Match))
+	}
+
+	results := r.Grep("match", SearchOptions{})
+	require.Len(t, results, 10000)
+	assert.Equal(t, 4999, results[9999].LineNumber)
+}
+
+// findAllMatches collects every match of pattern by scanning the whole text.
+func findAllMatches(r *Rope, pattern string, opts SearchOptions) []Range {
+	s := newSearcher(r, pattern, opts)
+	text := []rune(r.String())
+	var matches []Range
+	for i := s.indexFrom(text, 0); i >= 0; i = s.indexFrom(text, i+len(s.pattern)) {
+		matches = append(matches, NewRange(i, i+len(s.pattern)))
+	}
+	return matches
+}
+
+// TestFindIter_MatchesFullScan tests that the lazy matches equal a full scan
+func TestFindIter_MatchesFullScan(t *testing.T) {
+	text := strings.Repeat("Foo föo foo-bar\nfoofoo xfoo ", 300)
+	r := chunkedRope(text, 7)
+
+	cases := []struct {
+		pattern string
+		opts    SearchOptions
+	}{
+		{"foo", SearchOptions{}},
+		{"FOO", SearchOptions{CaseInsensitive: true}},
+		{"foo", SearchOptions{WholeWord: true}},
+		{"o\nf", SearchOptions{}},
+		{"ö", SearchOptions{}},
+		{"missing", SearchOptions{}},
+	}
+	for _, tc := range cases {
+		var got []Range
+		it := r.FindIter(tc.pattern, tc.opts)
+		for it.Next() {
+			got = append(got, it.Match())
+			assert.True(t, strings.EqualFold(tc.pattern, sliceRange(r, it.Match())))
+		}
+		assert.Equal(t, findAllMatches(r, tc.pattern, tc.opts), got, "pattern %q", tc.pattern)
+		assert.False(t, it.Next())
+	}
+}
+
+// TestFindIter_OneAtATime tests stepping through matches as "find next" does
+func TestFindIter_OneAtATime(t *testing.T) {
+	r := New("aaaa b aa")
+
+	it := r.FindIter("aa", SearchOptions{})
+	require.True(t, it.Next())
+	assert.Equal(t, NewRange(0, 2), it.Match())
+	require.True(t, it.Next())
+	assert.Equal(t, NewRange(2, 4), it.Match())
+	require.True(t, it.Next())
+	assert.Equal(t, NewRange(7, 9), it.Match())
+	assert.False(t, it.Next())
+
+	assert.False(t, r.FindIter("", SearchOptions{}).Next())
+	assert.False(t, Empty().FindIter("a", SearchOptions{}).Next())
+	var nilRope *Rope
+	assert.False(t, nilRope.FindIter("a", SearchOptions{}).Next())
+}
+
+// TestFindPrev tests finding the previous occurrence before a position
+func TestFindPrev(t *testing.T) {
+	r := New("foo bar foo baz Foo")
+
+	m, ok := r.FindPrev("foo", r.Length(), SearchOptions{})
+	require.True(t, ok)
+	assert.Equal(t, NewRange(8, 11), m)
+
+	// A match must end at or before the position
+	m, ok = r.FindPrev("foo", 10, SearchOptions{})
+	require.True(t, ok)
+	assert.Equal(t, NewRange(0, 3), m)
+	m, ok = r.FindPrev("foo", 11, SearchOptions{})
+	require.True(t, ok)
+	assert.Equal(t, NewRange(8, 11), m)
+
+	m, ok = r.FindPrev("FOO", 100, SearchOptions{CaseInsensitive: true})
+	require.True(t, ok)
+	assert.Equal(t, NewRange(16, 19), m)
+
+	// No wrapping past the start
+	_, ok = r.FindPrev("foo", 2, SearchOptions{})
+	assert.False(t, ok)
+	_, ok = r.FindPrev("baz", 12, SearchOptions{})
+	assert.False(t, ok)
+	_, ok = r.FindPrev("", 5, SearchOptions{})
+	assert.False(t, ok)
+}
+
+// TestFindPrev_LongDocument tests searching across blocks and leaves
+func TestFindPrev_LongDocument(t *testing.T) {
+	text := "needle" + strings.Repeat("hay stack ", 2000) + "needle hay"
+	r := chunkedRope(text, 13)
+	last := r.Length() - len("needle hay")
+
+	m, ok := r.FindPrev("needle", r.Length(), SearchOptions{})
+	require.True(t, ok)
+	assert.Equal(t, NewRange(last, last+6), m)
+
+	m, ok = r.FindPrev("needle", last+5, SearchOptions{})
+	require.True(t, ok)
+	assert.Equal(t, NewRange(0, 6), m)
+
+	m, ok = r.FindPrev("hay", last, SearchOptions{WholeWord: true})
+	require.True(t, ok)
+	assert.Equal(t, "hay", sliceRange(r, m))
+	assert.Equal(t, last-10, m.From())
+
+	// Every match the forward iterator finds is found backwards from its end
+	it := r.FindIter("stack", SearchOptions{})
+	for it.Next() {
+		m, ok := r.FindPrev("stack", it.Match().To(), SearchOptions{})
+		require.True(t, ok)
+		assert.Equal(t, it.Match(), m)
+	}
+}
+
+// TestFindNextWrap tests finding the next match with wrap-around
+func TestFindNextWrap(t *testing.T) {
+	r := New("foo bar foo baz")
+
+	m, wrapped, found := r.FindNextWrap("foo", 1, SearchOptions{})
+	assert.Equal(t, NewRange(8, 11), m)
+	assert.False(t, wrapped)
+	assert.True(t, found)
+
+	m, wrapped, found = r.FindNextWrap("foo", 8, SearchOptions{})
+	assert.Equal(t, NewRange(8, 11), m)
+	assert.False(t, wrapped)
+	assert.True(t, found)
+
+	// The only match is before from, so the search wraps
+	m, wrapped, found = r.FindNextWrap("bar", 5, SearchOptions{})
+	assert.Equal(t, NewRange(4, 7), m)
+	assert.True(t, wrapped)
+	assert.True(t, found)
+
+	_, wrapped, found = r.FindNextWrap("qux", 5, SearchOptions{})
+	assert.False(t, wrapped)
+	assert.False(t, found)
+	_, _, found = r.FindNextWrap("qux", 0, SearchOptions{})
+	assert.False(t, found)
+}
+
+// TestFindNextWrap_StartsMidDocument tests starting inside a multi-leaf rope
+func TestFindNextWrap_StartsMidDocument(t *testing.T) {
+	text := strings.Repeat("word sword words ", 100)
+	r := chunkedRope(text, 11)
+	opts := SearchOptions{WholeWord: true}
+	all := findAllMatches(r, "word", opts)
+
+	for from := 0; from <= r.Length(); from += 7 {
+		m, wrapped, found := r.FindNextWrap("word", from, opts)
+		require.True(t, found)
+		want := all[0]
+		for _, candidate := range all {
+			if candidate.From() >= from {
+				want = candidate
+				break
+			}
+		}
+		assert.Equal(t, want, m, "from %d", from)
+		assert.Equal(t, want.From() < from, wrapped, "from %d", from)
+	}
+}
+
+// TestReplaceMatch tests replacing single matches and the continue position
+func TestReplaceMatch(t *testing.T) {
+	r := New("foo bar foo")
+	opts := SearchOptions{}
+
+	// Longer replacement
+	m, _, found := r.FindNextWrap("foo", 0, opts)
+	require.True(t, found)
+	r2, next, cs, err := r.ReplaceMatch(m, "fooo")
+	require.NoError(t, err)
+	assert.Equal(t, "fooo bar foo", r2.String())
+	assert.Equal(t, 4, next)
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, r2.String(), applied.String())
+
+	// The replacement contains the pattern but is not found again
+	m, wrapped, found := r2.FindNextWrap("foo", next, opts)
+	require.True(t, found)
+	assert.False(t, wrapped)
+	assert.Equal(t, NewRange(9, 12), m)
+
+	// Shorter replacement, with a multi-byte character
+	r3, next, _, err := r2.ReplaceMatch(m, "é")
+	require.NoError(t, err)
+	assert.Equal(t, "fooo bar é", r3.String())
+	assert.Equal(t, 10, next)
+
+	// A reversed range replaces the same text
+	r4, next, _, err := r.ReplaceMatch(NewRange(7, 4), "")
+	require.NoError(t, err)
+	assert.Equal(t, "foo  foo", r4.String())
+	assert.Equal(t, 4, next)
+
+	_, _, _, err = r.ReplaceMatch(NewRange(8, 20), "x")
+	assert.Error(t, err)
+}
+
+// TestReplaceRegexFunc_IncrementNumbers tests rewriting every match from its text
+func TestReplaceRegexFunc_IncrementNumbers(t *testing.T) {
+	r := chunkedRope("1. café 9\n2. thé 99\n10. fin", 3)
+	re := regexp.MustCompile(`\d+`)
+
+	r2, cs, err := r.ReplaceRegexFunc(re, func(m []string) string {
+		n, _ := strconv.Atoi(m[0])
+		return strconv.Itoa(n + 1)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2. café 10\n3. thé 100\n11. fin", r2.String())
+
+	// The changeset replays the edit and inverts it
+	applied, err := cs.Apply(r)
+	require.NoError(t, err)
+	assert.Equal(t, r2.String(), applied.String())
+	inverse, err := cs.Invert(r)
+	require.NoError(t, err)
+	undone, err := inverse.Apply(r2)
+	require.NoError(t, err)
+	assert.Equal(t, r.String(), undone.String())
+}
+
+// TestReplaceRegexFunc_CaptureGroups tests swapping capture groups
+func TestReplaceRegexFunc_CaptureGroups(t *testing.T) {
+	r := New("a=b, key=value, x=")
+	re := regexp.MustCompile(`(\w+)=(\w*)`)
+
+	r2, _, err := r.ReplaceRegexFunc(re, func(m []string) string {
+		require.Len(t, m, 3)
+		return m[2] + "=" + m[1]
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "b=a, value=key, =x", r2.String())
+}
+
+// TestReplaceRegexFunc_EdgeCases tests no matches, empty matches and a nil regexp
+func TestReplaceRegexFunc_EdgeCases(t *testing.T) {
+	r := New("abc")
+
+	dash := func(m []string) string { return "-" }
+	r2, cs, err := r.ReplaceRegexFunc(regexp.MustCompile(`\d`), dash)
+	require.NoError(t, err)
+	assert.Same(t, r, r2)
+	assert.Equal(t, "retain 3", cs.String())
+
+	// Empty matches behave like the regexp package
+	re := regexp.MustCompile(`x*`)
+	r2, _, err = r.ReplaceRegexFunc(re, dash)
+	require.NoError(t, err)
+	assert.Equal(t, re.ReplaceAllString("abc", "-"), r2.String())
+
+	_, _, err = r.ReplaceRegexFunc(nil, dash)
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package rope
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ========== Display Width ==========
+
+// wideRanges lists the East Asian Wide (W) and Fullwidth (F) blocks that
+// occupy two columns in a monospaced terminal or editor.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK Radicals .. CJK Symbols and Punctuation
+	{0x3041, 0x33FF},   // Hiragana .. CJK Compatibility
+	{0x3400, 0x4DBF},   // CJK Unified Ideographs Extension A
+	{0x4E00, 0x9FFF},   // CJK Unified Ideographs
+	{0xA000, 0xA4CF},   // Yi Syllables and Radicals
+	{0xAC00, 0xD7A3},   // Hangul Syllables
+	{0xF900, 0xFAFF},   // CJK Compatibility Ideographs
+	{0xFE30, 0xFE4F},   // CJK Compatibility Forms
+	{0xFF00, 0xFF60},   // Fullwidth Forms
+	{0xFFE0, 0xFFE6},   // Fullwidth Signs
+	{0x1F300, 0x1F64F}, // Misc Symbols and Pictographs, Emoticons
+	{0x1F680, 0x1F6FF}, // Transport and Map Symbols
+	{0x1F900, 0x1F9FF}, // Supplemental Symbols and Pictographs
+	{0x20000, 0x2FFFD}, // CJK Unified Ideographs Extension B..
+	{0x30000, 0x3FFFD}, // CJK Unified Ideographs Extension G..
+}
+
+// RuneWidth returns the number of columns needed to display ch.
+// Control characters, combining marks and other zero-width characters
+// occupy 0 columns, East Asian wide and fullwidth characters occupy 2,
+// and everything else occupies 1.
+//
+// Tabs are reported as zero width; callers that render tabs expand them
+// to tab stops themselves.
+func RuneWidth(ch rune) int {
+	if ch < 0x20 || (ch >= 0x7F && ch < 0xA0) {
+		return 0
+	}
+	if ch < 0x1100 && !unicode.In(ch, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 1
+	}
+	if unicode.In(ch, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, wr := range wideRanges {
+		if ch < wr.lo {
+			break
+		}
+		if ch <= wr.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// StringWidth returns the display width of s as the sum of RuneWidth
+// over its runes.
+func StringWidth(s string) int {
+	width := 0
+	for _, ch := range s {
+		width += RuneWidth(ch)
+	}
+	return width
+}
+
+// advanceWidth returns the display column reached after writing s starting
+// at column col, expanding tabs to the next multiple of tabWidth.
+func advanceWidth(s string, col, tabWidth int) int {
+	for _, ch := range s {
+		if ch == '\t' {
+			col += tabWidth - col%tabWidth
+		} else {
+			col += RuneWidth(ch)
+		}
+	}
+	return col
+}
+
+// LineWidth returns the display width of line n, without its line ending,
+// measured like WrapLines: wide CJK characters count as 2 columns,
+// combining marks as 0, and tabs expand to the next multiple of tabWidth
+// (zero or less means 4). Returns 0 if n is not a valid line number.
+//
+// Example:
+//
+//	r := rope.New("a\tb\n世界")
+//	fmt.Println(r.LineWidth(0, 4), r.LineWidth(1, 4)) // 5 4
+func (r *Rope) LineWidth(n, tabWidth int) int {
+	if n < 0 || n >= r.LineCount() {
+		return 0
+	}
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+
+	start, contentEnd, _ := r.lineSpan(n)
+	text, err := r.Slice(start, contentEnd)
+	if err != nil {
+		return 0
+	}
+	return advanceWidth(text, 0, tabWidth)
+}
+
+// MaxLineWidth returns the display width of the widest line, measured as
+// LineWidth does, and that line's number, for sizing a horizontal
+// scrollbar. Among equally wide lines the first wins. An empty rope gives
+// 0, 0. With the default LineEndingLF mode the rope is scanned in a single
+// pass.
+//
+// Example:
+//
+//	r := rope.New("ab\n\tc\nd")
+//	width, line := r.MaxLineWidth(4)
+//	fmt.Println(width, line) // 5 1
+func (r *Rope) MaxLineWidth(tabWidth int) (width, lineNum int) {
+	if r == nil || r.Length() == 0 {
+		return 0, 0
+	}
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+
+	if r.lineEnding != LineEndingLF {
+		for n := 0; n < r.LineCount(); n++ {
+			if w := r.LineWidth(n, tabWidth); w > width {
+				width, lineNum = w, n
+			}
+		}
+		return width, lineNum
+	}
+
+	line, col := 0, 0
+	forEachLeaf(r.root, func(text string) bool {
+		for {
+			i := strings.IndexByte(text, '\n')
+			if i < 0 {
+				col = advanceWidth(text, col, tabWidth)
+				return true
+			}
+			// A '\r' before the '\n' is a control character of width 0
+			if col = advanceWidth(text[:i], col, tabWidth); col > width {
+				width, lineNum = col, line
+			}
+			line, col = line+1, 0
+			text = text[i+1:]
+		}
+	})
+	if col > width {
+		width, lineNum = col, line
+	}
+	return width, lineNum
+}
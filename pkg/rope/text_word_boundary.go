@@ -1,9 +1,5 @@
 package rope
 
-import (
-	"unicode"
-)
-
 // WordBoundary provides word boundary detection for text operations.
 // This is useful for text editing features like word selection, navigation,
 // and word-by-word processing.
@@ -24,7 +20,8 @@ func NewWordBoundary(rope *Rope) *WordBoundary {
 }
 
 // IsWordChar returns true if the rune is a word character.
-// Word characters are: letters, digits, and underscore (as in \w in regex).
+// By default word characters are letters, digits, and underscore (as in \w
+// in regex); a rope created with WithCharClassifier can change this.
 //
 // Example:
 //
@@ -36,11 +33,12 @@ func NewWordBoundary(rope *Rope) *WordBoundary {
 //	    }
 //	}
 func (wb *WordBoundary) IsWordChar(r rune) bool {
-	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+	return wb.rope.charClass(r) == CharClassWord
 }
 
 // IsWhitespace returns true if the rune is whitespace.
-// This includes spaces, tabs, newlines, and other Unicode whitespace.
+// This includes spaces, tabs, newlines, and other Unicode whitespace,
+// unless the rope's character classifier says otherwise.
 //
 // Example:
 //
@@ -52,7 +50,8 @@ func (wb *WordBoundary) IsWordChar(r rune) bool {
 //	    }
 //	}
 func (wb *WordBoundary) IsWhitespace(r rune) bool {
-	return unicode.IsSpace(r)
+	class := wb.rope.charClass(r)
+	return class == CharClassWhitespace || class == CharClassNewline
 }
 
 // PrevWordStart finds the start of the word before the given position.
@@ -346,6 +345,65 @@ func (wb *WordBoundary) MoveToWordBoundary(pos int, assoc Assoc) int {
 	}
 }
 
+// NextWordBoundary returns the start of the next run of word or punctuation
+// characters after pos, like Vim's "w" motion. Runs are split where the
+// character class (see CharClassAt) changes, and whitespace between runs is
+// skipped. Returns the rope length if there is no further run.
+//
+// Example:
+//
+//	wb := rope.NewWordBoundary(rope.New("foo.bar baz"))
+//	wb.NextWordBoundary(0) // 3, the "."
+//	wb.NextWordBoundary(4) // 8, "baz"
+func (wb *WordBoundary) NextWordBoundary(pos int) int {
+	length := wb.rope.Length()
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= length {
+		return length
+	}
+
+	// Skip the rest of the current run, then any whitespace
+	class := wb.rope.CharClassAt(pos)
+	i := pos + 1
+	for i < length && wb.rope.CharClassAt(i) == class {
+		i++
+	}
+	for i < length && isBlankClass(wb.rope.CharClassAt(i)) {
+		i++
+	}
+	return i
+}
+
+// PrevWordBoundary returns the start of the run of word or punctuation
+// characters before pos, like Vim's "b" motion. It is the backward
+// counterpart of NextWordBoundary. Returns 0 if there is no earlier run.
+func (wb *WordBoundary) PrevWordBoundary(pos int) int {
+	if pos > wb.rope.Length() {
+		pos = wb.rope.Length()
+	}
+
+	// Skip whitespace backwards, then the run before it
+	i := pos
+	for i > 0 && isBlankClass(wb.rope.CharClassAt(i-1)) {
+		i--
+	}
+	if i == 0 {
+		return 0
+	}
+	class := wb.rope.CharClassAt(i - 1)
+	for i > 0 && wb.rope.CharClassAt(i-1) == class {
+		i--
+	}
+	return i
+}
+
+// isBlankClass reports whether a character class is whitespace or a newline.
+func isBlankClass(class CharClass) bool {
+	return class == CharClassWhitespace || class == CharClassNewline
+}
+
 // BigWordStart finds the start of the "big word" before the given position.
 // Big words are separated by whitespace only.
 func (wb *WordBoundary) BigWordStart(pos int) int {
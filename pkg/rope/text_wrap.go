@@ -0,0 +1,148 @@
+package rope
+
+// ========== Soft Wrapping ==========
+
+// WrapMode selects where a line may be broken when soft wrapping.
+type WrapMode int
+
+const (
+	// WrapAtWhitespace breaks after whitespace, falling back to a hard break
+	// inside words that are wider than the viewport.
+	WrapAtWhitespace WrapMode = iota
+
+	// WrapAnywhere breaks at any character once the viewport is full.
+	WrapAnywhere
+)
+
+// WrapOptions configures WrapLines.
+type WrapOptions struct {
+	// Mode selects the break opportunities.
+	Mode WrapMode
+
+	// TabWidth is the distance between tab stops. Zero means 4.
+	TabWidth int
+}
+
+// WrappedLine is one visual line produced by soft wrapping.
+type WrappedLine struct {
+	Line  int // Source line number (0-indexed)
+	Start int // Character position where the segment starts
+	End   int // Character position where the segment ends (exclusive)
+	Width int // Display width of the segment
+}
+
+// WrapLines soft-wraps every line of the rope to fit a viewport of the given
+// display width. Widths are measured in columns: wide CJK characters count
+// as 2, combining marks as 0 and tabs expand to the next tab stop.
+//
+// Lines are split at '\n' only, as in the default LineEndingLF mode. The
+// '\n' is not part of any segment, but a '\r' before it is: it stays at the
+// end of the line's last segment with zero width. Concatenating the
+// segments of a line therefore reproduces Line(n) in LineEndingLF mode.
+// Each source line produces at least one WrappedLine (empty lines produce a
+// zero-width segment). Whitespace at a break stays at the end of the
+// earlier segment and may hang past the viewport edge, as in most editors.
+//
+// A width <= 0 disables wrapping and yields one segment per line.
+//
+// Example:
+//
+//	r := rope.New("the quick brown fox")
+//	for _, wl := range r.WrapLines(10, rope.WrapOptions{}) {
+//	    s, _ := r.Slice(wl.Start, wl.End)
+//	    fmt.Printf("%q\n", s) // "the quick ", "brown fox"
+//	}
+func (r *Rope) WrapLines(width int, opts WrapOptions) []WrappedLine {
+	if r == nil || r.Length() == 0 {
+		return nil
+	}
+
+	tabWidth := opts.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+
+	w := &wrapper{width: width, mode: opts.Mode, tabWidth: tabWidth}
+
+	lineNum := 0
+	lineStart := 0
+	pos := 0
+	var line []rune
+
+	it := r.NewIterator()
+	for it.Next() {
+		ch := it.Current()
+		if ch == '\n' {
+			w.wrapLine(lineNum, lineStart, line)
+			line = line[:0]
+			lineNum++
+			lineStart = pos + 1
+		} else {
+			line = append(line, ch)
+		}
+		pos++
+	}
+
+	// The last line only counts if it has content (matches LineCount)
+	if pos > lineStart {
+		w.wrapLine(lineNum, lineStart, line)
+	}
+
+	return w.out
+}
+
+// wrapper accumulates wrapped segments across lines.
+type wrapper struct {
+	width    int
+	mode     WrapMode
+	tabWidth int
+	out      []WrappedLine
+}
+
+// wrapLine wraps a single source line starting at character position start.
+func (w *wrapper) wrapLine(lineNum, start int, line []rune) {
+	emit := func(from, to, width int) {
+		w.out = append(w.out, WrappedLine{Line: lineNum, Start: start + from, End: start + to, Width: width})
+	}
+
+	segStart := 0
+	col := 0
+	lastBreak := -1 // Index just after the most recent whitespace run
+	colAtBreak := 0
+
+	for i, ch := range line {
+		cw := RuneWidth(ch)
+		if ch == '\t' {
+			cw = w.tabWidth - col%w.tabWidth
+		}
+
+		if ch == ' ' || ch == '\t' {
+			// Whitespace never forces a break; it hangs at the segment end
+			col += cw
+			if w.mode == WrapAtWhitespace {
+				lastBreak = i + 1
+				colAtBreak = col
+			}
+			continue
+		}
+
+		for w.width > 0 && col > 0 && cw > 0 && col+cw > w.width {
+			if lastBreak > segStart {
+				// Break after the last whitespace run
+				emit(segStart, lastBreak, colAtBreak)
+				col -= colAtBreak
+				segStart = lastBreak
+			} else {
+				// Hard break inside an overlong word
+				emit(segStart, i, col)
+				col = 0
+				segStart = i
+			}
+			lastBreak = -1
+		}
+
+		col += cw
+	}
+
+	emit(segStart, len(line), col)
+}
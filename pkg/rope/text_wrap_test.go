@@ -0,0 +1,148 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentsByLine slices each wrapped segment and groups them by source line.
+func segmentsByLine(t *testing.T, r *Rope, wrapped []WrappedLine) map[int][]string {
+	t.Helper()
+	result := make(map[int][]string)
+	for _, wl := range wrapped {
+		s, err := r.Slice(wl.Start, wl.End)
+		require.NoError(t, err)
+		result[wl.Line] = append(result[wl.Line], s)
+	}
+	return result
+}
+
+// TestWrapLines_Paragraph tests word wrapping a paragraph with CJK text at width 20
+func TestWrapLines_Paragraph(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog\n" +
+		"中文字符每个占两列宽度需要正确换行\n" +
+		"\n" +
+		"mixed 中文 and english words here"
+	r := New(text)
+
+	wrapped := r.WrapLines(20, WrapOptions{})
+	segments := segmentsByLine(t, r, wrapped)
+
+	assert.Equal(t, []string{"The quick brown fox ", "jumps over the lazy ", "dog"}, segments[0])
+	assert.Equal(t, []string{"中文字符每个占两列宽", "度需要正确换行"}, segments[1])
+	assert.Equal(t, []string{""}, segments[2])
+	assert.Equal(t, []string{"mixed 中文 and ", "english words here"}, segments[3])
+
+	// Concatenating the segments reproduces each source line
+	for lineNum := 0; lineNum < r.LineCount(); lineNum++ {
+		line, err := r.Line(lineNum)
+		require.NoError(t, err)
+		assert.Equal(t, line, strings.Join(segments[lineNum], ""))
+	}
+
+	// Visible widths fit the viewport (trailing whitespace may hang)
+	for _, wl := range wrapped {
+		s, _ := r.Slice(wl.Start, wl.End)
+		assert.LessOrEqual(t, StringWidth(strings.TrimRight(s, " ")), 20)
+	}
+	assert.Equal(t, 20, wrapped[3].Width)
+	assert.Equal(t, 14, wrapped[4].Width)
+}
+
+// TestWrapLines_HardBreak tests breaking words longer than the viewport
+func TestWrapLines_HardBreak(t *testing.T) {
+	r := New("abcdefghijkl xyz")
+
+	segments := segmentsByLine(t, r, r.WrapLines(5, WrapOptions{}))
+	assert.Equal(t, []string{"abcde", "fghij", "kl ", "xyz"}, segments[0])
+}
+
+// TestWrapLines_Anywhere tests breaking at any character
+func TestWrapLines_Anywhere(t *testing.T) {
+	r := New("ab cd ef")
+
+	segments := segmentsByLine(t, r, r.WrapLines(4, WrapOptions{Mode: WrapAnywhere}))
+	assert.Equal(t, []string{"ab c", "d ef"}, segments[0])
+}
+
+// TestWrapLines_CombiningAndTabs tests zero-width marks and tab stops
+func TestWrapLines_CombiningAndTabs(t *testing.T) {
+	// Combining acute accents take no columns and never start a segment
+	r := New("éééé")
+	wrapped := r.WrapLines(2, WrapOptions{})
+	segments := segmentsByLine(t, r, wrapped)
+	assert.Equal(t, []string{"éé", "éé"}, segments[0])
+	assert.Equal(t, 2, wrapped[0].Width)
+
+	r = New("\tab")
+	wrapped = r.WrapLines(0, WrapOptions{TabWidth: 8})
+	require.Len(t, wrapped, 1)
+	assert.Equal(t, 10, wrapped[0].Width)
+}
+
+// TestRuneWidth tests display widths of common characters
+func TestRuneWidth(t *testing.T) {
+	assert.Equal(t, 1, RuneWidth('a'))
+	assert.Equal(t, 2, RuneWidth('中'))
+	assert.Equal(t, 2, RuneWidth('한'))
+	assert.Equal(t, 0, RuneWidth('́'))
+	assert.Equal(t, 0, RuneWidth('‍'))
+	assert.Equal(t, 0, RuneWidth('\n'))
+	assert.Equal(t, 4, StringWidth("中文"))
+}
+
+// TestMaxLineWidth tests finding the widest line when it holds tabs and CJK
+func TestMaxLineWidth(t *testing.T) {
+	text := "short\r\n\t世界 x\r\nabcdefghi\r\n"
+
+	for _, r := range []*Rope{New(text), chunkedRope(text, 3), New(text).WithLineEnding(LineEndingCRLF)} {
+		width, line := r.MaxLineWidth(4)
+		assert.Equal(t, 10, width)
+		assert.Equal(t, 1, line)
+
+		width, line = r.MaxLineWidth(8)
+		assert.Equal(t, 14, width)
+		assert.Equal(t, 1, line)
+	}
+
+	// Without the tab the plain line is wider
+	width, line := New("世界 x\nabcdefghi").MaxLineWidth(4)
+	assert.Equal(t, 9, width)
+	assert.Equal(t, 1, line)
+
+	width, line = Empty().MaxLineWidth(4)
+	assert.Equal(t, 0, width)
+	assert.Equal(t, 0, line)
+}
+
+// TestLineWidth tests the width of single lines
+func TestLineWidth(t *testing.T) {
+	r := New("short\r\n\t世界 x\nab\tc")
+
+	assert.Equal(t, 5, r.LineWidth(0, 4))
+	assert.Equal(t, 10, r.LineWidth(1, 4))
+	assert.Equal(t, 10, r.LineWidth(1, 0))
+	assert.Equal(t, 5, r.LineWidth(2, 4))
+	assert.Equal(t, 0, r.LineWidth(3, 4))
+	assert.Equal(t, 0, r.LineWidth(-1, 4))
+}
+
+// TestWrapLines_CRLF tests that a '\r' before '\n' stays in the last segment
+func TestWrapLines_CRLF(t *testing.T) {
+	r := New("abc def\r\nxy\r\n")
+
+	wrapped := r.WrapLines(4, WrapOptions{})
+	segments := segmentsByLine(t, r, wrapped)
+	assert.Equal(t, []string{"abc ", "def\r"}, segments[0])
+	assert.Equal(t, []string{"xy\r"}, segments[1])
+	assert.Equal(t, 3, wrapped[1].Width)
+
+	for lineNum := 0; lineNum < r.LineCount(); lineNum++ {
+		line, err := r.Line(lineNum)
+		require.NoError(t, err)
+		assert.Equal(t, line, strings.Join(segments[lineNum], ""))
+	}
+}
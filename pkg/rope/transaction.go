@@ -0,0 +1,123 @@
+package rope
+
+import "unicode/utf8"
+
+// Transaction pairs a ChangeSet with the selection that results from it.
+//
+// Transactions are the unit recorded by History: undoing or redoing a
+// revision yields a Transaction whose changes are applied to the document
+// and whose selection (if any) replaces the current one.
+type Transaction struct {
+	changes   *ChangeSet
+	selection *Selection
+}
+
+// NewTransaction creates a transaction for the given changes without a selection.
+func NewTransaction(changes *ChangeSet) *Transaction {
+	return &Transaction{changes: changes}
+}
+
+// Changes returns the ChangeSet of the transaction.
+func (t *Transaction) Changes() *ChangeSet {
+	return t.changes
+}
+
+// Selection returns the selection after the transaction, or nil if the
+// transaction does not carry one.
+func (t *Transaction) Selection() *Selection {
+	return t.selection
+}
+
+// WithSelection returns a copy of the transaction carrying the given selection.
+func (t *Transaction) WithSelection(sel *Selection) *Transaction {
+	return &Transaction{
+		changes:   t.changes,
+		selection: sel,
+	}
+}
+
+// Apply applies the transaction's changes to a rope.
+func (t *Transaction) Apply(r *Rope) (*Rope, error) {
+	return t.changes.Apply(r)
+}
+
+// Invert returns a transaction that undoes this one. original must be the
+// document the transaction was applied to. The inverse carries no selection.
+func (t *Transaction) Invert(original *Rope) (*Transaction, error) {
+	inverted, err := t.changes.Invert(original)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransaction(inverted), nil
+}
+
+// SpliceTx returns a transaction replacing the characters [from, to) of doc
+// with text and leaving the cursor after it. The deleted text is captured
+// in the changeset, so the transaction can be undone with
+// InvertSelfContained once doc is gone.
+func SpliceTx(doc *Rope, from, to int, text string) (*Transaction, error) {
+	deleted, err := doc.Slice(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := NewChangeSet(doc.Length())
+	if from > 0 {
+		cs.Retain(from)
+	}
+	if deleted != "" {
+		cs.DeleteText(deleted)
+	}
+	if text != "" {
+		cs.Insert(text)
+	}
+	cs.finalize()
+	cursor := from + utf8.RuneCountInString(text)
+	return NewTransaction(cs).WithSelection(NewSelection(Point(cursor))), nil
+}
+
+// Commit performs the usual editor step for an edit in one call: it
+// applies cs to r, maps sel through cs and records the edit, carrying the
+// new selection, in h so it can be undone. Cursors at an insertion point
+// end up after the inserted text. sel and h may be nil.
+// Returns the edited rope and the mapped selection.
+//
+// Example:
+//
+//	doc, sel, err = rope.Commit(doc, cs, sel, history)
+func Commit(r *Rope, cs *ChangeSet, sel *Selection, h *History) (*Rope, *Selection, error) {
+	result, err := cs.Apply(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mapped *Selection
+	if sel != nil {
+		mapped = mapSelection(sel, cs)
+	}
+	if h != nil {
+		tx := NewTransaction(cs)
+		if mapped != nil {
+			tx = tx.WithSelection(mapped)
+		}
+		if err := h.CommitRevision(tx, r); err != nil {
+			return nil, nil, err
+		}
+	}
+	return result, mapped, nil
+}
+
+// mapSelection maps every range of sel through cs, keeping the primary
+// range. Cursors at an insertion point move after the inserted text and
+// stay collapsed.
+func mapSelection(sel *Selection, cs *ChangeSet) *Selection {
+	ranges := make([]Range, sel.Len())
+	for i, rng := range sel.Iter() {
+		if rng.IsCursor() {
+			ranges[i] = Point(cs.MapPosition(rng.Head, AssocAfter))
+		} else {
+			ranges[i] = rng.Map(cs, AssocAfter)
+		}
+	}
+	return NewSelectionWithPrimary(ranges, sel.PrimaryIndex())
+}